@@ -2,6 +2,8 @@
 // This source code is licensed under the BSD-style license found in the
 // LICENSE file in the root directory of this source tree.
 
+#include <stdlib.h>
+#include <algorithm>
 #include <rocksdb/utilities/backupable_db.h>
 
 using namespace rocksdb;
@@ -12,6 +14,60 @@ DEFINE_C_WRAP_CONSTRUCTOR(BackupableDBOptions)
 DEFINE_C_WRAP_DESTRUCTOR(BackupableDBOptions)
 DEFINE_C_WRAP_CONSTRUCTOR_ARGS(BackupableDBOptions, String)
 
+// If share_table_files == true, backup will assume that table files with
+// same name have the same contents. This enables incremental backups and
+// avoids unnecessary data copies.
+// If share_table_files == false, each backup will be on its own and will
+// not share any data with other backups.
+// default: true
+DEFINE_C_WRAP_GETTER(BackupableDBOptions, share_table_files, bool)
+DEFINE_C_WRAP_SETTER(BackupableDBOptions, share_table_files, bool)
+
+// If sync == true, we can guarantee you'll get consistent backup even
+// on a machine crash/reboot. Backup process is slower with sync enabled.
+// If sync == false, we don't guarantee anything on machine reboot. However,
+// chances are some of the backups are consistent.
+// Default: true
+DEFINE_C_WRAP_GETTER(BackupableDBOptions, sync, bool)
+DEFINE_C_WRAP_SETTER(BackupableDBOptions, sync, bool)
+
+// If true, it will delete whatever backups there are already
+// Default: false
+DEFINE_C_WRAP_GETTER(BackupableDBOptions, destroy_old_data, bool)
+DEFINE_C_WRAP_SETTER(BackupableDBOptions, destroy_old_data, bool)
+
+// If false, we won't backup log files. This option can be useful for backing
+// up in-memory databases where log file are persisted, but table files are in
+// memory.
+// Default: true
+DEFINE_C_WRAP_GETTER(BackupableDBOptions, backup_log_files, bool)
+DEFINE_C_WRAP_SETTER(BackupableDBOptions, backup_log_files, bool)
+
+// Max bytes that can be transferred in a second during backup.
+// If 0, go as fast as you can
+// Default: 0
+DEFINE_C_WRAP_GETTER(BackupableDBOptions, backup_rate_limit, uint64_t)
+DEFINE_C_WRAP_SETTER(BackupableDBOptions, backup_rate_limit, uint64_t)
+
+// Max bytes that can be transferred in a second during restore.
+// If 0, go as fast as you can
+// Default: 0
+DEFINE_C_WRAP_GETTER(BackupableDBOptions, restore_rate_limit, uint64_t)
+DEFINE_C_WRAP_SETTER(BackupableDBOptions, restore_rate_limit, uint64_t)
+
+// Only used if share_table_files is set to true. If true, will consider that
+// backups can come from different databases, hence a sst is not uniquely
+// identifed by its name, but by the triple (file name, crc32, file length)
+// Default: false
+DEFINE_C_WRAP_GETTER(BackupableDBOptions, share_files_with_checksum, bool)
+DEFINE_C_WRAP_SETTER(BackupableDBOptions, share_files_with_checksum, bool)
+
+// Up to this many background threads will copy files for CreateNewBackup()
+// and RestoreDBFromBackup()
+// Default: 1
+DEFINE_C_WRAP_GETTER(BackupableDBOptions, max_background_operations, int)
+DEFINE_C_WRAP_SETTER(BackupableDBOptions, max_background_operations, int)
+
 DEFINE_C_WRAP_CONSTRUCTOR(RestoreOptions)
 DEFINE_C_WRAP_CONSTRUCTOR_DEFAULT(RestoreOptions)
 DEFINE_C_WRAP_DESTRUCTOR(RestoreOptions)
@@ -23,6 +79,13 @@ DEFINE_C_WRAP_DESTRUCTOR(RestoreOptions)
 // Default: false
 DEFINE_C_WRAP_SETTER(RestoreOptions, keep_log_files, bool)
 
+DEFINE_C_WRAP_CONSTRUCTOR(BackupInfo)
+DEFINE_C_WRAP_DESTRUCTOR(BackupInfo)
+DEFINE_C_WRAP_DESTRUCTOR_ARRAY(BackupInfo)
+DEFINE_C_WRAP_GETTER(BackupInfo, backup_id, BackupID)
+DEFINE_C_WRAP_GETTER(BackupInfo, timestamp, int64_t)
+DEFINE_C_WRAP_GETTER(BackupInfo, size, uint64_t)
+DEFINE_C_WRAP_GETTER(BackupInfo, number_files, uint32_t)
 
 DEFINE_C_WRAP_CONSTRUCTOR(BackupEngine)
 DEFINE_C_WRAP_DESTRUCTOR(BackupEngine)
@@ -81,6 +144,154 @@ Status_t BackupEngineRestoreDBFromLatestBackup(BackupEngine_t* backup_engine_ptr
     return NewStatusTCopy(&stat);
 }
 
+// Deletes old backups, keeping latest num_backups_to_keep alive
+Status_t BackupEnginePurgeOldBackups(BackupEngine_t* backup_engine_ptr, uint32_t num_backups_to_keep)
+{
+    Status ret = ((backup_engine_ptr && GET_REP(backup_engine_ptr, BackupEngine)) ?
+                  GET_REP(backup_engine_ptr, BackupEngine)->PurgeOldBackups(num_backups_to_keep) :
+                  invalid_status);
+    return NewStatusTCopy(&ret);
+}
+
+// Deletes a specific backup
+Status_t BackupEngineDeleteBackup(BackupEngine_t* backup_engine_ptr, BackupID backup_id)
+{
+    Status ret = ((backup_engine_ptr && GET_REP(backup_engine_ptr, BackupEngine)) ?
+                  GET_REP(backup_engine_ptr, BackupEngine)->DeleteBackup(backup_id) :
+                  invalid_status);
+    return NewStatusTCopy(&ret);
+}
+
+// Returns info about backups in backup_info
+void BackupEngineGetBackupInfo(BackupEngine_t* backup_engine_ptr, BackupInfo_t** backup_info, int* n)
+{
+    *n = 0;
+    if (backup_engine_ptr && GET_REP(backup_engine_ptr, BackupEngine))
+    {
+        std::vector<BackupInfo> backup_info_vec;
+        GET_REP(backup_engine_ptr, BackupEngine)->GetBackupInfo(&backup_info_vec);
+        *n = backup_info_vec.size();
+        *backup_info = new BackupInfo_t[*n];
+        for (int j = 0; j < *n; j++)
+        {
+            (*backup_info)[j].rep = new BackupInfo(backup_info_vec[j]);
+        }
+    }
+}
+
+// Returns info about corrupt backups in corrupt_backups
+void BackupEngineGetCorruptedBackups(BackupEngine_t* backup_engine_ptr, BackupID** corrupt_backup_ids, int* n)
+{
+    *n = 0;
+    if (backup_engine_ptr && GET_REP(backup_engine_ptr, BackupEngine))
+    {
+        std::vector<BackupID> corrupt_backup_ids_vec;
+        GET_REP(backup_engine_ptr, BackupEngine)->GetCorruptedBackups(&corrupt_backup_ids_vec);
+        *n = corrupt_backup_ids_vec.size();
+        *corrupt_backup_ids = (BackupID*)malloc(sizeof(BackupID) * (*n));
+        std::copy(corrupt_backup_ids_vec.begin(), corrupt_backup_ids_vec.end(), *corrupt_backup_ids);
+    }
+}
+
+// Checks that each file exists and that the size of the file matches our
+// expectations. It does not check file checksum.
+Status_t BackupEngineVerifyBackup(BackupEngine_t* backup_engine_ptr, BackupID backup_id)
+{
+    Status ret = ((backup_engine_ptr && GET_REP(backup_engine_ptr, BackupEngine)) ?
+                  GET_REP(backup_engine_ptr, BackupEngine)->VerifyBackup(backup_id) :
+                  invalid_status);
+    return NewStatusTCopy(&ret);
+}
+
+// Will delete all the files we don't need anymore
+// It will do the full scan of the files/ directory and delete all the
+// files that are not referenced.
+Status_t BackupEngineGarbageCollect(BackupEngine_t* backup_engine_ptr)
+{
+    Status ret = ((backup_engine_ptr && GET_REP(backup_engine_ptr, BackupEngine)) ?
+                  GET_REP(backup_engine_ptr, BackupEngine)->GarbageCollect() :
+                  invalid_status);
+    return NewStatusTCopy(&ret);
+}
+
+DEFINE_C_WRAP_CONSTRUCTOR(BackupEngineReadOnly)
+DEFINE_C_WRAP_DESTRUCTOR(BackupEngineReadOnly)
+
+// Create a new read only backup engine from db_env and options.
+Status_t BackupEngineReadOnlyOpen(Env_t* db_env, const BackupableDBOptions_t* options, BackupEngineReadOnly_t* backup_engine_ptr)
+{
+    assert(db_env != NULL);
+    assert(options != NULL);
+    assert(backup_engine_ptr != NULL);
+    BackupEngineReadOnly** backup_engine = GET_REP_ADDR(backup_engine_ptr, BackupEngineReadOnly);
+    Status stat = BackupEngineReadOnly::Open(GET_REP(db_env, Env), GET_REP_REF(options, BackupableDBOptions), backup_engine);
+    return NewStatusTCopy(&stat);
+}
+
+// Returns info about backups in backup_info
+void BackupEngineReadOnlyGetBackupInfo(BackupEngineReadOnly_t* backup_engine_ptr, BackupInfo_t** backup_info, int* n)
+{
+    *n = 0;
+    if (backup_engine_ptr && GET_REP(backup_engine_ptr, BackupEngineReadOnly))
+    {
+        std::vector<BackupInfo> backup_info_vec;
+        GET_REP(backup_engine_ptr, BackupEngineReadOnly)->GetBackupInfo(&backup_info_vec);
+        *n = backup_info_vec.size();
+        *backup_info = new BackupInfo_t[*n];
+        for (int j = 0; j < *n; j++)
+        {
+            (*backup_info)[j].rep = new BackupInfo(backup_info_vec[j]);
+        }
+    }
+}
+
+// Returns info about corrupt backups in corrupt_backups
+void BackupEngineReadOnlyGetCorruptedBackups(BackupEngineReadOnly_t* backup_engine_ptr, BackupID** corrupt_backup_ids, int* n)
+{
+    *n = 0;
+    if (backup_engine_ptr && GET_REP(backup_engine_ptr, BackupEngineReadOnly))
+    {
+        std::vector<BackupID> corrupt_backup_ids_vec;
+        GET_REP(backup_engine_ptr, BackupEngineReadOnly)->GetCorruptedBackups(&corrupt_backup_ids_vec);
+        *n = corrupt_backup_ids_vec.size();
+        *corrupt_backup_ids = (BackupID*)malloc(sizeof(BackupID) * (*n));
+        std::copy(corrupt_backup_ids_vec.begin(), corrupt_backup_ids_vec.end(), *corrupt_backup_ids);
+    }
+}
+
+// Restoring DB from backup is NOT safe when there is another BackupEngine
+// running that might call DeleteBackup() or PurgeOldBackups(). It is caller's
+// responsibility to synchronize the operation, i.e. don't delete the backup
+// when you're restoring from it
+Status_t BackupEngineReadOnlyRestoreDBFromBackup(BackupEngineReadOnly_t* backup_engine_ptr, BackupID backup_id, String_t* db_dir, String_t* wal_dir, RestoreOptions_t* restore_options)
+{
+    assert(db_dir != NULL);
+    assert(GET_REP(db_dir, String) != NULL);
+    assert(wal_dir != NULL);
+    assert(GET_REP(wal_dir, String) != NULL);
+    assert(backup_engine_ptr != NULL);
+    assert(GET_REP(backup_engine_ptr, BackupEngineReadOnly) != NULL);
+    Status stat = GET_REP(backup_engine_ptr, BackupEngineReadOnly)->RestoreDBFromBackup(backup_id,
+                                                                                        GET_REP_REF(db_dir, String),
+                                                                                        GET_REP_REF(wal_dir, String),
+                                                                                        NULL == restore_options ? RestoreOptions() : GET_REP_REF(restore_options, RestoreOptions));
+    return NewStatusTCopy(&stat);
+}
+
+Status_t BackupEngineReadOnlyRestoreDBFromLatestBackup(BackupEngineReadOnly_t* backup_engine_ptr, String_t* db_dir, String_t* wal_dir, RestoreOptions_t* restore_options)
+{
+    assert(db_dir != NULL);
+    assert(GET_REP(db_dir, String) != NULL);
+    assert(wal_dir != NULL);
+    assert(GET_REP(wal_dir, String) != NULL);
+    assert(backup_engine_ptr != NULL);
+    assert(GET_REP(backup_engine_ptr, BackupEngineReadOnly) != NULL);
+    Status stat = GET_REP(backup_engine_ptr, BackupEngineReadOnly)->RestoreDBFromLatestBackup(GET_REP_REF(db_dir, String),
+                                                                                              GET_REP_REF(wal_dir, String),
+                                                                                              NULL == restore_options ? RestoreOptions() : GET_REP_REF(restore_options, RestoreOptions));
+    return NewStatusTCopy(&stat);
+}
+
 DEFINE_C_WRAP_CONSTRUCTOR(BackupableDB)
 DEFINE_C_WRAP_DESTRUCTOR(BackupableDB)
 
@@ -5,12 +5,14 @@
 package rocksdb
 
 /*
+#include <stdlib.h>
 #include "backupableDB.h"
 */
 import "C"
 
 import (
 	"runtime"
+	"unsafe"
 )
 
 // Wrap go BackupableDBOptions
@@ -50,6 +52,116 @@ func NewBackupableDBOptions(dpath *string) *BackupableDBOptions {
 	return cbdbop.toBackupableDBOptions()
 }
 
+// If share_table_files == true, backup will assume that table files with
+// same name have the same contents. This enables incremental backups and
+// avoids unnecessary data copies.
+// If share_table_files == false, each backup will be on its own and will
+// not share any data with other backups.
+// default: true
+func (bdbop *BackupableDBOptions) SetShareTableFiles(val bool) {
+	var cbdbop *C.BackupableDBOptions_t = &bdbop.bdbop
+	C.BackupableDBOptions_set_share_table_files(cbdbop, toCBool(val))
+}
+
+func (bdbop *BackupableDBOptions) ShareTableFiles() bool {
+	var cbdbop *C.BackupableDBOptions_t = &bdbop.bdbop
+	return C.BackupableDBOptions_get_share_table_files(cbdbop).toBool()
+}
+
+// If sync == true, we can guarantee you'll get consistent backup even
+// on a machine crash/reboot. Backup process is slower with sync enabled.
+// If sync == false, we don't guarantee anything on machine reboot. However,
+// chances are some of the backups are consistent.
+// Default: true
+func (bdbop *BackupableDBOptions) SetSync(val bool) {
+	var cbdbop *C.BackupableDBOptions_t = &bdbop.bdbop
+	C.BackupableDBOptions_set_sync(cbdbop, toCBool(val))
+}
+
+func (bdbop *BackupableDBOptions) Sync() bool {
+	var cbdbop *C.BackupableDBOptions_t = &bdbop.bdbop
+	return C.BackupableDBOptions_get_sync(cbdbop).toBool()
+}
+
+// If true, it will delete whatever backups there are already
+// Default: false
+func (bdbop *BackupableDBOptions) SetDestroyOldData(val bool) {
+	var cbdbop *C.BackupableDBOptions_t = &bdbop.bdbop
+	C.BackupableDBOptions_set_destroy_old_data(cbdbop, toCBool(val))
+}
+
+func (bdbop *BackupableDBOptions) DestroyOldData() bool {
+	var cbdbop *C.BackupableDBOptions_t = &bdbop.bdbop
+	return C.BackupableDBOptions_get_destroy_old_data(cbdbop).toBool()
+}
+
+// If false, we won't backup log files. This option can be useful for backing
+// up in-memory databases where log file are persisted, but table files are in
+// memory.
+// Default: true
+func (bdbop *BackupableDBOptions) SetBackupLogFiles(val bool) {
+	var cbdbop *C.BackupableDBOptions_t = &bdbop.bdbop
+	C.BackupableDBOptions_set_backup_log_files(cbdbop, toCBool(val))
+}
+
+func (bdbop *BackupableDBOptions) BackupLogFiles() bool {
+	var cbdbop *C.BackupableDBOptions_t = &bdbop.bdbop
+	return C.BackupableDBOptions_get_backup_log_files(cbdbop).toBool()
+}
+
+// Max bytes that can be transferred in a second during backup.
+// If 0, go as fast as you can
+// Default: 0
+func (bdbop *BackupableDBOptions) SetBackupRateLimit(val uint64) {
+	var cbdbop *C.BackupableDBOptions_t = &bdbop.bdbop
+	C.BackupableDBOptions_set_backup_rate_limit(cbdbop, C.uint64_t(val))
+}
+
+func (bdbop *BackupableDBOptions) BackupRateLimit() uint64 {
+	var cbdbop *C.BackupableDBOptions_t = &bdbop.bdbop
+	return uint64(C.BackupableDBOptions_get_backup_rate_limit(cbdbop))
+}
+
+// Max bytes that can be transferred in a second during restore.
+// If 0, go as fast as you can
+// Default: 0
+func (bdbop *BackupableDBOptions) SetRestoreRateLimit(val uint64) {
+	var cbdbop *C.BackupableDBOptions_t = &bdbop.bdbop
+	C.BackupableDBOptions_set_restore_rate_limit(cbdbop, C.uint64_t(val))
+}
+
+func (bdbop *BackupableDBOptions) RestoreRateLimit() uint64 {
+	var cbdbop *C.BackupableDBOptions_t = &bdbop.bdbop
+	return uint64(C.BackupableDBOptions_get_restore_rate_limit(cbdbop))
+}
+
+// Only used if share_table_files is set to true. If true, will consider that
+// backups can come from different databases, hence a sst is not uniquely
+// identifed by its name, but by the triple (file name, crc32, file length)
+// Default: false
+func (bdbop *BackupableDBOptions) SetShareFilesWithChecksum(val bool) {
+	var cbdbop *C.BackupableDBOptions_t = &bdbop.bdbop
+	C.BackupableDBOptions_set_share_files_with_checksum(cbdbop, toCBool(val))
+}
+
+func (bdbop *BackupableDBOptions) ShareFilesWithChecksum() bool {
+	var cbdbop *C.BackupableDBOptions_t = &bdbop.bdbop
+	return C.BackupableDBOptions_get_share_files_with_checksum(cbdbop).toBool()
+}
+
+// Up to this many background threads will copy files for CreateNewBackup()
+// and RestoreDBFromBackup()
+// Default: 1
+func (bdbop *BackupableDBOptions) SetMaxBackgroundOperations(val int) {
+	var cbdbop *C.BackupableDBOptions_t = &bdbop.bdbop
+	C.BackupableDBOptions_set_max_background_operations(cbdbop, C.int(val))
+}
+
+func (bdbop *BackupableDBOptions) MaxBackgroundOperations() int {
+	var cbdbop *C.BackupableDBOptions_t = &bdbop.bdbop
+	return int(C.BackupableDBOptions_get_max_background_operations(cbdbop))
+}
+
 // Wrap go RestoreOptions
 type RestoreOptions struct {
 	rsop C.RestoreOptions_t
@@ -95,6 +207,42 @@ func NewRestoreOptions() *RestoreOptions {
 	return crsop.toRestoreOptions()
 }
 
+// Information about a backup
+type BackupInfo struct {
+	ID uint32
+	// Seconds since the epoch when the backup was created
+	Timestamp int64
+	// Size of the backup in bytes
+	Size uint64
+	// Number of the files in the backup
+	NumberFiles uint32
+}
+
+// C BackupInfo array to go BackupInfo array
+func newBackupInfoArrayFromCArray(cbinfos *C.BackupInfo_t, sz uint) (binfos []BackupInfo) {
+	defer C.DeleteBackupInfoTArray(cbinfos)
+	binfos = make([]BackupInfo, sz)
+	for i := uint(0); i < sz; i++ {
+		var cbinfo *C.BackupInfo_t = &(*[arrayDimenMax]C.BackupInfo_t)(unsafe.Pointer(cbinfos))[i]
+		binfos[i].ID = uint32(C.BackupInfo_get_backup_id(cbinfo))
+		binfos[i].Timestamp = int64(C.BackupInfo_get_timestamp(cbinfo))
+		binfos[i].Size = uint64(C.BackupInfo_get_size(cbinfo))
+		binfos[i].NumberFiles = uint32(C.BackupInfo_get_number_files(cbinfo))
+		C.DeleteBackupInfoT(cbinfo, toCBool(false))
+	}
+	return
+}
+
+// C BackupID array to go uint32 array
+func newBackupIDArrayFromCArray(cids *C.BackupID, sz uint) (ids []uint32) {
+	defer C.free(unsafe.Pointer(cids))
+	ids = make([]uint32, sz)
+	for i := uint(0); i < sz; i++ {
+		ids[i] = uint32((*[arrayDimenMax]C.BackupID)(unsafe.Pointer(cids))[i])
+	}
+	return
+}
+
 // Wrap go BackupEngine
 type BackupEngine struct {
 	beg C.BackupEngine_t
@@ -166,3 +314,138 @@ func (beg *BackupEngine) RestoreDBFromLatestBackup(dbdir *string, waldir *string
 	stat = cstat.toStatus()
 	return
 }
+
+// Deletes old backups, keeping latest num_backups_to_keep alive
+func (beg *BackupEngine) PurgeOldBackups(keep uint32) (stat *Status) {
+	cstat := C.BackupEnginePurgeOldBackups(&beg.beg, C.uint32_t(keep))
+	stat = cstat.toStatus()
+	return
+}
+
+// Deletes a specific backup
+func (beg *BackupEngine) DeleteBackup(id uint32) (stat *Status) {
+	cstat := C.BackupEngineDeleteBackup(&beg.beg, C.BackupID(id))
+	stat = cstat.toStatus()
+	return
+}
+
+// Returns info about backups
+func (beg *BackupEngine) GetBackupInfo() (binfos []BackupInfo) {
+	var (
+		cbinfos *C.BackupInfo_t
+		n C.int
+	)
+	C.BackupEngineGetBackupInfo(&beg.beg, &cbinfos, &n)
+	binfos = newBackupInfoArrayFromCArray(cbinfos, uint(n))
+	return
+}
+
+// Returns the IDs of the corrupt backups
+func (beg *BackupEngine) GetCorruptedBackups() (ids []uint32) {
+	var (
+		cids *C.BackupID
+		n C.int
+	)
+	C.BackupEngineGetCorruptedBackups(&beg.beg, &cids, &n)
+	ids = newBackupIDArrayFromCArray(cids, uint(n))
+	return
+}
+
+// Checks that each file exists and that the size of the file matches our
+// expectations. It does not check file checksum.
+// Returns Status::OK() if all checks are good
+func (beg *BackupEngine) VerifyBackup(id uint32) (stat *Status) {
+	cstat := C.BackupEngineVerifyBackup(&beg.beg, C.BackupID(id))
+	stat = cstat.toStatus()
+	return
+}
+
+// Will delete all the files we don't need anymore
+// It will do the full scan of the files/ directory and delete all the
+// files that are not referenced.
+func (beg *BackupEngine) GarbageCollect() (stat *Status) {
+	cstat := C.BackupEngineGarbageCollect(&beg.beg)
+	stat = cstat.toStatus()
+	return
+}
+
+// Wrap go BackupEngineReadOnly, a backup engine for accessing information
+// about backups and restoring from them. It never modifies the backup
+// directory.
+type BackupEngineReadOnly struct {
+	begro C.BackupEngineReadOnly_t
+	// true if begro is deleted
+	closed bool
+}
+
+// Release resources
+func (begro *BackupEngineReadOnly) finalize() {
+	if !begro.closed {
+		begro.closed = true
+		var cbegro *C.BackupEngineReadOnly_t = &begro.begro
+		C.DeleteBackupEngineReadOnlyT(cbegro, toCBool(false))
+	}
+}
+
+// Close the @BackupEngineReadOnly
+func (begro *BackupEngineReadOnly) Close() {
+	runtime.SetFinalizer(begro, nil)
+	begro.finalize()
+}
+
+// Create a new read only backup engine from db_env and options.
+func BackupEngineReadOnlyOpen(env *Env, options *BackupableDBOptions) (begro *BackupEngineReadOnly, stat *Status) {
+	begro = &BackupEngineReadOnly{}
+	cstat := C.BackupEngineReadOnlyOpen(&env.env, &options.bdbop, &begro.begro)
+	stat = cstat.toStatus()
+	if stat.Ok() {
+		runtime.SetFinalizer(begro, finalize)
+	}
+	return
+}
+
+// Returns info about backups
+func (begro *BackupEngineReadOnly) GetBackupInfo() (binfos []BackupInfo) {
+	var (
+		cbinfos *C.BackupInfo_t
+		n C.int
+	)
+	C.BackupEngineReadOnlyGetBackupInfo(&begro.begro, &cbinfos, &n)
+	binfos = newBackupInfoArrayFromCArray(cbinfos, uint(n))
+	return
+}
+
+// Returns the IDs of the corrupt backups
+func (begro *BackupEngineReadOnly) GetCorruptedBackups() (ids []uint32) {
+	var (
+		cids *C.BackupID
+		n C.int
+	)
+	C.BackupEngineReadOnlyGetCorruptedBackups(&begro.begro, &cids, &n)
+	ids = newBackupIDArrayFromCArray(cids, uint(n))
+	return
+}
+
+// Restoring DB from backup is NOT safe when there is another BackupEngine
+// running that might call DeleteBackup() or PurgeOldBackups(). It is caller's
+// responsibility to synchronize the operation, i.e. don't delete the backup
+// when you're restoring from it
+func (begro *BackupEngineReadOnly) RestoreDBFromBackup(id uint32, dbdir *string, waldir *string, rsop *RestoreOptions) (stat *Status) {
+	cdbd := newCStringFromString(dbdir)
+	defer cdbd.del()
+	cwald := newCStringFromString(waldir)
+	defer cwald.del()
+	cstat := C.BackupEngineReadOnlyRestoreDBFromBackup(&begro.begro, C.BackupID(id), &cdbd.str, &cwald.str, &rsop.rsop)
+	stat = cstat.toStatus()
+	return
+}
+
+func (begro *BackupEngineReadOnly) RestoreDBFromLatestBackup(dbdir *string, waldir *string, rsop *RestoreOptions) (stat *Status) {
+	cdbd := newCStringFromString(dbdir)
+	defer cdbd.del()
+	cwald := newCStringFromString(waldir)
+	defer cwald.del()
+	cstat := C.BackupEngineReadOnlyRestoreDBFromLatestBackup(&begro.begro, &cdbd.str, &cwald.str, &rsop.rsop)
+	stat = cstat.toStatus()
+	return
+}
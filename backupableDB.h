@@ -18,6 +18,23 @@ DEFINE_C_WRAP_STRUCT(BackupableDBOptions)
 DEFINE_C_WRAP_CONSTRUCTOR_DEC(BackupableDBOptions)
 DEFINE_C_WRAP_CONSTRUCTOR_ARGS_DEC(BackupableDBOptions, String)
 DEFINE_C_WRAP_DESTRUCTOR_DEC(BackupableDBOptions)
+// Get/Set methods
+DEFINE_C_WRAP_GETTER_DEC(BackupableDBOptions, share_table_files, bool)
+DEFINE_C_WRAP_SETTER_DEC(BackupableDBOptions, share_table_files, bool)
+DEFINE_C_WRAP_GETTER_DEC(BackupableDBOptions, sync, bool)
+DEFINE_C_WRAP_SETTER_DEC(BackupableDBOptions, sync, bool)
+DEFINE_C_WRAP_GETTER_DEC(BackupableDBOptions, destroy_old_data, bool)
+DEFINE_C_WRAP_SETTER_DEC(BackupableDBOptions, destroy_old_data, bool)
+DEFINE_C_WRAP_GETTER_DEC(BackupableDBOptions, backup_log_files, bool)
+DEFINE_C_WRAP_SETTER_DEC(BackupableDBOptions, backup_log_files, bool)
+DEFINE_C_WRAP_GETTER_DEC(BackupableDBOptions, backup_rate_limit, uint64_t)
+DEFINE_C_WRAP_SETTER_DEC(BackupableDBOptions, backup_rate_limit, uint64_t)
+DEFINE_C_WRAP_GETTER_DEC(BackupableDBOptions, restore_rate_limit, uint64_t)
+DEFINE_C_WRAP_SETTER_DEC(BackupableDBOptions, restore_rate_limit, uint64_t)
+DEFINE_C_WRAP_GETTER_DEC(BackupableDBOptions, share_files_with_checksum, bool)
+DEFINE_C_WRAP_SETTER_DEC(BackupableDBOptions, share_files_with_checksum, bool)
+DEFINE_C_WRAP_GETTER_DEC(BackupableDBOptions, max_background_operations, int)
+DEFINE_C_WRAP_SETTER_DEC(BackupableDBOptions, max_background_operations, int)
 
 DEFINE_C_WRAP_STRUCT(RestoreOptions)
 DEFINE_C_WRAP_CONSTRUCTOR_DEC(RestoreOptions)
@@ -26,6 +43,17 @@ DEFINE_C_WRAP_DESTRUCTOR_DEC(RestoreOptions)
 // Get/Set methods
 DEFINE_C_WRAP_SETTER_DEC(RestoreOptions, keep_log_files, bool)
 
+// Information about a backup
+DEFINE_C_WRAP_STRUCT(BackupInfo)
+DEFINE_C_WRAP_CONSTRUCTOR_DEC(BackupInfo)
+DEFINE_C_WRAP_DESTRUCTOR_DEC(BackupInfo)
+DEFINE_C_WRAP_DESTRUCTOR_ARRAY_DEC(BackupInfo)
+// Get methods
+DEFINE_C_WRAP_GETTER_DEC(BackupInfo, backup_id, BackupID)
+DEFINE_C_WRAP_GETTER_DEC(BackupInfo, timestamp, int64_t)
+DEFINE_C_WRAP_GETTER_DEC(BackupInfo, size, uint64_t)
+DEFINE_C_WRAP_GETTER_DEC(BackupInfo, number_files, uint32_t)
+
 // Please see the documentation in BackupableDB and RestoreBackupableDB
 DEFINE_C_WRAP_STRUCT(BackupEngine)
 DEFINE_C_WRAP_CONSTRUCTOR_DEC(BackupEngine)
@@ -44,6 +72,48 @@ Status_t BackupEngineCreateNewBackup(BackupEngine_t* backup_engine_ptr, DB_t* db
 Status_t BackupEngineRestoreDBFromBackup(BackupEngine_t* backup_engine_ptr, BackupID backup_id, String_t* db_dir, String_t* wal_dir, RestoreOptions_t* restore_options);
 Status_t BackupEngineRestoreDBFromLatestBackup(BackupEngine_t* backup_engine_ptr, String_t* db_dir, String_t* wal_dir, RestoreOptions_t* restore_options);
 
+// Deletes old backups, keeping latest num_backups_to_keep alive
+Status_t BackupEnginePurgeOldBackups(BackupEngine_t* backup_engine_ptr, uint32_t num_backups_to_keep);
+
+// Deletes a specific backup
+Status_t BackupEngineDeleteBackup(BackupEngine_t* backup_engine_ptr, BackupID backup_id);
+
+// Returns info about backups in backup_info
+void BackupEngineGetBackupInfo(BackupEngine_t* backup_engine_ptr, BackupInfo_t** backup_info, int* n);
+
+// Returns info about corrupt backups in corrupt_backups
+void BackupEngineGetCorruptedBackups(BackupEngine_t* backup_engine_ptr, BackupID** corrupt_backup_ids, int* n);
+
+// Checks that each file exists and that the size of the file matches our
+// expectations. It does not check file checksum.
+Status_t BackupEngineVerifyBackup(BackupEngine_t* backup_engine_ptr, BackupID backup_id);
+
+// Will delete all the files we don't need anymore
+// It will do the full scan of the files/ directory and delete all the
+// files that are not referenced.
+Status_t BackupEngineGarbageCollect(BackupEngine_t* backup_engine_ptr);
+
+// A backup engine for accessing information about backups and restoring from
+// them.
+DEFINE_C_WRAP_STRUCT(BackupEngineReadOnly)
+DEFINE_C_WRAP_CONSTRUCTOR_DEC(BackupEngineReadOnly)
+DEFINE_C_WRAP_DESTRUCTOR_DEC(BackupEngineReadOnly)
+
+// Create a new read only backup engine from db_env and options.
+Status_t BackupEngineReadOnlyOpen(Env_t* db_env, const BackupableDBOptions_t* options, BackupEngineReadOnly_t* backup_engine_ptr);
+
+// Returns info about backups in backup_info
+void BackupEngineReadOnlyGetBackupInfo(BackupEngineReadOnly_t* backup_engine_ptr, BackupInfo_t** backup_info, int* n);
+
+// Returns info about corrupt backups in corrupt_backups
+void BackupEngineReadOnlyGetCorruptedBackups(BackupEngineReadOnly_t* backup_engine_ptr, BackupID** corrupt_backup_ids, int* n);
+
+// Restoring DB from backup is NOT safe when there is another BackupEngine
+// running that might call DeleteBackup() or PurgeOldBackups(). It is caller's
+// responsibility to synchronize the operation, i.e. don't delete the backup
+// when you're restoring from it
+Status_t BackupEngineReadOnlyRestoreDBFromBackup(BackupEngineReadOnly_t* backup_engine_ptr, BackupID backup_id, String_t* db_dir, String_t* wal_dir, RestoreOptions_t* restore_options);
+Status_t BackupEngineReadOnlyRestoreDBFromLatestBackup(BackupEngineReadOnly_t* backup_engine_ptr, String_t* db_dir, String_t* wal_dir, RestoreOptions_t* restore_options);
 
 // BackupableDBOptions have to be the same as the ones used in a previous
 // incarnation of the DB
@@ -0,0 +1,569 @@
+// Copyright (c) 2015, Dean ChaoJun Pan.  All rights reserved.
+// This source code is licensed under the BSD-style license found in the
+// LICENSE file in the root directory of this source tree.
+//
+// Bulk loading on top of SstFileWriter and AddFile. BulkLoader sorts
+// the added key/value pairs externally, writes them to non-overlapping
+// SST files and adds the files to the database, which bypasses the
+// memtable, the WAL and the compactions of the regular write path.
+
+// +build !lite
+
+package rocksdb
+
+import (
+	"bufio"
+	"bytes"
+	"container/heap"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"sync"
+)
+
+// Options to control the behavior of a BulkLoader
+type BulkLoaderOptions struct {
+	// Directory under which the sorted runs and the SST files are
+	// created.
+	// Default: os.TempDir()
+	TempDir string
+
+	// If true, the SST files are hard linked into the database instead
+	// of copied. TempDir must be on the same file system as the database.
+	// Default: false
+	MoveFiles bool
+
+	// Bytes of keys and values buffered in memory before they are
+	// sorted and spilled to a run file.
+	// Default: 64MB
+	MemoryBudget int
+
+	// Max number of runs sorted and spilled concurrently. Add blocks
+	// while as many runs are being spilled. Up to
+	// (Parallelism + 1) * MemoryBudget bytes can be held in memory.
+	// Default: runtime.NumCPU()
+	Parallelism int
+
+	// Bytes of keys and values written to each SST file.
+	// Default: 64MB
+	TargetFileSize uint64
+}
+
+const (
+	defaultBulkLoaderMemoryBudget = 64 << 20
+	defaultBulkLoaderTargetFileSize = 64 << 20
+)
+
+// Name of the builtin byte-wise comparator
+const bytewiseComparatorName = "leveldb.BytewiseComparator"
+
+// The success status returned by every successful BulkLoader.Add, so
+// that adding a pair costs no cgo call
+var bulkLoaderOKStatus = NewOKStatus()
+
+// A key/value pair buffered by BulkLoader
+type bulkEntry struct {
+	key []byte
+	val []byte
+	// Order of the pair in the calls to Add. The last added value
+	// wins if the same key is added more than once.
+	seq uint64
+}
+
+// BulkLoader loads unsorted key/value pairs into a column family.
+// Add can be called from many goroutines concurrently. The pairs are
+// sorted by the comparator of the column family, which may be a go
+// IComparator, written to SST files and added to the database by Finish.
+//
+// Like AddFile, the loaded keys must not overlap the keys already in the
+// column family, and no other writes may happen during Finish.
+type BulkLoader struct {
+	db *DB
+	options *Options
+	cfh []*ColumnFamilyHandle
+	bopts BulkLoaderOptions
+	cmp func(a, b []byte) int
+	// Directory of the run files and the SST files
+	dir string
+
+	// Protect the fields below
+	mu sync.Mutex
+	buf []bulkEntry
+	bufSize int
+	seq uint64
+	runs []string
+	nextFile int
+	finished bool
+
+	// Protect stat. It is separate from mu so that a failed spilling
+	// goroutine never waits for an Add blocked on sem.
+	statMu sync.Mutex
+	// First error of the spilling goroutines
+	stat *Status
+
+	// Limit the spilling goroutines
+	sem chan struct{}
+	spills sync.WaitGroup
+}
+
+// Create a BulkLoader that loads into the column family cfh (default
+// column family if not specified) of db. options must be the options the
+// column family is opened with, as the SST files are built with its
+// comparator, table factory and the other settings. bopts can be nil.
+func NewBulkLoader(db *DB, options *Options, bopts *BulkLoaderOptions, cfh ...*ColumnFamilyHandle) (bl *BulkLoader, stat *Status) {
+	if db.closed {
+		stat = NewDBClosedStatus()
+		return
+	}
+
+	bl = &BulkLoader{db: db, options: options, cfh: cfh}
+	if nil != bopts {
+		bl.bopts = *bopts
+	}
+	if bl.bopts.TempDir == "" {
+		bl.bopts.TempDir = os.TempDir()
+	}
+	if bl.bopts.MemoryBudget <= 0 {
+		bl.bopts.MemoryBudget = defaultBulkLoaderMemoryBudget
+	}
+	if bl.bopts.Parallelism <= 0 {
+		bl.bopts.Parallelism = runtime.NumCPU()
+	}
+	if bl.bopts.TargetFileSize == 0 {
+		bl.bopts.TargetFileSize = defaultBulkLoaderTargetFileSize
+	}
+	bl.sem = make(chan struct{}, bl.bopts.Parallelism)
+
+	// Avoid the cgo calls per comparison for the default ordering and
+	// the go comparators. Only the comparators implemented in c++ are
+	// called through cgo.
+	if gocmp := options.ColumnFamilyOptions.cmp; nil != gocmp && nil != gocmp.itf {
+		bl.cmp = gocmp.itf.Compare
+	} else if cmp := options.ColumnFamilyOptions.Comparator(); cmp.Name() == bytewiseComparatorName {
+		bl.cmp = bytes.Compare
+	} else {
+		bl.cmp = cmp.Compare
+	}
+
+	dir, err := ioutil.TempDir(bl.bopts.TempDir, "rocksdb_bulkload")
+	if nil != err {
+		bl = nil
+		stat = NewIOErrorStatus(err.Error())
+		return
+	}
+	bl.dir = dir
+	stat = NewOKStatus()
+	return
+}
+
+// Add the pair key/value. The byte slices are copied, so they can be
+// reused by the caller after Add returns. If the same key is added more
+// than once, the value of the last call to Add wins.
+// On success the same shared OK status is returned by every call.
+func (bl *BulkLoader) Add(key, val []byte) (stat *Status) {
+	ent := bulkEntry{
+		key: append([]byte(nil), key...),
+		val: append([]byte(nil), val...),
+	}
+
+	bl.mu.Lock()
+	if bl.finished {
+		bl.mu.Unlock()
+		stat = NewInvalidArgumentStatus("BulkLoader is finished")
+		return
+	}
+	if stat = bl.getStat(); nil != stat {
+		bl.mu.Unlock()
+		return
+	}
+
+	ent.seq = bl.seq
+	bl.seq++
+	bl.buf = append(bl.buf, ent)
+	bl.bufSize += len(ent.key) + len(ent.val)
+	var (
+		buf []bulkEntry
+		path string
+	)
+	if bl.bufSize >= bl.bopts.MemoryBudget {
+		buf, path = bl.takeBufLocked()
+	}
+	bl.mu.Unlock()
+
+	// Wait for a spilling slot without holding bl.mu, so the other
+	// calls to Add and the spilling goroutines are not blocked.
+	if nil != buf {
+		bl.spill(path, buf)
+	}
+	return bulkLoaderOKStatus
+}
+
+// Take the buffered pairs to spill to the returned run file. The
+// caller must pass them to spill after releasing bl.mu. Returns nil
+// if nothing is buffered.
+// REQUIRES: bl.mu is held
+func (bl *BulkLoader) takeBufLocked() (buf []bulkEntry, path string) {
+	if len(bl.buf) == 0 {
+		return
+	}
+
+	buf = bl.buf
+	path = bl.newFileNameLocked("run")
+	bl.runs = append(bl.runs, path)
+	bl.buf = nil
+	bl.bufSize = 0
+	// Count the spill before bl.mu is released, so that Finish
+	// waits for it.
+	bl.spills.Add(1)
+	return
+}
+
+// Write buf to the run file path in a spilling goroutine. Blocks while
+// Parallelism runs are being spilled.
+// REQUIRES: bl.mu is not held
+func (bl *BulkLoader) spill(path string, buf []bulkEntry) {
+	bl.sem <- struct{}{}
+	go func() {
+		defer bl.spills.Done()
+		defer func() { <-bl.sem }()
+
+		if stat := bl.writeRun(path, buf); nil != stat {
+			bl.setStat(stat)
+		}
+	}()
+}
+
+// Record the first error of the spilling goroutines
+func (bl *BulkLoader) setStat(stat *Status) {
+	bl.statMu.Lock()
+	defer bl.statMu.Unlock()
+	if nil == bl.stat {
+		bl.stat = stat
+	}
+}
+
+// Return the first error of the spilling goroutines, nil if none
+func (bl *BulkLoader) getStat() *Status {
+	bl.statMu.Lock()
+	defer bl.statMu.Unlock()
+	return bl.stat
+}
+
+// Return a new file name under bl.dir
+// REQUIRES: bl.mu is held
+func (bl *BulkLoader) newFileNameLocked(ext string) string {
+	bl.nextFile++
+	return filepath.Join(bl.dir, fmt.Sprintf("%06d.%s", bl.nextFile, ext))
+}
+
+// Sort buf and write it to the run file path. Of the pairs with the
+// same key only the last added one is written.
+func (bl *BulkLoader) writeRun(path string, buf []bulkEntry) (stat *Status) {
+	sort.Sort(&bulkEntrySorter{ents: buf, cmp: bl.cmp})
+
+	f, err := os.Create(path)
+	if nil != err {
+		return NewIOErrorStatus(err.Error())
+	}
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	for i := range buf {
+		if i > 0 && bl.cmp(buf[i-1].key, buf[i].key) == 0 {
+			continue
+		}
+		if err = writeBulkEntry(w, &buf[i]); nil != err {
+			return NewIOErrorStatus(err.Error())
+		}
+	}
+	if err = w.Flush(); nil != err {
+		return NewIOErrorStatus(err.Error())
+	}
+	if err = f.Close(); nil != err {
+		return NewIOErrorStatus(err.Error())
+	}
+	return
+}
+
+// Sort bulkEntrys by key, the last added first
+type bulkEntrySorter struct {
+	ents []bulkEntry
+	cmp func(a, b []byte) int
+}
+
+func (s *bulkEntrySorter) Len() int {
+	return len(s.ents)
+}
+
+func (s *bulkEntrySorter) Swap(i, j int) {
+	s.ents[i], s.ents[j] = s.ents[j], s.ents[i]
+}
+
+func (s *bulkEntrySorter) Less(i, j int) bool {
+	return bulkEntryLess(s.cmp, &s.ents[i], &s.ents[j])
+}
+
+func bulkEntryLess(cmp func(a, b []byte) int, a, b *bulkEntry) bool {
+	if c := cmp(a.key, b.key); c != 0 {
+		return c < 0
+	}
+	return a.seq > b.seq
+}
+
+// Serialize ent as varint key length, key, varint value length,
+// value and varint seq.
+func writeBulkEntry(w *bufio.Writer, ent *bulkEntry) (err error) {
+	var hdr [binary.MaxVarintLen64]byte
+
+	n := binary.PutUvarint(hdr[:], uint64(len(ent.key)))
+	if _, err = w.Write(hdr[:n]); nil != err {
+		return
+	}
+	if _, err = w.Write(ent.key); nil != err {
+		return
+	}
+	n = binary.PutUvarint(hdr[:], uint64(len(ent.val)))
+	if _, err = w.Write(hdr[:n]); nil != err {
+		return
+	}
+	if _, err = w.Write(ent.val); nil != err {
+		return
+	}
+	n = binary.PutUvarint(hdr[:], ent.seq)
+	_, err = w.Write(hdr[:n])
+	return
+}
+
+// Deserialize a bulkEntry written by writeBulkEntry. Returns io.EOF
+// at the end of the run.
+func readBulkEntry(r *bufio.Reader, ent *bulkEntry) (err error) {
+	var sz uint64
+
+	if sz, err = binary.ReadUvarint(r); nil != err {
+		return
+	}
+	ent.key = make([]byte, sz)
+	if _, err = io.ReadFull(r, ent.key); nil != err {
+		return unexpectedEOF(err)
+	}
+	if sz, err = binary.ReadUvarint(r); nil != err {
+		return unexpectedEOF(err)
+	}
+	ent.val = make([]byte, sz)
+	if _, err = io.ReadFull(r, ent.val); nil != err {
+		return unexpectedEOF(err)
+	}
+	ent.seq, err = binary.ReadUvarint(r)
+	return unexpectedEOF(err)
+}
+
+// io.EOF in the middle of an entry means a truncated run
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// A run being merged
+type bulkRun struct {
+	f *os.File
+	r *bufio.Reader
+	// The current entry of the run
+	ent bulkEntry
+}
+
+// Min heap of bulkRuns ordered by their current entry
+type bulkRunHeap struct {
+	runs []*bulkRun
+	cmp func(a, b []byte) int
+}
+
+func (h *bulkRunHeap) Len() int {
+	return len(h.runs)
+}
+
+func (h *bulkRunHeap) Swap(i, j int) {
+	h.runs[i], h.runs[j] = h.runs[j], h.runs[i]
+}
+
+func (h *bulkRunHeap) Less(i, j int) bool {
+	return bulkEntryLess(h.cmp, &h.runs[i].ent, &h.runs[j].ent)
+}
+
+func (h *bulkRunHeap) Push(x interface{}) {
+	h.runs = append(h.runs, x.(*bulkRun))
+}
+
+func (h *bulkRunHeap) Pop() interface{} {
+	n := len(h.runs)
+	run := h.runs[n-1]
+	h.runs = h.runs[:n-1]
+	return run
+}
+
+// Spill the buffered pairs, merge all the runs into SST files and add
+// them to the database. The temporary files are removed whether or not
+// Finish succeeds. The BulkLoader can't be used after Finish.
+//
+// AddFile ingests one SST file per call, so the load is not atomic. The
+// files are added in the comparator order, and added is the number of
+// them added to the database. If AddFile fails, the first added files
+// stay ingested, i.e. the keys up to some key are loaded, and the rest
+// of the files are removed.
+func (bl *BulkLoader) Finish() (added int, stat *Status) {
+	bl.mu.Lock()
+	if bl.finished {
+		bl.mu.Unlock()
+		stat = NewInvalidArgumentStatus("BulkLoader is finished")
+		return
+	}
+	bl.finished = true
+	buf, path := bl.takeBufLocked()
+	bl.mu.Unlock()
+
+	if nil != buf {
+		bl.spill(path, buf)
+	}
+	bl.spills.Wait()
+	defer os.RemoveAll(bl.dir)
+
+	if stat = bl.getStat(); nil != stat {
+		return
+	}
+	if bl.db.closed {
+		stat = NewDBClosedStatus()
+		return
+	}
+
+	var ssts []string
+	if ssts, stat = bl.merge(); !stat.Ok() {
+		return
+	}
+
+	for _, sst := range ssts {
+		if stat = bl.db.AddFile(&sst, bl.bopts.MoveFiles, bl.cfh...); !stat.Ok() {
+			return
+		}
+		added++
+	}
+	return
+}
+
+// Abandon the load and remove the temporary files.
+func (bl *BulkLoader) Close() {
+	bl.mu.Lock()
+	bl.finished = true
+	bl.mu.Unlock()
+
+	bl.spills.Wait()
+	os.RemoveAll(bl.dir)
+}
+
+// Merge the runs into SST files of about TargetFileSize bytes each.
+// As the runs are merged in the comparator order, the key ranges of the
+// SST files don't overlap.
+func (bl *BulkLoader) merge() (ssts []string, stat *Status) {
+	h := &bulkRunHeap{cmp: bl.cmp}
+	defer func() {
+		for _, run := range h.runs {
+			run.f.Close()
+		}
+	}()
+
+	for _, path := range bl.runs {
+		f, err := os.Open(path)
+		if nil != err {
+			stat = NewIOErrorStatus(err.Error())
+			return
+		}
+		run := &bulkRun{f: f, r: bufio.NewReader(f)}
+		if err = readBulkEntry(run.r, &run.ent); nil == err {
+			h.runs = append(h.runs, run)
+		} else {
+			f.Close()
+			if err != io.EOF {
+				stat = NewIOErrorStatus(err.Error())
+				return
+			}
+		}
+	}
+	heap.Init(h)
+
+	var (
+		sfw *SstFileWriter
+		size uint64
+		last []byte
+	)
+	defer func() {
+		if nil != sfw {
+			sfw.Close()
+		}
+	}()
+
+	for h.Len() > 0 {
+		run := h.runs[0]
+		ent := run.ent
+
+		// Advance the run before writing, ent holds the current entry
+		if err := readBulkEntry(run.r, &run.ent); nil == err {
+			heap.Fix(h, 0)
+		} else {
+			heap.Pop(h)
+			run.f.Close()
+			if err != io.EOF {
+				stat = NewIOErrorStatus(err.Error())
+				return
+			}
+		}
+
+		// The last added value of a key comes first, skip the others
+		if nil != last && bl.cmp(last, ent.key) == 0 {
+			continue
+		}
+		last = ent.key
+
+		if nil == sfw {
+			bl.mu.Lock()
+			path := bl.newFileNameLocked("sst")
+			bl.mu.Unlock()
+			sfw = NewSstFileWriter(bl.options)
+			if stat = sfw.Open(&path); !stat.Ok() {
+				return
+			}
+			ssts = append(ssts, path)
+			size = 0
+		}
+
+		if stat = sfw.Add(ent.key, ent.val); !stat.Ok() {
+			return
+		}
+		size += uint64(len(ent.key) + len(ent.val))
+
+		if size >= bl.bopts.TargetFileSize {
+			_, stat = sfw.Finish()
+			sfw.Close()
+			sfw = nil
+			if !stat.Ok() {
+				return
+			}
+		}
+	}
+
+	if nil != sfw {
+		_, stat = sfw.Finish()
+		sfw.Close()
+		sfw = nil
+		if !stat.Ok() {
+			return
+		}
+	}
+
+	stat = NewOKStatus()
+	return
+}
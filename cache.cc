@@ -32,3 +32,19 @@ PCache_t NewPCacheTRawArgs(size_t capacity, int numShardBits)
     *((PCache*)wrap_t.rep) = NewLRUCache(capacity, numShardBits);
     return wrap_t;
 }
+
+// returns the maximum configured capacity of the cache
+size_t PCacheGetCapacity(const PCache_t* cache)
+{
+    return ((cache && GET_REP(cache, PCache) && GET_REP_REF(cache, PCache)) ?
+            GET_REP_REF(cache, PCache)->GetCapacity() :
+            0);
+}
+
+// returns the memory size for the entries residing in the cache.
+size_t PCacheGetUsage(const PCache_t* cache)
+{
+    return ((cache && GET_REP(cache, PCache) && GET_REP_REF(cache, PCache)) ?
+            GET_REP_REF(cache, PCache)->GetUsage() :
+            0);
+}
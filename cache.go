@@ -70,3 +70,23 @@ func NewLRUCache(capacity uint64, numShardBits ...int) *Cache {
 	ccache := C.NewPCacheTRawArgs(C.size_t(capacity), cnumshbits)
 	return ccache.toCache()
 }
+
+// returns the maximum configured capacity of the cache
+func (cache *Cache) GetCapacity() uint64 {
+	if cache.closed {
+		return 0
+	}
+
+	var ccache *C.PCache_t = &cache.cache
+	return uint64(C.PCacheGetCapacity(ccache))
+}
+
+// returns the memory size for the entries residing in the cache.
+func (cache *Cache) GetUsage() uint64 {
+	if cache.closed {
+		return 0
+	}
+
+	var ccache *C.PCache_t = &cache.cache
+	return uint64(C.PCacheGetUsage(ccache))
+}
@@ -25,6 +25,11 @@ DEFINE_C_WRAP_CONSTRUCTOR_DEC(PCache)
 DEFINE_C_WRAP_CONSTRUCTOR_RAW_ARGS_DEC(PCache, size_t, int)
 DEFINE_C_WRAP_DESTRUCTOR_DEC(PCache)
 
+// returns the maximum configured capacity of the cache
+size_t PCacheGetCapacity(const PCache_t* cache);
+// returns the memory size for the entries residing in the cache.
+size_t PCacheGetUsage(const PCache_t* cache);
+
 #ifdef __cplusplus
 }  /* end extern "C" */
 #endif
@@ -0,0 +1,37 @@
+// Copyright (c) 2015, Dean ChaoJun Pan.  All rights reserved.
+// This source code is licensed under the BSD-style license found in the
+// LICENSE file in the root directory of this source tree.
+
+#include <rocksdb/utilities/checkpoint.h>
+
+using namespace rocksdb;
+
+#include "checkpoint.h"
+
+DEFINE_C_WRAP_CONSTRUCTOR(Checkpoint)
+DEFINE_C_WRAP_DESTRUCTOR(Checkpoint)
+
+// Creates a Checkpoint object to be used for creating openable snapshots
+Status_t CheckpointCreate(DB_t* db, Checkpoint_t* checkpoint_ptr)
+{
+    assert(db != NULL);
+    assert(checkpoint_ptr != NULL);
+    Checkpoint** checkpoint = GET_REP_ADDR(checkpoint_ptr, Checkpoint);
+    Status stat = Checkpoint::Create(GET_REP(db, DB), checkpoint);
+    return NewStatusTCopy(&stat);
+}
+
+// Builds an openable snapshot of RocksDB on the same disk, which
+// accepts an output directory on the same disk, and under the directory
+// (1) hard-linked SST files pointing to existing live SST files
+// SST files will be copied if output directory is on a different filesystem
+// (2) a copied manifest files and other files
+// The directory should not already exist and will be created by this API.
+// The directory will be an absolute path
+Status_t CheckpointCreateCheckpoint(Checkpoint_t* checkpoint_ptr, const String_t* checkpoint_dir)
+{
+    Status ret = ((checkpoint_ptr && GET_REP(checkpoint_ptr, Checkpoint)) ?
+                  GET_REP(checkpoint_ptr, Checkpoint)->CreateCheckpoint(GET_REP_REF(checkpoint_dir, String)) :
+                  invalid_status);
+    return NewStatusTCopy(&ret);
+}
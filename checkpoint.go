@@ -0,0 +1,76 @@
+// Copyright (c) 2015, Dean ChaoJun Pan.  All rights reserved.
+// This source code is licensed under the BSD-style license found in the
+// LICENSE file in the root directory of this source tree.
+
+package rocksdb
+
+/*
+#include "checkpoint.h"
+*/
+import "C"
+
+import (
+	"runtime"
+)
+
+// Wrap go Checkpoint. A checkpoint is an openable snapshot of a
+// database at a point in time.
+type Checkpoint struct {
+	cp C.Checkpoint_t
+	// true if cp is deleted
+	closed bool
+}
+
+// Release resources
+func (cp *Checkpoint) finalize() {
+	if !cp.closed {
+		cp.closed = true
+		var ccp *C.Checkpoint_t = &cp.cp
+		C.DeleteCheckpointT(ccp, toCBool(false))
+	}
+}
+
+// Close the @Checkpoint
+func (cp *Checkpoint) Close() {
+	runtime.SetFinalizer(cp, nil)
+	cp.finalize()
+}
+
+// Creates a Checkpoint object to be used for creating openable snapshots
+// of the db.
+func NewCheckpoint(db *DB) (cp *Checkpoint, stat *Status) {
+	if db.closed {
+		stat = NewDBClosedStatus()
+		return
+	}
+
+	cp = &Checkpoint{}
+	cstat := C.CheckpointCreate(&db.db, &cp.cp)
+	stat = cstat.toStatus()
+	if stat.Ok() {
+		runtime.SetFinalizer(cp, finalize)
+	} else {
+		cp = nil
+	}
+	return
+}
+
+// Builds an openable snapshot of RocksDB on the same disk, which
+// accepts an output directory on the same disk, and under the directory
+// (1) hard-linked SST files pointing to existing live SST files
+// SST files will be copied if output directory is on a different filesystem
+// (2) a copied manifest files and other files
+// The directory should not already exist and will be created by this API.
+// The directory will be an absolute path
+func (cp *Checkpoint) CreateCheckpoint(dir *string) (stat *Status) {
+	if cp.closed {
+		stat = NewDBClosedStatus()
+		return
+	}
+
+	cdir := newCStringFromString(dir)
+	defer cdir.del()
+	cstat := C.CheckpointCreateCheckpoint(&cp.cp, &cdir.str)
+	stat = cstat.toStatus()
+	return
+}
@@ -0,0 +1,36 @@
+// Copyright (c) 2015, Dean ChaoJun Pan.  All rights reserved.
+// This source code is licensed under the BSD-style license found in the
+// LICENSE file in the root directory of this source tree.
+
+#ifndef GO_ROCKSDB_INCLUDE_CHECKPOINT_H_
+#define GO_ROCKSDB_INCLUDE_CHECKPOINT_H_
+
+#include "types.h"
+#include "db.h"
+
+#ifdef __cplusplus
+extern "C" {
+#endif
+
+// A checkpoint is an openable snapshot of a database at a point in time.
+DEFINE_C_WRAP_STRUCT(Checkpoint)
+DEFINE_C_WRAP_CONSTRUCTOR_DEC(Checkpoint)
+DEFINE_C_WRAP_DESTRUCTOR_DEC(Checkpoint)
+
+// Creates a Checkpoint object to be used for creating openable snapshots
+Status_t CheckpointCreate(DB_t* db, Checkpoint_t* checkpoint_ptr);
+
+// Builds an openable snapshot of RocksDB on the same disk, which
+// accepts an output directory on the same disk, and under the directory
+// (1) hard-linked SST files pointing to existing live SST files
+// SST files will be copied if output directory is on a different filesystem
+// (2) a copied manifest files and other files
+// The directory should not already exist and will be created by this API.
+// The directory will be an absolute path
+Status_t CheckpointCreateCheckpoint(Checkpoint_t* checkpoint_ptr, const String_t* checkpoint_dir);
+
+#ifdef __cplusplus
+}
+#endif
+
+#endif // GO_ROCKSDB_INCLUDE_CHECKPOINT_H_
@@ -130,6 +130,28 @@ Comparator_t NewComparator(void* go_cmp)
     return wrap_t;
 }
 
+// Three-way comparison of a and b by the comparator cmp.
+// The byte-wise ordering is used if cmp is NULL.
+int ComparatorCompare(const Comparator_t* cmp, const Slice_t* a, const Slice_t* b)
+{
+    assert(GET_REP(a, Slice) != NULL);
+    assert(GET_REP(b, Slice) != NULL);
+    const Comparator* rep = ((cmp && GET_REP(cmp, Comparator)) ?
+                             GET_REP(cmp, Comparator) :
+                             BytewiseComparator());
+    return rep->Compare(GET_REP_REF(a, Slice), GET_REP_REF(b, Slice));
+}
+
+// The name of the comparator cmp.
+String_t ComparatorName(const Comparator_t* cmp)
+{
+    const Comparator* rep = ((cmp && GET_REP(cmp, Comparator)) ?
+                             GET_REP(cmp, Comparator) :
+                             BytewiseComparator());
+    String ret(rep->Name());
+    return NewStringTCopy(&ret);
+}
+
 // Return a builtin comparator that uses lexicographic byte-wise
 // ordering.  The result remains the property of this module and
 // must not be deleted.
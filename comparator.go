@@ -93,6 +93,8 @@ func IComparatorFindShortSuccessor(ccmp unsafe.Pointer, key *C.String_t, sz *C.s
 // Wrap go Comparator
 type Comparator struct {
 	cmp C.Comparator_t
+	// The go IComparator, nil if the comparator is implemented in c++
+	itf IComparator
 	// True if the Comparator is closed
 	closed bool
 }
@@ -129,7 +131,27 @@ func NewComparator(itf IComparator) (cmp *Comparator) {
 		citf = InterfacesAddReference(itf)
 	}
 	ccmp := C.NewComparator(citf)
-	return ccmp.toComparator(true)
+	cmp = ccmp.toComparator(true)
+	cmp.itf = itf
+	return
+}
+
+// Three-way comparison of a and b by the comparator. Returns value:
+//   < 0 iff "a" < "b",
+//   == 0 iff "a" == "b",
+//   > 0 iff "a" > "b"
+func (cmp *Comparator) Compare(a, b []byte) int {
+	ca := newSliceFromBytes(a)
+	defer ca.del()
+	cb := newSliceFromBytes(b)
+	defer cb.del()
+	return int(C.ComparatorCompare(&cmp.cmp, &ca.slc, &cb.slc))
+}
+
+// The name of the comparator.
+func (cmp *Comparator) Name() string {
+	cname := C.ComparatorName(&cmp.cmp)
+	return cname.cToString()
 }
 
 // Return a new default Comparator
@@ -20,6 +20,12 @@ DEFINE_C_WRAP_DESTRUCTOR_DEC(Comparator)
 // Return a Comparator from a go Comparator interface
 Comparator_t NewComparator(void* go_cmp);
 
+// Three-way comparison of a and b by the comparator cmp.
+// The byte-wise ordering is used if cmp is NULL.
+int ComparatorCompare(const Comparator_t* cmp, const Slice_t* a, const Slice_t* b);
+// The name of the comparator cmp.
+String_t ComparatorName(const Comparator_t* cmp);
+
 Comparator_t GoBytewiseComparator();
 Comparator_t GoReverseBytewiseComparator();
 
@@ -10,10 +10,6 @@
 
 using namespace rocksdb;
 
-DEFINE_C_WRAP_CONSTRUCTOR(TablePropertiesCollection)
-DEFINE_C_WRAP_CONSTRUCTOR_DEFAULT(TablePropertiesCollection)
-DEFINE_C_WRAP_DESTRUCTOR(TablePropertiesCollection)
-
 // A range of keys
 DEFINE_C_WRAP_CONSTRUCTOR(Range)
 DEFINE_C_WRAP_CONSTRUCTOR_ARGS(Range, Slice, Slice)
@@ -1023,10 +1019,13 @@ Status_t DBGetUpdatesSince(const DB_t* dbptr, SequenceNumber seq_number,
     {
         unique_ptr<TransactionLogIterator> iter_ptr;
         assert(GET_REP(dbptr, DB) != NULL);
-        assert(GET_REP(read_options, TransactionLogIterator_ReadOptions) != NULL);
-        ret = GET_REP(dbptr, DB)->GetUpdatesSince(seq_number, &iter_ptr, GET_REP_REF(read_options, TransactionLogIterator_ReadOptions));
+        ret = GET_REP(dbptr, DB)->GetUpdatesSince(seq_number, &iter_ptr,
+                                                  (read_options && GET_REP(read_options, TransactionLogIterator_ReadOptions)) ?
+                                                  GET_REP_REF(read_options, TransactionLogIterator_ReadOptions) :
+                                                  TransactionLogIterator::ReadOptions());
         iter->rep = iter_ptr.release();
     }
+    else
     {
         ret = invalid_status;
     }
@@ -1139,6 +1138,40 @@ Status_t DBGetPropertiesOfAllTables(const DB_t* dbptr,
     const ColumnFamilyHandle_t column_family = DBDefaultColumnFamily(dbptr);
     return DBGetPropertiesOfAllTablesWithColumnFamily(dbptr, &column_family, props);
 }
+
+// Load table file located at "file_path" into "column_family", a pointer to
+// ExternalSstFileInfo can be used instead of "file_path" to do a blind add
+// that wont need to read the file, move_file can be set to true to
+// move the file instead of copying it.
+//
+// Current Requirements:
+// (1) Key range in loaded table file don't overlap with
+//     existing keys or tombstones in DB.
+// (2) No other writes happen during AddFile call, otherwise
+//     DB may get corrupted.
+// (3) No snapshots are held.
+Status_t DBAddFileWithColumnFamily(const DB_t* dbptr, 
+                                   const ColumnFamilyHandle_t* column_family,
+                                   const String_t* file_path,
+                                   bool move_file)
+{
+    assert(dbptr != NULL);
+    assert(GET_REP(dbptr, DB) != NULL);
+    assert(GET_REP(column_family, ColumnFamilyHandle) != NULL);
+    assert(GET_REP(file_path, String) != NULL);
+    Status stat = GET_REP(dbptr, DB)->AddFile(GET_REP(column_family, ColumnFamilyHandle), GET_REP_REF(file_path, String), move_file);
+    return NewStatusTCopy(dbptr ?
+                          &stat :
+                          const_cast<Status*>(&invalid_status));
+}
+
+Status_t DBAddFile(const DB_t* dbptr, 
+                   const String_t* file_path,
+                   bool move_file)
+{
+    const ColumnFamilyHandle_t column_family = DBDefaultColumnFamily(dbptr);
+    return DBAddFileWithColumnFamily(dbptr, &column_family, file_path, move_file);
+}
 #endif  // ROCKSDB_LITE
 
 // Destroy the contents of the specified database.
@@ -21,21 +21,6 @@ const (
 	initialMapSize int = 20
 )
 
-type TablePropertiesCollection struct {
-	tpc C.TablePropertiesCollection_t
-}
-
-func (tpc *TablePropertiesCollection) finalize() {
-	var ctpc *C.TablePropertiesCollection_t = &tpc.tpc
-	C.DeleteTablePropertiesCollectionT(ctpc, toCBool(false))
-}
-
-func (ctpc *C.TablePropertiesCollection_t) toTablePropertiesCollection() (tpc *TablePropertiesCollection) {
-	tpc = &TablePropertiesCollection{tpc: *ctpc}	
-	runtime.SetFinalizer(tpc, finalize)
-	return
-}
-
 // A range of keys
 type Range struct {
 	startSlc *cSlice
@@ -170,11 +155,25 @@ type DB struct {
 	cfhmapmtx sync.Mutex
 	// Mutext to protect itmap
 	itmapmtx sync.Mutex
+	// Map of Transactions to close before the db is closed
+	txnmap map[*Transaction]bool
+	// Mutext to protect txnmap
+	txnmapmtx sync.Mutex
+	// Map of the running subscriptions to stop before the db is closed
+	submap map[*subscription]bool
+	// No more subscription can be added
+	substopped bool
+	// Mutext to protect submap and substopped
+	submapmtx sync.Mutex
+	// Release the C++ object owning db instead of deleting db if not nil
+	release func()
+	// Keep the env of the options from garbage collected
+	env *Env
 }
 
-// Return a default DB
-func newDB() (db *DB) {
-	db = &DB{cfhmapmtx: sync.Mutex{}, itmapmtx: sync.Mutex{}}
+// Return a default DB to open with options
+func newDB(options *DBOptions) (db *DB) {
+	db = &DB{cfhmapmtx: sync.Mutex{}, itmapmtx: sync.Mutex{}, env: options.env}
 	return 
 }
 
@@ -212,9 +211,75 @@ func (db *DB) removeFromItmap(it *Iterator) {
 	delete(db.itmap, it)
 }
 
+// Add txn to txnmap
+func (db *DB) addToTxnmap(txn *Transaction) {
+	defer db.txnmapmtx.Unlock()
+	db.txnmapmtx.Lock()
+	if nil == db.txnmap {
+		db.txnmap = make(map[*Transaction]bool, initialMapSize)
+	}
+	db.txnmap[txn] = true
+}
+
+// Remove txn from txnmap
+func (db *DB) removeFromTxnmap(txn *Transaction) {
+	defer db.txnmapmtx.Unlock()
+	db.txnmapmtx.Lock()
+	delete(db.txnmap, txn)
+}
+
+// A running subscription of the db
+type subscription struct {
+	// Stop the subscription
+	cancel func()
+	// Closed when the subscription has stopped
+	done chan struct{}
+}
+
+// Add sub to submap. Returns false if the db is being closed.
+func (db *DB) addToSubmap(sub *subscription) bool {
+	defer db.submapmtx.Unlock()
+	db.submapmtx.Lock()
+	if db.substopped {
+		return false
+	}
+	if nil == db.submap {
+		db.submap = make(map[*subscription]bool, initialMapSize)
+	}
+	db.submap[sub] = true
+	return true
+}
+
+// Remove sub from submap
+func (db *DB) removeFromSubmap(sub *subscription) {
+	defer db.submapmtx.Unlock()
+	db.submapmtx.Lock()
+	delete(db.submap, sub)
+}
+
+// Stop all the subscriptions and wait for them to stop
+func (db *DB) stopSubscriptions() {
+	db.submapmtx.Lock()
+	db.substopped = true
+	subs := make([]*subscription, 0, len(db.submap))
+	for k, _ := range db.submap {
+		subs = append(subs, k)
+	}
+	db.submapmtx.Unlock()
+
+	for _, sub := range subs {
+		sub.cancel()
+	}
+	for _, sub := range subs {
+		<-sub.done
+	}
+}
+
 // Release the @db
 func (db *DB) finalize() {
 	if !db.closed {
+		// Stop the subscriptions before the db they read is released
+		db.stopSubscriptions()
 		db.closed = true
 
 		// Close all the opened ColumnFamilyHandles 
@@ -227,6 +292,16 @@ func (db *DB) finalize() {
 			k.Close()
 		}
 
+		// Close all the opened Transactions
+		for k, _ := range db.txnmap {
+			k.Close()
+		}
+
+		if nil != db.release {
+			db.release()
+			return
+		}
+
 		var cdb *C.DB_t = &db.db
 		C.DeleteDBT(cdb, toCBool(false))
 	}
@@ -257,7 +332,7 @@ func (db *DB) Close() {
 // as column_families --- handles[i] will be a handle that you
 // will use to operate on column family column_family[i]
 func Open(options *Options, name *string, cfds ...*ColumnFamilyDescriptor) (db *DB, stat *Status, cfhs []*ColumnFamilyHandle) {
-	db = newDB()
+	db = newDB(&options.DBOptions)
 	rstr := newCStringFromString(name)
 	defer rstr.del()
 
@@ -314,7 +389,7 @@ func Open(options *Options, name *string, cfds ...*ColumnFamilyDescriptor) (db *
 // Not supported in ROCKSDB_LITE, in which case the function will
 // return Status_t::NotSupported.
 func OpenForReadOnly(options *Options, name *string, cfds ...interface{}) (db *DB, cfhs []*ColumnFamilyHandle, stat *Status) {
-	db = newDB()
+	db = newDB(&options.DBOptions)
 	rstr := newCStringFromString(name)
 	defer rstr.del()
 
@@ -17,16 +17,12 @@
 #include "transaction_log.h"
 #include "snapshot.h"
 #include "columnFamilyHandle.h"
+#include "table_properties.h"
 
 #ifdef __cplusplus
 extern "C" {
 #endif
 
-DEFINE_C_WRAP_STRUCT(TablePropertiesCollection)
-DEFINE_C_WRAP_CONSTRUCTOR_DEC(TablePropertiesCollection)
-DEFINE_C_WRAP_CONSTRUCTOR_DEFAULT_DEC(TablePropertiesCollection)
-DEFINE_C_WRAP_DESTRUCTOR_DEC(TablePropertiesCollection)
-
 DEFINE_C_WRAP_STRUCT(ColumnFamilyDescriptor)
 DEFINE_C_WRAP_CONSTRUCTOR_DEFAULT_DEC(ColumnFamilyDescriptor)
 DEFINE_C_WRAP_CONSTRUCTOR_ARGS_DEC(ColumnFamilyDescriptor, String, ColumnFamilyOptions)
@@ -204,6 +200,13 @@ Status_t DBGetPropertiesOfAllTablesWithColumnFamily(const DB_t* dbptr,
                                                     TablePropertiesCollection_t* props);
 Status_t DBGetPropertiesOfAllTables(const DB_t* dbptr, 
                                     TablePropertiesCollection_t* props);
+Status_t DBAddFileWithColumnFamily(const DB_t* dbptr, 
+                                   const ColumnFamilyHandle_t* column_family,
+                                   const String_t* file_path,
+                                   bool move_file);
+Status_t DBAddFile(const DB_t* dbptr, 
+                   const String_t* file_path,
+                   bool move_file);
 Status_t DBDestroyDB(const String_t* name, const Options_t* options);
 Status_t DBRepairDB(const String_t* dbname, const Options_t* options);
 
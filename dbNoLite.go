@@ -128,7 +128,7 @@ func (db *DB) GetSortedWalFiles() (files []*LogFile, stat *Status) {
 // use this api, else the WAL files will get
 // cleared aggressively and the iterator might keep getting invalid before
 // an update is read.
-func (db *DB) GetUpdatesSince(sqn SequenceNumber, tranropt ...TransactionLogIteratorReadOptions) (it *TransactionLogIterator, stat *Status) {
+func (db *DB) GetUpdatesSince(sqn SequenceNumber, tranropt ...*TransactionLogIteratorReadOptions) (it *TransactionLogIterator, stat *Status) {
 	if db.closed {
 		stat = NewDBClosedStatus()
 		return
@@ -146,7 +146,9 @@ func (db *DB) GetUpdatesSince(sqn SequenceNumber, tranropt ...TransactionLogIter
 
 	cstat := C.DBGetUpdatesSince(cdb, C.SequenceNumber(sqn), &cit, ctranropt)
 	stat = cstat.toStatus()
-	it = cit.toTransactionLogIterator()
+	if stat.Ok() {
+		it = cit.toTransactionLogIterator()
+	}
 	return
 }
 
@@ -196,7 +198,7 @@ func (db *DB) GetColumnFamilyMetaData(cfh ...*ColumnFamilyHandle) (md *ColumnFam
 	var (
 		cdb *C.DB_t = &db.db
 		ccfh *C.ColumnFamilyHandle_t
-		ccfmd C.ColumnFamilyMetaData_t
+		ccfmd C.ColumnFamilyMetaData_t = C.NewColumnFamilyMetaDataTDefault()
 	)
 
 	if cfh != nil {
@@ -208,11 +210,13 @@ func (db *DB) GetColumnFamilyMetaData(cfh ...*ColumnFamilyHandle) (md *ColumnFam
 	} else {
 		C.DBGetColumnFamilyMetaData(cdb, &ccfmd)
 	}
-	md = ccfmd.toColumnFamilyMetaData()
+	md = ccfmd.toColumnFamilyMetaData(true)
 	return
 }
 
-func (db *DB) GetPropertiesOfAllTables(cfh ...*ColumnFamilyHandle) (tpc *TablePropertiesCollection, stat *Status) {
+// Returns the properties of all the tables of the column family cfh
+// (default column family if not specified) keyed by the file name.
+func (db *DB) GetPropertiesOfAllTables(cfh ...*ColumnFamilyHandle) (tpc TablePropertiesCollection, stat *Status) {
 	if db.closed {
 		stat = NewDBClosedStatus()
 		return
@@ -221,7 +225,7 @@ func (db *DB) GetPropertiesOfAllTables(cfh ...*ColumnFamilyHandle) (tpc *TablePr
 	var (
 		cdb *C.DB_t = &db.db
 		ccfh *C.ColumnFamilyHandle_t
-		ctpc C.TablePropertiesCollection_t
+		ctpc C.TablePropertiesCollection_t = C.NewTablePropertiesCollectionTDefault()
 	)
 
 	if cfh != nil {
@@ -235,7 +239,46 @@ func (db *DB) GetPropertiesOfAllTables(cfh ...*ColumnFamilyHandle) (tpc *TablePr
 		cstat = C.DBGetPropertiesOfAllTables(cdb, &ctpc)
 	}
 	stat = cstat.toStatus()
-	tpc = ctpc.toTablePropertiesCollection()
+	tpc = ctpc.toTablePropertiesCollection(true)
+	return
+}
+
+// Load the table file located at filePath into the column family cfh
+// (default column family if not specified). The file is moved instead of
+// copied if moveFile is true.
+//
+// Current Requirements:
+// (1) Key range in loaded table file don't overlap with
+//     existing keys or tombstones in DB.
+// (2) No other writes happen during AddFile call, otherwise
+//     DB may get corrupted.
+// (3) No snapshots are held.
+func (db *DB) AddFile(filePath *string, moveFile bool, cfh ...*ColumnFamilyHandle) (stat *Status) {
+	if db.closed {
+		stat = NewDBClosedStatus()
+		return
+	}
+
+	cpath := newCStringFromString(filePath)
+	defer cpath.del()
+
+	var (
+		cdb *C.DB_t = &db.db
+		ccfh *C.ColumnFamilyHandle_t
+		ccpath *C.String_t = &cpath.str
+	)
+
+	if cfh != nil {
+		ccfh = &cfh[0].cfh
+	}
+
+	var cstat C.Status_t
+	if ccfh != nil {
+		cstat = C.DBAddFileWithColumnFamily(cdb, ccfh, ccpath, toCBool(moveFile))
+	} else {
+		cstat = C.DBAddFile(cdb, ccpath, toCBool(moveFile))
+	}
+	stat = cstat.toStatus()
 	return
 }
 
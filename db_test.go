@@ -6,9 +6,15 @@
 package rocksdb
 
 import (
+	"context"
+	"time"
 	"os"
 	"fmt"
 	"bytes"
+	"io"
+	"runtime"
+	"sync"
+	"sync/atomic"
 	"testing"
 )
 
@@ -100,6 +106,8 @@ func (tcf *testComparator) FindShortSuccessor(key []byte) []byte {
 // Custom Merge Operator
 type testMergeOperator struct {
 	t *testing.T
+	// The info log level seen by FullMerge, -1 if not called
+	logLevel int32
 }
 
 func (tcf *testMergeOperator) Name() string {
@@ -108,6 +116,8 @@ func (tcf *testMergeOperator) Name() string {
 	
 func (tcf *testMergeOperator) FullMerge(key []byte, exval []byte, opdlist [][]byte, logger *Logger) (suc bool, newval []byte) {
 	// tcf.t.Logf("testMergeOperator::FullMerge key = %s, exval = %s, opdlist = %s", key, exval, opdlist)
+	atomic.StoreInt32(&tcf.logLevel, int32(logger.GetInfoLogLevel()))
+	logger.Warn("testMergeOperator::FullMerge key = %s", key)
 	newval = []byte("fake")
 	suc = true
 	return
@@ -232,6 +242,320 @@ func (tstf *testSliceTransform) SameResultWhenAppended(prefix []byte) bool {
 	return false
 }
 
+// Custom WriteBatch handler
+type testWriteBatchHandler struct {
+	t *testing.T
+	pos int
+}
+
+func (twbh *testWriteBatchHandler) Put(key, val []byte) {
+	switch twbh.pos {
+	case 0:
+		checkCondition(twbh.t, bytes.Equal(key, []byte("bar")))
+		checkCondition(twbh.t, bytes.Equal(val, []byte("b")))
+	case 1:
+		checkCondition(twbh.t, bytes.Equal(key, []byte("box")))
+		checkCondition(twbh.t, bytes.Equal(val, []byte("c")))
+	default:
+		checkCondition(twbh.t, false)
+	}
+	twbh.pos++
+}
+
+func (twbh *testWriteBatchHandler) PutCF(cfid uint32, key, val []byte) {
+	checkCondition(twbh.t, false)
+}
+
+func (twbh *testWriteBatchHandler) Merge(key, val []byte) {
+	checkCondition(twbh.t, false)
+}
+
+func (twbh *testWriteBatchHandler) MergeCF(cfid uint32, key, val []byte) {
+	checkCondition(twbh.t, false)
+}
+
+func (twbh *testWriteBatchHandler) Delete(key []byte) {
+	checkCondition(twbh.t, twbh.pos == 2)
+	checkCondition(twbh.t, bytes.Equal(key, []byte("bar")))
+	twbh.pos++
+}
+
+func (twbh *testWriteBatchHandler) DeleteCF(cfid uint32, key []byte) {
+	checkCondition(twbh.t, false)
+}
+
+func (twbh *testWriteBatchHandler) LogData(blob []byte) {
+}
+
+// Custom TablePropertiesCollector counting the keys of a table
+type testTablePropertiesCollector struct {
+	count int
+}
+
+func (ttpc *testTablePropertiesCollector) Name() string {
+	return "testTablePropertiesCollector"
+}
+
+func (ttpc *testTablePropertiesCollector) Add(key, value []byte) {
+	ttpc.count++
+}
+
+func (ttpc *testTablePropertiesCollector) Finish() map[string]string {
+	return map[string]string{"test.count": fmt.Sprintf("%d", ttpc.count)}
+}
+
+// Custom TablePropertiesCollectorFactory
+type testTablePropertiesCollectorFactory struct {
+}
+
+func (ttpcf *testTablePropertiesCollectorFactory) Name() string {
+	return "testTablePropertiesCollectorFactory"
+}
+
+func (ttpcf *testTablePropertiesCollectorFactory) CreateTablePropertiesCollector(cfid uint32) ITablePropertiesCollector {
+	return &testTablePropertiesCollector{}
+}
+
+// Custom TablePropertiesCollectorFactory creating no collector
+type testNilTablePropertiesCollectorFactory struct {
+}
+
+func (ttpcf *testNilTablePropertiesCollectorFactory) Name() string {
+	return "testNilTablePropertiesCollectorFactory"
+}
+
+func (ttpcf *testNilTablePropertiesCollectorFactory) CreateTablePropertiesCollector(cfid uint32) ITablePropertiesCollector {
+	return nil
+}
+
+// Custom EventListener counting the events
+type testEventListener struct {
+	EventListener
+	flushes int32
+	compactions int32
+	compactedFiles int32
+	created int32
+	deleted int32
+}
+
+func (tel *testEventListener) OnFlushCompleted(info *FlushJobInfo) {
+	if info.FilePath != "" {
+		atomic.AddInt32(&tel.flushes, 1)
+	}
+}
+
+func (tel *testEventListener) OnCompactionCompleted(info *CompactionJobInfo) {
+	if info.Status.Ok() {
+		atomic.AddInt32(&tel.compactedFiles, int32(len(info.InputFiles)))
+		atomic.AddInt32(&tel.compactions, 1)
+	}
+}
+
+func (tel *testEventListener) OnTableFileCreated(info *TableFileCreationInfo) {
+	if info.FileSize > 0 {
+		atomic.AddInt32(&tel.created, 1)
+	}
+}
+
+func (tel *testEventListener) OnTableFileDeleted(info *TableFileDeletionInfo) {
+	atomic.AddInt32(&tel.deleted, 1)
+}
+
+// Wait until the counter of the event becomes positive as the
+// call-backs may run after the triggering call has returned.
+func waitForEvent(counter *int32) bool {
+	for i := 0; i < 100; i++ {
+		if atomic.LoadInt32(counter) > 0 {
+			return true
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return false
+}
+
+// Convert the go error to a status
+func testEnvStatus(err error) *Status {
+	if nil == err {
+		return nil
+	} else if os.IsNotExist(err) {
+		return NewNotFoundStatus(err.Error())
+	}
+	return NewIOErrorStatus(err.Error())
+}
+
+// Custom Env on the os package counting the bytes written
+type testEnv struct {
+	written int64
+	locks map[string]bool
+	locksmtx sync.Mutex
+}
+
+type testEnvFile struct {
+	env *testEnv
+	f *os.File
+}
+
+func (tef *testEnvFile) Read(buf []byte) (int, *Status) {
+	n, err := io.ReadFull(tef.f, buf)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		err = nil
+	}
+	return n, testEnvStatus(err)
+}
+
+func (tef *testEnvFile) Skip(n uint64) *Status {
+	_, err := tef.f.Seek(int64(n), io.SeekCurrent)
+	return testEnvStatus(err)
+}
+
+func (tef *testEnvFile) ReadAt(buf []byte, offset uint64) (int, *Status) {
+	n, err := tef.f.ReadAt(buf, int64(offset))
+	if err == io.EOF {
+		err = nil
+	}
+	return n, testEnvStatus(err)
+}
+
+func (tef *testEnvFile) Append(data []byte) *Status {
+	n, err := tef.f.Write(data)
+	atomic.AddInt64(&tef.env.written, int64(n))
+	return testEnvStatus(err)
+}
+
+func (tef *testEnvFile) Close() *Status {
+	return testEnvStatus(tef.f.Close())
+}
+
+func (tef *testEnvFile) Flush() *Status {
+	return nil
+}
+
+func (tef *testEnvFile) Sync() *Status {
+	return testEnvStatus(tef.f.Sync())
+}
+
+func (tef *testEnvFile) Fsync() *Status {
+	return testEnvStatus(tef.f.Sync())
+}
+
+func (te *testEnv) openFile(name string, flag int) (*testEnvFile, *Status) {
+	f, err := os.OpenFile(name, flag, 0644)
+	if nil != err {
+		return nil, testEnvStatus(err)
+	}
+	return &testEnvFile{env: te, f: f}, nil
+}
+
+func (te *testEnv) NewSequentialFile(name string) (ISequentialFile, *Status) {
+	return te.openFile(name, os.O_RDONLY)
+}
+
+func (te *testEnv) NewRandomAccessFile(name string) (IRandomAccessFile, *Status) {
+	return te.openFile(name, os.O_RDONLY)
+}
+
+func (te *testEnv) NewWritableFile(name string) (IWritableFile, *Status) {
+	return te.openFile(name, os.O_WRONLY|os.O_CREATE|os.O_TRUNC)
+}
+
+func (te *testEnv) NewDirectory(name string) (IDirectory, *Status) {
+	return te.openFile(name, os.O_RDONLY)
+}
+
+func (te *testEnv) FileExists(name string) *Status {
+	_, err := os.Stat(name)
+	return testEnvStatus(err)
+}
+
+func (te *testEnv) GetChildren(dir string) ([]string, *Status) {
+	f, err := os.Open(dir)
+	if nil != err {
+		return nil, testEnvStatus(err)
+	}
+	defer f.Close()
+	names, err := f.Readdirnames(-1)
+	return names, testEnvStatus(err)
+}
+
+func (te *testEnv) DeleteFile(name string) *Status {
+	return testEnvStatus(os.Remove(name))
+}
+
+func (te *testEnv) CreateDir(name string) *Status {
+	return testEnvStatus(os.Mkdir(name, 0755))
+}
+
+func (te *testEnv) CreateDirIfMissing(name string) *Status {
+	if err := os.Mkdir(name, 0755); nil != err && !os.IsExist(err) {
+		return testEnvStatus(err)
+	}
+	return nil
+}
+
+func (te *testEnv) DeleteDir(name string) *Status {
+	return testEnvStatus(os.Remove(name))
+}
+
+func (te *testEnv) GetFileSize(name string) (uint64, *Status) {
+	fi, err := os.Stat(name)
+	if nil != err {
+		return 0, testEnvStatus(err)
+	}
+	return uint64(fi.Size()), nil
+}
+
+func (te *testEnv) GetFileModificationTime(name string) (uint64, *Status) {
+	fi, err := os.Stat(name)
+	if nil != err {
+		return 0, testEnvStatus(err)
+	}
+	return uint64(fi.ModTime().Unix()), nil
+}
+
+func (te *testEnv) RenameFile(src, target string) *Status {
+	return testEnvStatus(os.Rename(src, target))
+}
+
+func (te *testEnv) LinkFile(src, target string) *Status {
+	return testEnvStatus(os.Link(src, target))
+}
+
+func (te *testEnv) LockFile(name string) (interface{}, *Status) {
+	defer te.locksmtx.Unlock()
+	te.locksmtx.Lock()
+	if te.locks[name] {
+		return nil, NewIOErrorStatus("lock " + name + ": already held")
+	}
+	te.locks[name] = true
+	return name, nil
+}
+
+func (te *testEnv) UnlockFile(lock interface{}) *Status {
+	defer te.locksmtx.Unlock()
+	te.locksmtx.Lock()
+	delete(te.locks, lock.(string))
+	return nil
+}
+
+func (te *testEnv) NowMicros() uint64 {
+	return uint64(time.Now().UnixNano() / 1000)
+}
+
+func (te *testEnv) NowNanos() uint64 {
+	return uint64(time.Now().UnixNano())
+}
+
+// Custom ILogger counting the log lines by level
+type testLogger struct {
+	lines [NUM_INFO_LOG_LEVELS]int32
+}
+
+func (tl *testLogger) Logv(level int, msg string) {
+	if level >= 0 && level < NUM_INFO_LOG_LEVELS && msg != "" {
+		atomic.AddInt32(&tl.lines[level], 1)
+	}
+}
+
 // Test from rocksdb's c_test.c.
 func TestCMain(t *testing.T) {
 	var (
@@ -241,6 +565,7 @@ func TestCMain(t *testing.T) {
 
 	dbname := fmt.Sprintf("%s/rocksdb_go_test-%d", os.TempDir(), os.Geteuid);
 	dbbackupname := fmt.Sprintf("%s/rocksdb_go_test-%d-backup", os.TempDir(), os.Geteuid);
+	dbcheckpointname := fmt.Sprintf("%s/rocksdb_go_test-%d-checkpoint", os.TempDir(), os.Geteuid());
 	fmt.Printf("rocksdbgo version = %d.%d\n", majorVersionGo, minorVersionGo)
 	fmt.Printf("rocksdb version = %d.%d\n", majorVersion, minorVersion)
 	t.Log("phase: create_objects")
@@ -255,6 +580,8 @@ func TestCMain(t *testing.T) {
 	options := NewOptions()
 
 	options.SetComparator(cmp)
+	options.AddTablePropertiesCollectorFactory(NewTablePropertiesCollectorFactory(&testTablePropertiesCollectorFactory{}))
+	options.AddTablePropertiesCollectorFactory(NewTablePropertiesCollectorFactory(&testNilTablePropertiesCollectorFactory{}))
 	options.SetErrorIfExists(true)
 	options.SetEnv(env)
 	options.SetInfoLog(NewPLoggerDefault())
@@ -320,6 +647,45 @@ func TestCMain(t *testing.T) {
 	if !stat.Ok() {
 		t.Fatalf("backup_and_restore: CreateNewBackup: status = %s", stat)
 	}
+	binfos := be.GetBackupInfo()
+	checkCondition(t, len(binfos) == 1)
+	checkCondition(t, binfos[0].NumberFiles > 0)
+	checkCondition(t, binfos[0].Size > 0)
+	stat = be.VerifyBackup(binfos[0].ID)
+	if !stat.Ok() {
+		t.Fatalf("backup_and_restore: VerifyBackup: status = %s", stat)
+	}
+	checkCondition(t, len(be.GetCorruptedBackups()) == 0)
+	stat = be.CreateNewBackup(db)
+	if !stat.Ok() {
+		t.Fatalf("backup_and_restore: CreateNewBackup: status = %s", stat)
+	}
+	binfos = be.GetBackupInfo()
+	checkCondition(t, len(binfos) == 2)
+	latestid := binfos[1].ID
+	checkCondition(t, latestid != binfos[0].ID)
+	stat = be.PurgeOldBackups(1)
+	if !stat.Ok() {
+		t.Fatalf("backup_and_restore: PurgeOldBackups: status = %s", stat)
+	}
+	// Only the latest backup is kept
+	binfos = be.GetBackupInfo()
+	checkCondition(t, len(binfos) == 1 && binfos[0].ID == latestid)
+	stat = be.CreateNewBackup(db)
+	if !stat.Ok() {
+		t.Fatalf("backup_and_restore: CreateNewBackup: status = %s", stat)
+	}
+	checkCondition(t, len(be.GetBackupInfo()) == 2)
+	stat = be.DeleteBackup(latestid)
+	if !stat.Ok() {
+		t.Fatalf("backup_and_restore: DeleteBackup: status = %s", stat)
+	}
+	binfos = be.GetBackupInfo()
+	checkCondition(t, len(binfos) == 1 && binfos[0].ID != latestid)
+	stat = be.GarbageCollect()
+	if !stat.Ok() {
+		t.Fatalf("backup_and_restore: GarbageCollect: status = %s", stat)
+	}
 	stat = db.Delete(woptions, []byte("foo"))
 	if !stat.Ok() {
 		t.Fatalf("backup_and_restore: Delete: status = %s", stat)
@@ -345,6 +711,81 @@ func TestCMain(t *testing.T) {
 	db.checkGet(t, ropts, []byte("foo"), []byte("hello"))
 	be.Close()
 
+	t.Log("phase: backup_engine_read_only")
+	{
+		bdbopts := NewBackupableDBOptions(&dbbackupname)
+		bdbopts.SetShareTableFiles(false)
+		bdbopts.SetSync(false)
+		bdbopts.SetDestroyOldData(true)
+		bdbopts.SetBackupLogFiles(false)
+		bdbopts.SetBackupRateLimit(1 << 20)
+		bdbopts.SetRestoreRateLimit(2 << 20)
+		bdbopts.SetShareFilesWithChecksum(true)
+		bdbopts.SetMaxBackgroundOperations(2)
+		checkCondition(t, !bdbopts.ShareTableFiles())
+		checkCondition(t, !bdbopts.Sync())
+		checkCondition(t, bdbopts.DestroyOldData())
+		checkCondition(t, !bdbopts.BackupLogFiles())
+		checkCondition(t, bdbopts.BackupRateLimit() == 1 << 20)
+		checkCondition(t, bdbopts.RestoreRateLimit() == 2 << 20)
+		checkCondition(t, bdbopts.ShareFilesWithChecksum())
+		checkCondition(t, bdbopts.MaxBackgroundOperations() == 2)
+		bdbopts.Close()
+
+		bdbopts = NewBackupableDBOptions(&dbbackupname)
+		bero, stat := BackupEngineReadOnlyOpen(options.Env(), bdbopts)
+		if !stat.Ok() {
+			t.Fatalf("backup_engine_read_only: BackupEngineReadOnlyOpen: status = %s", stat)
+		}
+		checkCondition(t, len(bero.GetBackupInfo()) == 1)
+		checkCondition(t, len(bero.GetCorruptedBackups()) == 0)
+		dbrestorename := dbname + "-restore"
+		restore_options := NewRestoreOptions()
+		stat = bero.RestoreDBFromLatestBackup(&dbrestorename, &dbrestorename, restore_options)
+		if !stat.Ok() {
+			t.Fatalf("backup_engine_read_only: RestoreDBFromLatestBackup: status = %s", stat)
+		}
+		restore_options.Close()
+		bero.Close()
+		bdbopts.Close()
+		options.SetErrorIfExists(false);
+		rodb, stat, _ := Open(options, &dbrestorename)
+		if !stat.Ok() {
+			t.Fatalf("backup_engine_read_only: open: stat = %s", stat)
+		}
+		options.SetErrorIfExists(true);
+		rodb.checkGet(t, ropts, []byte("foo"), []byte("hello"))
+		rodb.Close()
+		stat = DestroyDB(options, &dbrestorename)
+		if !stat.Ok() {
+			t.Fatalf("backup_engine_read_only: DestroyDB: status = %s", stat)
+		}
+	}
+
+	t.Log("phase: checkpoint")
+	os.RemoveAll(dbcheckpointname)
+	cp, stat := NewCheckpoint(db)
+	if !stat.Ok() {
+		t.Fatalf("checkpoint: NewCheckpoint: status = %s", stat)
+	}
+	stat = cp.CreateCheckpoint(&dbcheckpointname)
+	if !stat.Ok() {
+		t.Fatalf("checkpoint: CreateCheckpoint: status = %s", stat)
+	}
+	cp.Close()
+	options.SetErrorIfExists(false);
+	cpdb, stat, _ := Open(options, &dbcheckpointname)
+	if !stat.Ok() {
+		t.Fatalf("checkpoint: open: stat = %s", stat)
+	}
+	options.SetErrorIfExists(true);
+	cpdb.checkGet(t, ropts, []byte("foo"), []byte("hello"))
+	cpdb.Close()
+	stat = DestroyDB(options, &dbcheckpointname)
+	if !stat.Ok() {
+		t.Fatalf("checkpoint: DestroyDB: status = %s", stat)
+	}
+
 	t.Log("phase: compactall")
 	cropt := NewCompactRangeOptions()
 	stat = db.CompactRange(cropt, nil, nil)
@@ -360,6 +801,41 @@ func TestCMain(t *testing.T) {
 	}
 	db.checkGet(t, ropts, []byte("foo"), []byte("hello"))
 
+	t.Log("phase: metadata")
+	{
+		lfmds := db.GetLiveFilesMetaData()
+		checkCondition(t, len(lfmds) > 0)
+		for _, lfmd := range lfmds {
+			checkCondition(t, lfmd.ColumnFamilyName == "default")
+			checkCondition(t, len(lfmd.Name) > 0)
+			checkCondition(t, lfmd.Size > 0)
+			checkCondition(t, bytes.Compare(lfmd.SmallestKey, lfmd.LargestKey) <= 0)
+			checkCondition(t, lfmd.SmallestSeqno <= lfmd.LargestSeqno)
+		}
+		cfmd := db.GetColumnFamilyMetaData()
+		checkCondition(t, cfmd.Name == "default")
+		checkCondition(t, cfmd.FileCount == uint64(len(lfmds)))
+		var nfiles int
+		for _, lmd := range cfmd.Levels {
+			nfiles += len(lmd.Files)
+		}
+		checkCondition(t, nfiles == len(lfmds))
+
+		tpc, stat := db.GetPropertiesOfAllTables()
+		if !stat.Ok() {
+			t.Fatalf("err: GetPropertiesOfAllTables: stat = %s", stat)
+		}
+		checkCondition(t, len(tpc) == len(lfmds))
+		for _, tp := range tpc {
+			checkCondition(t, tp.NumEntries > 0)
+			checkCondition(t, tp.DataSize > 0)
+			checkCondition(t, tp.RawKeySize > 0)
+			checkCondition(t, tp.ComparatorName == "foo")
+			checkCondition(t, tp.ColumnFamilyName == "default")
+			checkCondition(t, tp.UserCollectedProperties["test.count"] == fmt.Sprintf("%d", tp.NumEntries))
+		}
+	}
+
 	t.Log("phase: writebatch")
 	wb := NewWriteBatch()
 	wb.Put([]byte("foo"), []byte("a"))
@@ -374,12 +850,14 @@ func TestCMain(t *testing.T) {
 	db.checkGet(t, ropts, []byte("foo"), []byte("hello"))
 	db.checkGet(t, ropts, []byte("bar"), nil)
 	db.checkGet(t, ropts, []byte("box"), []byte("c"))
-	// StartPhase("writebatch");
-	// {
-	// 	int pos = 0;
-	// 	rocksdb_writebatch_iterate(wb, &pos, CheckPut, CheckDel);
-	// 	CheckCondition(pos == 3);
-	// }
+	{
+		handler := &testWriteBatchHandler{t: t}
+		stat = wb.Iterate(handler)
+		if !stat.Ok() {
+			t.Fatalf("err: writebatch Iterate: stat = %s", stat)
+		}
+		checkCondition(t, handler.pos == 3)
+	}
 	wb.Close()
 
 	t.Log("phase: writebatch_rep")
@@ -394,6 +872,96 @@ func TestCMain(t *testing.T) {
 	wb1.Close()
 	wb2.Close()
 
+	t.Log("phase: transaction_log")
+	{
+		tranropt := NewTransactionLogIteratorReadOptions(true)
+		checkCondition(t, tranropt.VerifyChecksums())
+		tranit, stat := db.GetUpdatesSince(0, tranropt)
+		if !stat.Ok() {
+			t.Fatalf("err: GetUpdatesSince: stat = %s", stat)
+		}
+		var lastsqn SequenceNumber
+		nbatch := 0
+		for ; tranit.Valid(); tranit.Next() {
+			checkCondition(t, tranit.Status().Ok())
+			res := tranit.GetBatch()
+			checkCondition(t, res.Batch != nil)
+			checkCondition(t, res.Sequence > lastsqn)
+			lastsqn = res.Sequence
+			res.Batch.Close()
+			nbatch++
+		}
+		checkCondition(t, nbatch > 0)
+		tranit.Close()
+		tranropt.Close()
+
+		files, stat := db.GetSortedWalFiles()
+		if !stat.Ok() {
+			t.Fatalf("err: GetSortedWalFiles: stat = %s", stat)
+		}
+		checkCondition(t, len(files) > 0)
+		logf := files[len(files)-1]
+		checkCondition(t, logf.Type() == AliveLogFile)
+		checkCondition(t, len(logf.PathName()) > 0)
+		checkCondition(t, logf.LogNumber() > 0)
+		checkCondition(t, logf.StartSequence() <= lastsqn)
+		checkCondition(t, logf.SizeFileBytes() > 0)
+	}
+
+	t.Log("phase: subscribe")
+	{
+		ctx, cancel := context.WithCancel(context.Background())
+		fromsqn := db.GetLatestSequenceNumber() + 1
+		events, errs := db.Subscribe(ctx, fromsqn, 10*time.Millisecond)
+		stat = db.Put(woptions, []byte("sub"), []byte("v"))
+		if !stat.Ok() {
+			t.Fatalf("err: subscribe Put: stat = %s", stat)
+		}
+		select {
+		case ev := <-events:
+			checkCondition(t, ev.Sequence == fromsqn)
+			checkCondition(t, ev.Op == ChangeOpPut)
+			checkCondition(t, ev.ColumnFamilyID == 0)
+			checkCondition(t, bytes.Equal(ev.Key, []byte("sub")))
+			checkCondition(t, bytes.Equal(ev.Value, []byte("v")))
+		case <-time.After(5 * time.Second):
+			t.Fatalf("err: subscribe: no event received")
+		}
+		cancel()
+		for _ = range events {
+		}
+		checkCondition(t, nil == <-errs)
+		stat = db.Delete(woptions, []byte("sub"))
+		if !stat.Ok() {
+			t.Fatalf("err: subscribe Delete: stat = %s", stat)
+		}
+
+		// Closing the db stops the running subscriptions
+		subname := dbname + "-subscribe"
+		sub_options := NewOptions()
+		sub_options.SetCreateIfMissing(true)
+		subdb, stat, _ := Open(sub_options, &subname)
+		if !stat.Ok() {
+			t.Fatalf("err: subscribe open: stat = %s", stat)
+		}
+		events, errs = subdb.Subscribe(context.Background(), subdb.GetLatestSequenceNumber() + 1, time.Millisecond)
+		stat = subdb.Put(woptions, []byte("sub"), []byte("v"))
+		if !stat.Ok() {
+			t.Fatalf("err: subscribe Put: stat = %s", stat)
+		}
+		subdb.Close()
+		for _ = range events {
+		}
+		checkCondition(t, nil == <-errs)
+		events, errs = subdb.Subscribe(context.Background(), 0)
+		_, ok := <-events
+		checkCondition(t, !ok)
+		checkCondition(t, (<-errs).IsShutdownInProgress())
+		stat = DestroyDB(sub_options, &subname)
+		t.Logf("subscribe: DestroyDB: status = %s", stat)
+		sub_options.Close()
+	}
+
 	t.Log("phase: iter")
 	iter := db.NewIterator(ropts)
 	checkCondition(t, !iter.Valid())
@@ -546,12 +1114,15 @@ func TestCMain(t *testing.T) {
 	options_with_filter_factory.Close()
 
 	t.Log("phase: merge_operator")
-	cmop := &testMergeOperator{t: t}
+	cmop := &testMergeOperator{t: t, logLevel: -1}
 	merge_operator := NewMergeOperator(cmop)
 	db.Close()
 	stat = DestroyDB(options, &dbname)
 	t.Logf("merge_operator: DestroyDB: status = %s", stat)
 	options.SetMergeOperator(merge_operator)
+	// The merge operator logs through the info log of the DB
+	merge_tl := &testLogger{}
+	options.SetInfoLog(NewPLogger(merge_tl, WARN_LEVEL))
 	db, stat, _ = Open(options, &dbname)
 	if !stat.Ok() {
 		t.Fatalf("compaction_filter_v2: err: open: stat = %s", stat)
@@ -571,6 +1142,9 @@ func TestCMain(t *testing.T) {
 		t.Fatalf("compaction_filter_v2:err: put err: stat = %s", stat)
 	}
 	db.checkGet(t, ropts, []byte("bar"), []byte("fake"))
+	checkCondition(t, WARN_LEVEL == atomic.LoadInt32(&cmop.logLevel))
+	checkCondition(t, 0 < atomic.LoadInt32(&merge_tl.lines[WARN_LEVEL]))
+	options.SetInfoLog(NewPLoggerDefault())
 
 	t.Log("phase: columnfamilies")
 	db.Close()
@@ -659,6 +1233,831 @@ func TestCMain(t *testing.T) {
 	db_options.Close()
 	cf_options.Close()
 
+	t.Log("phase: sst_file_writer")
+	{
+		sstname := fmt.Sprintf("%s/rocksdb_go_test-%d.sst", os.TempDir(), os.Geteuid())
+		sst_options := NewOptions()
+		sst_options.SetCreateIfMissing(true)
+		sfw := NewSstFileWriter(sst_options)
+		stat = sfw.Open(&sstname)
+		if !stat.Ok() {
+			t.Fatalf("sst_file_writer: Open: stat = %s", stat)
+		}
+		for _, key := range []string{"sst1", "sst2", "sst3"} {
+			stat = sfw.Add([]byte(key), []byte("v" + key))
+			if !stat.Ok() {
+				t.Fatalf("sst_file_writer: Add: stat = %s", stat)
+			}
+		}
+		stat = sfw.Add([]byte("sst0"), []byte("out of order"))
+		checkCondition(t, !stat.Ok())
+		esfi, stat := sfw.Finish()
+		if !stat.Ok() {
+			t.Fatalf("sst_file_writer: Finish: stat = %s", stat)
+		}
+		sfw.Close()
+		checkCondition(t, esfi.FilePath == sstname)
+		checkCondition(t, esfi.NumEntries == 3)
+		checkCondition(t, string(esfi.SmallestKey) == "sst1")
+		checkCondition(t, string(esfi.LargestKey) == "sst3")
+		checkCondition(t, esfi.FileSize > 0)
+
+		db, stat, _ = Open(sst_options, &dbname)
+		if !stat.Ok() {
+			t.Fatalf("sst_file_writer: open: stat = %s", stat)
+		}
+		stat = db.AddFile(&sstname, true)
+		if !stat.Ok() {
+			t.Fatalf("sst_file_writer: AddFile: stat = %s", stat)
+		}
+		db.checkGet(t, ropts, []byte("sst1"), []byte("vsst1"))
+		db.checkGet(t, ropts, []byte("sst3"), []byte("vsst3"))
+		db.checkGet(t, ropts, []byte("sst0"), nil)
+		db.Close()
+		stat = DestroyDB(sst_options, &dbname)
+		t.Logf("sst_file_writer: DestroyDB: status = %s", stat)
+		sst_options.Close()
+	}
+
+	t.Log("phase: bulk_loader")
+	{
+		bl_options := NewOptions()
+		bl_options.SetCreateIfMissing(true)
+		bl_options.SetComparator(cmp)
+		db, stat, _ = Open(bl_options, &dbname)
+		if !stat.Ok() {
+			t.Fatalf("bulk_loader: open: stat = %s", stat)
+		}
+		bl, stat := NewBulkLoader(db, bl_options, &BulkLoaderOptions{MemoryBudget: 1024, Parallelism: 2, TargetFileSize: 4096})
+		if !stat.Ok() {
+			t.Fatalf("bulk_loader: NewBulkLoader: stat = %s", stat)
+		}
+		const nloaders, nkeys = 4, 500
+		errs := make(chan *Status, nloaders)
+		for i := 0; i < nloaders; i++ {
+			go func(i int) {
+				for j := nkeys - 1; j >= 0; j-- {
+					if j % nloaders != i {
+						continue
+					}
+					key := []byte(fmt.Sprintf("bulk%06d", j))
+					if stat := bl.Add(key, key); !stat.Ok() {
+						errs <- stat
+						return
+					}
+				}
+				errs <- nil
+			}(i)
+		}
+		for i := 0; i < nloaders; i++ {
+			if stat := <-errs; nil != stat {
+				t.Fatalf("bulk_loader: Add: stat = %s", stat)
+			}
+		}
+		// The last added value wins
+		stat = bl.Add([]byte("bulk000000"), []byte("last"))
+		if !stat.Ok() {
+			t.Fatalf("bulk_loader: Add: stat = %s", stat)
+		}
+		added, stat := bl.Finish()
+		if !stat.Ok() {
+			t.Fatalf("bulk_loader: Finish: stat = %s", stat)
+		}
+		checkCondition(t, added > 1)
+		checkCondition(t, !bl.Add([]byte("bulk"), nil).Ok())
+		bl.Close()
+		checkCondition(t, len(db.GetLiveFilesMetaData()) > 1)
+		db.checkGet(t, ropts, []byte("bulk000000"), []byte("last"))
+		db.checkGet(t, ropts, []byte("bulk000001"), []byte("bulk000001"))
+		db.checkGet(t, ropts, []byte("bulk000499"), []byte("bulk000499"))
+		iter = db.NewIterator(ropts)
+		n := 0
+		for iter.SeekToFirst(); iter.Valid(); iter.Next() {
+			n++
+		}
+		checkCondition(t, n == nkeys)
+		iter.Close()
+
+		// A failed spill must not deadlock the calls to Add waiting
+		// for the only spilling slot.
+		bl, stat = NewBulkLoader(db, bl_options, &BulkLoaderOptions{MemoryBudget: 1, Parallelism: 1})
+		if !stat.Ok() {
+			t.Fatalf("bulk_loader: NewBulkLoader: stat = %s", stat)
+		}
+		os.RemoveAll(bl.dir)
+		done := make(chan *Status, nloaders)
+		for i := 0; i < nloaders; i++ {
+			go func(i int) {
+				for j := 0; j < nkeys; j++ {
+					key := []byte(fmt.Sprintf("bulkfail%d-%06d", i, j))
+					if stat := bl.Add(key, key); !stat.Ok() {
+						done <- stat
+						return
+					}
+				}
+				done <- nil
+			}(i)
+		}
+		for i := 0; i < nloaders; i++ {
+			select {
+			case stat = <-done:
+				checkCondition(t, nil != stat && !stat.Ok())
+			case <-time.After(10 * time.Second):
+				t.Fatalf("bulk_loader: Add deadlocked after a failed spill")
+			}
+		}
+		added, stat = bl.Finish()
+		checkCondition(t, 0 == added && !stat.Ok())
+		bl.Close()
+
+		db.Close()
+		stat = DestroyDB(bl_options, &dbname)
+		t.Logf("bulk_loader: DestroyDB: status = %s", stat)
+		bl_options.Close()
+	}
+
+	t.Log("phase: transaction_db")
+	{
+		txn_options := NewOptions()
+		txn_options.SetCreateIfMissing(true)
+		tdbopt := NewTransactionDBOptions()
+		tdbopt.SetTransactionLockTimeout(100)
+		checkCondition(t, tdbopt.TransactionLockTimeout() == 100)
+		tdb, stat, _ := OpenTransactionDB(txn_options, tdbopt, &dbname)
+		if !stat.Ok() {
+			t.Fatalf("transaction_db: open: stat = %s", stat)
+		}
+		stat = tdb.Put(woptions, []byte("acct"), []byte("100"))
+		if !stat.Ok() {
+			t.Fatalf("transaction_db: Put: stat = %s", stat)
+		}
+
+		// Row lock held by GetForUpdate
+		txn1 := tdb.BeginTransaction(woptions)
+		val, stat := txn1.GetForUpdate(ropts, []byte("acct"))
+		if !stat.Ok() {
+			t.Fatalf("transaction_db: GetForUpdate: stat = %s", stat)
+		}
+		checkCondition(t, string(val) == "100")
+		stat = txn1.Put([]byte("acct"), []byte("90"))
+		if !stat.Ok() {
+			t.Fatalf("transaction_db: Put: stat = %s", stat)
+		}
+		topt := NewTransactionOptions()
+		topt.SetLockTimeout(10)
+		txn2 := tdb.BeginTransaction(woptions, topt)
+		stat = txn2.Put([]byte("acct"), []byte("0"))
+		checkCondition(t, stat.IsTimedOut())
+		checkCondition(t, stat.IsLockTimeout())
+		checkCondition(t, !stat.IsBusy())
+		txn2.Close()
+		topt.Close()
+		tdb.checkGet(t, ropts, []byte("acct"), []byte("100"))
+		stat = txn1.Commit()
+		if !stat.Ok() {
+			t.Fatalf("transaction_db: Commit: stat = %s", stat)
+		}
+		txn1.Close()
+		tdb.checkGet(t, ropts, []byte("acct"), []byte("90"))
+
+		// Save points and rollback
+		txn3 := tdb.BeginTransaction(woptions)
+		txn3.Put([]byte("sp1"), []byte("a"))
+		txn3.SetSavePoint()
+		txn3.Put([]byte("sp2"), []byte("b"))
+		txn3.Delete([]byte("sp1"))
+		val, stat = txn3.Get(ropts, []byte("sp2"))
+		checkCondition(t, stat.Ok() && string(val) == "b")
+		stat = txn3.RollbackToSavePoint()
+		if !stat.Ok() {
+			t.Fatalf("transaction_db: RollbackToSavePoint: stat = %s", stat)
+		}
+		checkCondition(t, txn3.RollbackToSavePoint().IsNotFound())
+		stat = txn3.Commit()
+		if !stat.Ok() {
+			t.Fatalf("transaction_db: Commit: stat = %s", stat)
+		}
+		txn3.Close()
+		tdb.checkGet(t, ropts, []byte("sp1"), []byte("a"))
+		tdb.checkGet(t, ropts, []byte("sp2"), nil)
+		txn4 := tdb.BeginTransaction(woptions)
+		txn4.Put([]byte("rb"), []byte("a"))
+		stat = txn4.Rollback()
+		if !stat.Ok() {
+			t.Fatalf("transaction_db: Rollback: stat = %s", stat)
+		}
+		txn4.Close()
+		tdb.checkGet(t, ropts, []byte("rb"), nil)
+
+		// Write conflict with the snapshot
+		txn5 := tdb.BeginTransaction(woptions)
+		checkCondition(t, nil == txn5.GetSnapshot())
+		txn5.SetSnapshot()
+		checkCondition(t, nil != txn5.GetSnapshot())
+		stat = tdb.Put(woptions, []byte("acct"), []byte("80"))
+		if !stat.Ok() {
+			t.Fatalf("transaction_db: Put: stat = %s", stat)
+		}
+		stat = txn5.Put([]byte("acct"), []byte("70"))
+		checkCondition(t, stat.IsBusy())
+		txn5.Close()
+		tdb.checkGet(t, ropts, []byte("acct"), []byte("80"))
+
+		// Closing the db closes the open transactions
+		txn6 := tdb.BeginTransaction(woptions)
+		stat = txn6.Put([]byte("open"), []byte("1"))
+		if !stat.Ok() {
+			t.Fatalf("transaction_db: Put: stat = %s", stat)
+		}
+		tdb.Close()
+		checkCondition(t, txn6.closed)
+		checkCondition(t, !txn6.Put([]byte("open"), []byte("2")).Ok())
+		txn6.Close()
+		stat = DestroyDB(txn_options, &dbname)
+		t.Logf("transaction_db: DestroyDB: status = %s", stat)
+		tdbopt.Close()
+		txn_options.Close()
+	}
+
+	t.Log("phase: optimistic_transaction_db")
+	{
+		otxn_options := NewOptions()
+		otxn_options.SetCreateIfMissing(true)
+		otdb, stat, _ := OpenOptimisticTransactionDB(otxn_options, &dbname)
+		if !stat.Ok() {
+			t.Fatalf("optimistic_transaction_db: open: stat = %s", stat)
+		}
+		stat = otdb.Put(woptions, []byte("acct"), []byte("100"))
+		if !stat.Ok() {
+			t.Fatalf("optimistic_transaction_db: Put: stat = %s", stat)
+		}
+
+		// Read from the transaction snapshot
+		otopt := NewOptimisticTransactionOptions()
+		otopt.SetSetSnapshot(true)
+		checkCondition(t, otopt.SetSnapshot())
+		txn1 := otdb.BeginTransaction(woptions, otopt)
+		snp := txn1.GetSnapshot()
+		checkCondition(t, nil != snp)
+		stat = otdb.Put(woptions, []byte("other"), []byte("1"))
+		if !stat.Ok() {
+			t.Fatalf("optimistic_transaction_db: Put: stat = %s", stat)
+		}
+		snpropts := NewReadOptions()
+		snpropts.SetSnapshot(snp)
+		val, stat := txn1.Get(snpropts, []byte("other"))
+		checkCondition(t, stat.IsNotFound() && nil == val)
+		snpropts.Close()
+
+		// Conflict detected at commit time
+		val, stat = txn1.GetForUpdate(ropts, []byte("acct"))
+		if !stat.Ok() {
+			t.Fatalf("optimistic_transaction_db: GetForUpdate: stat = %s", stat)
+		}
+		checkCondition(t, string(val) == "100")
+		stat = txn1.Put([]byte("acct"), []byte("90"))
+		if !stat.Ok() {
+			t.Fatalf("optimistic_transaction_db: Put: stat = %s", stat)
+		}
+		stat = otdb.Put(woptions, []byte("acct"), []byte("80"))
+		if !stat.Ok() {
+			t.Fatalf("optimistic_transaction_db: Put: stat = %s", stat)
+		}
+		stat = txn1.Commit()
+		checkCondition(t, stat.IsBusy())
+		txn1.Close()
+		otdb.checkGet(t, ropts, []byte("acct"), []byte("80"))
+
+		// Commit without conflict
+		txn2 := otdb.BeginTransaction(woptions)
+		txn2.Put([]byte("acct"), []byte("70"))
+		stat = txn2.Commit()
+		if !stat.Ok() {
+			t.Fatalf("optimistic_transaction_db: Commit: stat = %s", stat)
+		}
+		txn2.Close()
+		otdb.checkGet(t, ropts, []byte("acct"), []byte("70"))
+
+		// Closing the db closes the open transactions
+		txn3 := otdb.BeginTransaction(woptions, otopt)
+		stat = txn3.Put([]byte("open"), []byte("1"))
+		if !stat.Ok() {
+			t.Fatalf("optimistic_transaction_db: Put: stat = %s", stat)
+		}
+		otdb.Close()
+		checkCondition(t, txn3.closed)
+		checkCondition(t, !txn3.Commit().Ok())
+		txn3.Close()
+		stat = DestroyDB(otxn_options, &dbname)
+		t.Logf("optimistic_transaction_db: DestroyDB: status = %s", stat)
+		otopt.Close()
+		otxn_options.Close()
+	}
+
+	t.Log("phase: db_ttl")
+	{
+		ttl_options := NewOptions()
+		ttl_options.SetCreateIfMissing(true)
+		dbttl, stat, _ := OpenWithTTL(ttl_options, &dbname, 1)
+		if !stat.Ok() {
+			t.Fatalf("db_ttl: open: stat = %s", stat)
+		}
+		cfttl := "cfttl"
+		cfh, stat := dbttl.CreateColumnFamilyWithTTL(&ttl_options.ColumnFamilyOptions, &cfttl, 0)
+		if !stat.Ok() {
+			t.Fatalf("db_ttl: CreateColumnFamilyWithTTL: stat = %s", stat)
+		}
+		stat = dbttl.Put(woptions, []byte("expired"), []byte("a"))
+		if !stat.Ok() {
+			t.Fatalf("db_ttl: Put: stat = %s", stat)
+		}
+		stat = dbttl.Put(woptions, []byte("kept"), []byte("b"), cfh)
+		if !stat.Ok() {
+			t.Fatalf("db_ttl: Put: stat = %s", stat)
+		}
+		dbttl.checkGet(t, ropts, []byte("expired"), []byte("a"))
+		time.Sleep(2 * time.Second)
+		ttlcropt := NewCompactRangeOptions()
+		stat = dbttl.CompactRange(ttlcropt, nil, nil)
+		if !stat.Ok() {
+			t.Fatalf("db_ttl: CompactRange: stat = %s", stat)
+		}
+		stat = dbttl.CompactRange(ttlcropt, nil, nil, cfh)
+		if !stat.Ok() {
+			t.Fatalf("db_ttl: CompactRange: stat = %s", stat)
+		}
+		dbttl.checkGet(t, ropts, []byte("expired"), nil)
+		dbttl.checkGet(t, ropts, []byte("kept"), []byte("b"), cfh)
+		cfh.Close()
+		dbttl.Close()
+
+		// Reopen with per column family ttls
+		cfds := []*ColumnFamilyDescriptor{NewColumnFamilyDescriptor(&default_s, &ttl_options.ColumnFamilyOptions), NewColumnFamilyDescriptor(&cfttl, &ttl_options.ColumnFamilyOptions)}
+		dbttl, stat, cfhs := OpenColumnFamiliesWithTTL(ttl_options, &dbname, cfds, []int32{0, 1})
+		if !stat.Ok() {
+			t.Fatalf("db_ttl: OpenColumnFamiliesWithTTL: stat = %s", stat)
+		}
+		time.Sleep(2 * time.Second)
+		stat = dbttl.CompactRange(ttlcropt, nil, nil, cfhs[1])
+		if !stat.Ok() {
+			t.Fatalf("db_ttl: CompactRange: stat = %s", stat)
+		}
+		dbttl.checkGet(t, ropts, []byte("kept"), nil, cfhs[1])
+		_, stat, _ = OpenColumnFamiliesWithTTL(ttl_options, &dbname, cfds, []int32{0})
+		checkCondition(t, stat.IsInvalidArgument())
+		for _, cfh := range cfhs {
+			cfh.Close()
+		}
+		dbttl.Close()
+
+		stat = DestroyDB(ttl_options, &dbname)
+		t.Logf("db_ttl: DestroyDB: status = %s", stat)
+		ttl_options.Close()
+	}
+
+	t.Log("phase: write_batch_with_index")
+	{
+		wbwi_options := NewOptions()
+		wbwi_options.SetCreateIfMissing(true)
+		db, stat, _ = Open(wbwi_options, &dbname)
+		if !stat.Ok() {
+			t.Fatalf("write_batch_with_index: open: stat = %s", stat)
+		}
+		db.Put(woptions, []byte("a"), []byte("1"))
+		db.Put(woptions, []byte("c"), []byte("3"))
+		wbwi := NewWriteBatchWithIndex(nil, 0, true)
+		wbwi.Put([]byte("b"), []byte("2"))
+		wbwi.Delete([]byte("c"))
+		wbwi.Put([]byte("a"), []byte("10"))
+		val, stat := wbwi.GetFromBatch(&wbwi_options.DBOptions, []byte("b"))
+		checkCondition(t, stat.Ok() && string(val) == "2")
+		_, stat = wbwi.GetFromBatch(&wbwi_options.DBOptions, []byte("d"))
+		checkCondition(t, stat.IsNotFound())
+		val, stat = wbwi.GetFromBatchAndDB(db, ropts, []byte("a"))
+		checkCondition(t, stat.Ok() && string(val) == "10")
+		_, stat = wbwi.GetFromBatchAndDB(db, ropts, []byte("c"))
+		checkCondition(t, stat.IsNotFound())
+		db.checkGet(t, ropts, []byte("c"), []byte("3"))
+
+		// Pending writes merged over the db
+		it := wbwi.NewIteratorWithBase(db.NewIterator(ropts))
+		var kvs []string
+		for it.SeekToFirst(); it.Valid(); it.Next() {
+			kvs = append(kvs, string(it.Key())+"="+string(it.Value()))
+		}
+		checkCondition(t, it.Status().Ok())
+		checkCondition(t, fmt.Sprint(kvs) == "[a=10 b=2]")
+		it.Close()
+
+		wbt := wbwi.GetWriteBatch()
+		checkCondition(t, wbt.Count() == 3)
+		stat = db.Write(woptions, wbt)
+		if !stat.Ok() {
+			t.Fatalf("write_batch_with_index: Write: stat = %s", stat)
+		}
+		wbt.Close()
+		// Closing the batch closes the iterators reading it
+		it = wbwi.NewIteratorWithBase(db.NewIterator(ropts))
+		it.SeekToFirst()
+		checkCondition(t, it.Valid())
+		wbwi.Close()
+		checkCondition(t, it.closed)
+		it.Close()
+		db.checkGet(t, ropts, []byte("a"), []byte("10"))
+		db.checkGet(t, ropts, []byte("b"), []byte("2"))
+		db.checkGet(t, ropts, []byte("c"), nil)
+
+		t.Log("phase: write_batch_with_index_no_overwrite")
+		// No iterator without overwriteKey, and base is left usable
+		wbwi = NewWriteBatchWithIndex(nil, 0, false)
+		wbwi.Put([]byte("b"), []byte("20"))
+		base := db.NewIterator(ropts)
+		checkCondition(t, nil == wbwi.NewIteratorWithBase(base))
+		checkCondition(t, !base.closed)
+		base.SeekToFirst()
+		checkCondition(t, base.Valid())
+		checkCondition(t, string(base.Key()) == "a")
+		base.Close()
+		wbwi.Close()
+
+		db.Close()
+		stat = DestroyDB(wbwi_options, &dbname)
+		t.Logf("write_batch_with_index: DestroyDB: status = %s", stat)
+		wbwi_options.Close()
+	}
+
+	t.Log("phase: statistics")
+	{
+		stats_options := NewOptions()
+		stats_options.SetCreateIfMissing(true)
+		stats := NewStatistics()
+		stats_options.SetStatistics(stats)
+		db, stat, _ = Open(stats_options, &dbname)
+		if !stat.Ok() {
+			t.Fatalf("statistics: open: stat = %s", stat)
+		}
+		db.Put(woptions, []byte("a"), []byte("1"))
+		db.Put(woptions, []byte("b"), []byte("2"))
+		db.checkGet(t, ropts, []byte("a"), []byte("1"))
+		checkCondition(t, stats.GetTickerCount(NumberKeysWritten) == 2)
+		checkCondition(t, stats.GetTickerCount(NumberKeysRead) == 1)
+		checkCondition(t, stats.GetTickerCount(MemtableHit) == 1)
+		hd := stats.HistogramData(DBGet)
+		checkCondition(t, nil != hd && hd.Average >= 0)
+		checkCondition(t, BlockCacheHit.String() == "rocksdb.block.cache.hit")
+		checkCondition(t, DBGet.String() == "rocksdb.db.get.micros")
+		for _, tk := range Tickers() {
+			checkCondition(t, tk.String() != "")
+		}
+		for _, hg := range Histograms() {
+			checkCondition(t, hg.String() != "")
+		}
+		t.Logf("statistics: %s", stats)
+		stats.Reset()
+		checkCondition(t, stats.GetTickerCount(NumberKeysWritten) == 0)
+
+		db.Close()
+		stat = DestroyDB(stats_options, &dbname)
+		t.Logf("statistics: DestroyDB: status = %s", stat)
+		stats.Close()
+		stats_options.Close()
+	}
+
+	t.Log("phase: rate_limiter")
+	{
+		_, stat = NewGenericRateLimiter(0, DefaultRateLimiterRefillPeriodUs, DefaultRateLimiterFairness)
+		checkCondition(t, stat.IsInvalidArgument())
+		_, stat = NewGenericRateLimiter(1<<30, 0, DefaultRateLimiterFairness)
+		checkCondition(t, stat.IsInvalidArgument())
+		_, stat = NewGenericRateLimiter(1<<30, DefaultRateLimiterRefillPeriodUs, 0)
+		checkCondition(t, stat.IsInvalidArgument())
+		rl, stat := NewGenericRateLimiter(1<<30, DefaultRateLimiterRefillPeriodUs, DefaultRateLimiterFairness)
+		if !stat.Ok() {
+			t.Fatalf("rate_limiter: NewGenericRateLimiter: stat = %s", stat)
+		}
+		checkCondition(t, rl.GetSingleBurstBytes() > 0)
+		rl_options := NewOptions()
+		rl_options.SetCreateIfMissing(true)
+		rl_options.SetRateLimiter(rl)
+		rl_dbname := dbname + "_rl"
+		db, stat, _ = Open(rl_options, &dbname)
+		if !stat.Ok() {
+			t.Fatalf("rate_limiter: open: stat = %s", stat)
+		}
+		// Shared by another db
+		rl_db, stat, _ := Open(rl_options, &rl_dbname)
+		if !stat.Ok() {
+			t.Fatalf("rate_limiter: open: stat = %s", stat)
+		}
+		checkCondition(t, rl.SetBytesPerSecond(0).IsInvalidArgument())
+		checkCondition(t, rl.SetBytesPerSecond(-1).IsInvalidArgument())
+		stat = rl.SetBytesPerSecond(1 << 20)
+		if !stat.Ok() {
+			t.Fatalf("rate_limiter: SetBytesPerSecond: stat = %s", stat)
+		}
+		rl_fopts := NewFlushOptions()
+		for i, rdb := range []*DB{db, rl_db} {
+			stat = rdb.Put(woptions, []byte("foo"), bytes.Repeat([]byte("v"), 4096))
+			if !stat.Ok() {
+				t.Fatalf("rate_limiter: Put: stat = %s", stat)
+			}
+			through := rl.GetTotalBytesThrough()
+			stat = rdb.Flush(rl_fopts)
+			if !stat.Ok() {
+				t.Fatalf("rate_limiter: Flush %d: stat = %s", i, stat)
+			}
+			checkCondition(t, rl.GetTotalBytesThrough() > through)
+		}
+		checkCondition(t, rl.GetTotalRequests() > 0)
+
+		rl_db.Close()
+		db.Close()
+		stat = DestroyDB(rl_options, &rl_dbname)
+		t.Logf("rate_limiter: DestroyDB: status = %s", stat)
+		stat = DestroyDB(rl_options, &dbname)
+		t.Logf("rate_limiter: DestroyDB: status = %s", stat)
+		rl_options.Close()
+		rl.Close()
+	}
+
+	t.Log("phase: event_listener")
+	{
+		tel := &testEventListener{}
+		el_options := NewOptions()
+		el_options.SetCreateIfMissing(true)
+		el_options.AddListener(tel)
+		db, stat, _ = Open(el_options, &dbname)
+		if !stat.Ok() {
+			t.Fatalf("event_listener: open: stat = %s", stat)
+		}
+		el_fopts := NewFlushOptions()
+		for i := 0; i < 2; i++ {
+			stat = db.Put(woptions, []byte(fmt.Sprintf("foo%d", i)), []byte("bar"))
+			if !stat.Ok() {
+				t.Fatalf("event_listener: Put: stat = %s", stat)
+			}
+			stat = db.Flush(el_fopts)
+			if !stat.Ok() {
+				t.Fatalf("event_listener: Flush: stat = %s", stat)
+			}
+		}
+		checkCondition(t, waitForEvent(&tel.flushes))
+		checkCondition(t, waitForEvent(&tel.created))
+		el_cropt := NewCompactRangeOptions()
+		stat = db.CompactRange(el_cropt, nil, nil)
+		if !stat.Ok() {
+			t.Fatalf("event_listener: CompactRange: stat = %s", stat)
+		}
+		checkCondition(t, waitForEvent(&tel.compactions))
+		checkCondition(t, atomic.LoadInt32(&tel.compactedFiles) >= 2)
+		checkCondition(t, waitForEvent(&tel.deleted))
+
+		db.Close()
+		stat = DestroyDB(el_options, &dbname)
+		t.Logf("event_listener: DestroyDB: status = %s", stat)
+		el_options.Close()
+	}
+
+	t.Log("phase: go_env")
+	{
+		checkCondition(t, nil == NewEnv(nil))
+		te := &testEnv{locks: make(map[string]bool)}
+		goenv := NewEnv(te)
+		env_options := NewOptions()
+		env_options.SetCreateIfMissing(true)
+		env_options.SetEnv(goenv)
+		db, stat, _ = Open(env_options, &dbname)
+		if !stat.Ok() {
+			t.Fatalf("go_env: open: stat = %s", stat)
+		}
+		// The lock is held by the opened db
+		_, stat, _ = Open(env_options, &dbname)
+		checkCondition(t, !stat.Ok())
+		stat = db.Put(woptions, []byte("foo"), []byte("hello"))
+		if !stat.Ok() {
+			t.Fatalf("go_env: Put: stat = %s", stat)
+		}
+		env_fopts := NewFlushOptions()
+		stat = db.Flush(env_fopts)
+		if !stat.Ok() {
+			t.Fatalf("go_env: Flush: stat = %s", stat)
+		}
+		checkCondition(t, atomic.LoadInt64(&te.written) > 0)
+		db.Close()
+		checkCondition(t, len(te.locks) == 0)
+
+		db, stat, _ = Open(env_options, &dbname)
+		if !stat.Ok() {
+			t.Fatalf("go_env: reopen: stat = %s", stat)
+		}
+		db.checkGet(t, ropts, []byte("foo"), []byte("hello"))
+		db.Close()
+		stat = DestroyDB(env_options, &dbname)
+		t.Logf("go_env: DestroyDB: status = %s", stat)
+
+		// The env is kept alive by the options and the db, and the
+		// base env by the env wrapping it
+		env_options.SetEnv(NewEnv(te, NewEnv(te)))
+		runtime.GC()
+		runtime.GC()
+		db, stat, _ = Open(env_options, &dbname)
+		if !stat.Ok() {
+			t.Fatalf("go_env: open with base: stat = %s", stat)
+		}
+		env_options.SetEnv(NewEnvDefault())
+		runtime.GC()
+		runtime.GC()
+		stat = db.Put(woptions, []byte("foo"), []byte("hello"))
+		if !stat.Ok() {
+			t.Fatalf("go_env: Put with base: stat = %s", stat)
+		}
+		stat = db.Flush(env_fopts)
+		if !stat.Ok() {
+			t.Fatalf("go_env: Flush with base: stat = %s", stat)
+		}
+		db.Close()
+		env_options.SetEnv(NewEnv(te))
+		stat = DestroyDB(env_options, &dbname)
+		t.Logf("go_env: DestroyDB: status = %s", stat)
+		env_options.Close()
+	}
+
+	t.Log("phase: mem_env")
+	{
+		mem_options := NewOptions()
+		mem_options.SetCreateIfMissing(true)
+		// The env and its files are kept alive by the options
+		mem_options.SetEnv(NewMemEnv(nil))
+		runtime.GC()
+		runtime.GC()
+		mem_dbname := dbname + "_mem"
+		db, stat, _ = Open(mem_options, &mem_dbname)
+		if !stat.Ok() {
+			t.Fatalf("mem_env: open: stat = %s", stat)
+		}
+		mem_cf1 := "cf1"
+		var mem_cfh *ColumnFamilyHandle
+		mem_cfh, stat = db.CreateColumnFamily(&mem_options.ColumnFamilyOptions, &mem_cf1)
+		if !stat.Ok() {
+			t.Fatalf("mem_env: CreateColumnFamily: stat = %s", stat)
+		}
+		mem_cfh.Close()
+		stat = db.Put(woptions, []byte("foo"), []byte("hello"))
+		if !stat.Ok() {
+			t.Fatalf("mem_env: Put: stat = %s", stat)
+		}
+		db.Close()
+
+		// Nothing is written to the disk
+		_, err := os.Stat(mem_dbname)
+		checkCondition(t, os.IsNotExist(err))
+
+		var mem_cfss []string
+		mem_cfss, stat = ListColumnFamilies(&mem_options.DBOptions, &mem_dbname)
+		if !stat.Ok() {
+			t.Fatalf("mem_env: ListColumnFamilies: stat = %s", stat)
+		}
+		checkCondition(t, 2 == len(mem_cfss))
+
+		// The data lives as long as the env
+		runtime.GC()
+		runtime.GC()
+		mem_options.SetCreateIfMissing(false)
+		db, stat, _ = Open(mem_options, &mem_dbname, NewColumnFamilyDescriptor(&default_s, &mem_options.ColumnFamilyOptions), NewColumnFamilyDescriptor(&mem_cf1, &mem_options.ColumnFamilyOptions))
+		if !stat.Ok() {
+			t.Fatalf("mem_env: reopen: stat = %s", stat)
+		}
+		db.checkGet(t, ropts, []byte("foo"), []byte("hello"))
+		db.Close()
+
+		stat = DestroyDB(mem_options, &mem_dbname)
+		if !stat.Ok() {
+			t.Fatalf("mem_env: DestroyDB: stat = %s", stat)
+		}
+		_, stat, _ = Open(mem_options, &mem_dbname)
+		checkCondition(t, !stat.Ok())
+
+		// The base env is kept alive by the mem env
+		mem_options.SetEnv(NewMemEnv(NewEnv(&testEnv{locks: make(map[string]bool)})))
+		mem_options.SetCreateIfMissing(true)
+		runtime.GC()
+		runtime.GC()
+		db, stat, _ = Open(mem_options, &mem_dbname)
+		if !stat.Ok() {
+			t.Fatalf("mem_env: open with base: stat = %s", stat)
+		}
+		stat = db.Put(woptions, []byte("foo"), []byte("hello"))
+		if !stat.Ok() {
+			t.Fatalf("mem_env: Put with base: stat = %s", stat)
+		}
+		db.Close()
+		mem_options.Close()
+	}
+
+	t.Log("phase: fault_injection_env")
+	{
+		fienv := NewFaultInjectionEnv()
+		fi_options := NewOptions()
+		fi_dbname := dbname + "_fi"
+		results, stat := CrashTest(fi_options, fi_dbname, fienv, 100)
+		if !stat.Ok() {
+			t.Fatalf("fault_injection_env: CrashTest: stat = %s", stat)
+		}
+		checkCondition(t, 2 == len(results))
+		for _, res := range results {
+			t.Logf("fault_injection_env: sync = %v: %d of %d survived", res.Sync, res.Survived, res.Written)
+			if res.Sync {
+				checkCondition(t, res.Survived == res.Written)
+			} else {
+				// The WAL is never synced, so all the writes are dropped
+				checkCondition(t, res.Survived < res.Written)
+				checkCondition(t, 0 == res.Survived)
+			}
+		}
+
+		// A crash drops the unsynced write and keeps the synced one
+		fi_options.SetEnv(fienv.Env())
+		db, stat, _ = Open(fi_options, &fi_dbname)
+		if !stat.Ok() {
+			t.Fatalf("fault_injection_env: open: stat = %s", stat)
+		}
+		fi_wopts := NewWriteOptions()
+		fi_wopts.SetSync(true)
+		stat = db.Put(fi_wopts, []byte("synced"), []byte("hello"))
+		if !stat.Ok() {
+			t.Fatalf("fault_injection_env: Put: stat = %s", stat)
+		}
+		fi_wopts.SetSync(false)
+		stat = db.Put(fi_wopts, []byte("unsynced"), []byte("hello"))
+		if !stat.Ok() {
+			t.Fatalf("fault_injection_env: Put: stat = %s", stat)
+		}
+		stat = fienv.SimulateCrash(db)
+		if !stat.Ok() {
+			t.Fatalf("fault_injection_env: SimulateCrash: stat = %s", stat)
+		}
+		db, stat, _ = Open(fi_options, &fi_dbname)
+		if !stat.Ok() {
+			t.Fatalf("fault_injection_env: open after crash: stat = %s", stat)
+		}
+		db.checkGet(t, ropts, []byte("synced"), []byte("hello"))
+		db.checkGet(t, ropts, []byte("unsynced"), nil)
+		fi_wopts.Close()
+		stat = db.Put(woptions, []byte("foo"), []byte("hello"))
+		if !stat.Ok() {
+			t.Fatalf("fault_injection_env: Put: stat = %s", stat)
+		}
+		fi_fopts := NewFlushOptions()
+		fienv.FailFileTypes(TableFile)
+		stat = db.Flush(fi_fopts)
+		checkCondition(t, !stat.Ok())
+		db.Close()
+		fienv.ResetState()
+
+		db, stat, _ = Open(fi_options, &fi_dbname)
+		if !stat.Ok() {
+			t.Fatalf("fault_injection_env: reopen: stat = %s", stat)
+		}
+		fienv.FailWritesAfter(0)
+		stat = db.Put(woptions, []byte("bar"), []byte("hello"))
+		checkCondition(t, !stat.Ok())
+		db.Close()
+		fienv.ResetState()
+		stat = DestroyDB(fi_options, &fi_dbname)
+		t.Logf("fault_injection_env: DestroyDB: status = %s", stat)
+		fi_options.Close()
+	}
+
+	t.Log("phase: go_logger")
+	{
+		tl := &testLogger{}
+		log_options := NewOptions()
+		log_options.SetCreateIfMissing(true)
+		log_options.SetInfoLog(NewPLogger(tl, WARN_LEVEL))
+		log_dbname := dbname + "_log"
+		db, stat, _ = Open(log_options, &log_dbname)
+		if !stat.Ok() {
+			t.Fatalf("go_logger: open: stat = %s", stat)
+		}
+		db.Close()
+		// The lines under WARN_LEVEL are dropped
+		checkCondition(t, 0 == atomic.LoadInt32(&tl.lines[INFO_LEVEL]))
+
+		log_options.SetInfoLog(NewPLogger(tl))
+		db, stat, _ = Open(log_options, &log_dbname)
+		if !stat.Ok() {
+			t.Fatalf("go_logger: reopen: stat = %s", stat)
+		}
+		db.Close()
+		checkCondition(t, 0 < atomic.LoadInt32(&tl.lines[INFO_LEVEL]))
+		// No LOG file is written into the DB directory
+		_, err := os.Stat(log_dbname + "/LOG")
+		checkCondition(t, os.IsNotExist(err))
+		stat = DestroyDB(log_options, &log_dbname)
+		t.Logf("go_logger: DestroyDB: status = %s", stat)
+		log_options.Close()
+	}
+
 	t.Log("phase: prefix")
 	// Create new database
 	options.SetAllowMmapReads(true)
@@ -0,0 +1,78 @@
+// Copyright (c) 2015, Dean ChaoJun Pan.  All rights reserved.
+// This source code is licensed under the BSD-style license found in the
+// LICENSE file in the root directory of this source tree.
+
+#include <rocksdb/utilities/db_ttl.h>
+
+using namespace rocksdb;
+
+#include "db_ttl.h"
+
+DEFINE_C_WRAP_CONSTRUCTOR(DBWithTTL)
+DEFINE_C_WRAP_STATIC_CAST(DBWithTTL, DB)
+
+// Open a DBWithTTL similar to DB::Open(). Entries older than ttl
+// seconds are removed during compaction. ttl <= 0 means infinity.
+Status_t DBWithTTLOpen(const Options_t* options,
+                       const String_t* name,
+                       DBWithTTL_t* dbptr,
+                       int32_t ttl,
+                       bool read_only)
+{
+    assert(dbptr != NULL);
+    DBWithTTL** rdbptr = GET_REP_ADDR(dbptr, DBWithTTL);
+    assert(rdbptr != NULL);
+    assert(GET_REP(options, Options) != NULL);
+    assert(GET_REP(name, String) != NULL);
+    Status stat = DBWithTTL::Open(GET_REP_REF(options, Options), GET_REP_REF(name, String), rdbptr, ttl, read_only);
+    return NewStatusTCopy(&stat);
+}
+
+// Open a DBWithTTL with column families similar to DB::Open().
+// ttls[i] is the ttl of column_families[i].
+Status_t DBWithTTLOpenWithColumnFamilies(const Options_t* options,
+                                         const String_t* name,
+                                         const ColumnFamilyDescriptor_t column_families[], const int size_col,
+                                         ColumnFamilyHandle_t **handles,
+                                         DBWithTTL_t* dbptr,
+                                         const int32_t ttls[],
+                                         bool read_only)
+{
+    std::vector<ColumnFamilyDescriptor> column_families_vec = std::vector<ColumnFamilyDescriptor>();
+    for (int i = 0; i < size_col; i++)
+        column_families_vec.push_back(*(ColumnFamilyDescriptor*)column_families[i].rep);
+    std::vector<int32_t> ttls_vec(ttls, ttls + size_col);
+    std::vector<ColumnFamilyHandle*> handles_vec;
+    assert(dbptr != NULL);
+    DBWithTTL** rdbptr = GET_REP_ADDR(dbptr, DBWithTTL);
+    assert(rdbptr != NULL);
+    assert(GET_REP(options, Options) != NULL);
+    assert(GET_REP(name, String) != NULL);
+    Status stat = DBWithTTL::Open(GET_REP_REF(options, Options), GET_REP_REF(name, String), column_families_vec, &handles_vec, rdbptr, ttls_vec, read_only);
+    Status_t ret = NewStatusTCopy(&stat);
+    *handles = new ColumnFamilyHandle_t[size_col];
+    for (int j = 0; j < size_col; j++)
+    {
+        ColumnFamilyHandle** rcfh = GET_REP_ADDR(&(*handles)[j], ColumnFamilyHandle);
+        *rcfh = (j < (int)handles_vec.size()) ? handles_vec[j] : nullptr;
+    }
+    return ret;
+}
+
+// Create a column_family with ttl and return the handle of column family
+// through the argument handle.
+Status_t DBWithTTLCreateColumnFamilyWithTtl(DBWithTTL_t* dbptr,
+                                            const ColumnFamilyOptions_t* options,
+                                            const String_t* column_family_name,
+                                            ColumnFamilyHandle_t* handle,
+                                            int ttl)
+{
+    assert(handle != NULL);
+    ColumnFamilyHandle** rcfh = GET_REP_ADDR(handle, ColumnFamilyHandle);
+    assert(GET_REP(options, ColumnFamilyOptions) != NULL);
+    assert(GET_REP(column_family_name, String) != NULL);
+    Status ret = (dbptr && GET_REP(dbptr, DBWithTTL)) ?
+        GET_REP(dbptr, DBWithTTL)->CreateColumnFamilyWithTtl(GET_REP_REF(options, ColumnFamilyOptions), GET_REP_REF(column_family_name, String), rcfh, ttl) :
+        invalid_status;
+    return NewStatusTCopy(&ret);
+}
@@ -0,0 +1,142 @@
+// Copyright (c) 2015, Dean ChaoJun Pan.  All rights reserved.
+// This source code is licensed under the BSD-style license found in the
+// LICENSE file in the root directory of this source tree.
+
+package rocksdb
+
+/*
+#include "db_ttl.h"
+*/
+import "C"
+
+import (
+	"runtime"
+)
+
+// Database with TTL support.
+//
+// USE-CASES:
+// This API should be used to open the db when key-values inserted are
+// meant to be removed from the db in a non-strict 'ttl' amount of time.
+// Therefore, this guarantees that key-values inserted will remain in the
+// db for >= ttl amount of time and the db will make efforts to remove the
+// key-values as soon as possible after ttl seconds of their insertion.
+//
+// BEHAVIOUR:
+// TTL is accepted in seconds.
+// (int32_t)Timestamp(creation) is suffixed to values in Put internally.
+// Expired TTL values are deleted in compaction only:(Timestamp+ttl<time_now).
+// Get/Iterator may return expired entries(compaction not run on them yet).
+// Different TTL may be used during different Opens.
+// Example: Open1 at t=0 with ttl=4 and insert k1,k2, close at t=2.
+//          Open2 at t=3 with ttl=5. Now k1,k2 should be deleted at t>=5.
+// A ttl <= 0 means infinity (the entries are never expired).
+//
+// All the DB methods can be called on a DBWithTTL.
+type DBWithTTL struct {
+	*DB
+	dbttl C.DBWithTTL_t
+}
+
+// Open a DBWithTTL similar to Open(). ttl is applied to all
+// the column families in cfds.
+func OpenWithTTL(options *Options, name *string, ttl int32, cfds ...*ColumnFamilyDescriptor) (dbttl *DBWithTTL, stat *Status, cfhs []*ColumnFamilyHandle) {
+	var ttls []int32
+
+	if cfds != nil {
+		ttls = make([]int32, len(cfds))
+		for i := range ttls {
+			ttls[i] = ttl
+		}
+	}
+
+	return openWithTTL(options, name, ttl, cfds, ttls)
+}
+
+// Open a DBWithTTL with column families similar to Open(). ttls[i] is the
+// ttl of the column family cfds[i].
+func OpenColumnFamiliesWithTTL(options *Options, name *string, cfds []*ColumnFamilyDescriptor, ttls []int32) (dbttl *DBWithTTL, stat *Status, cfhs []*ColumnFamilyHandle) {
+	if len(cfds) != len(ttls) {
+		stat = NewInvalidArgumentStatus("ttls should be specified for each column family")
+		return
+	}
+
+	return openWithTTL(options, name, 0, cfds, ttls)
+}
+
+// Open a DBWithTTL
+func openWithTTL(options *Options, name *string, ttl int32, cfds []*ColumnFamilyDescriptor, ttls []int32) (dbttl *DBWithTTL, stat *Status, cfhs []*ColumnFamilyHandle) {
+	dbttl = &DBWithTTL{DB: newDB(&options.DBOptions)}
+	rstr := newCStringFromString(name)
+	defer rstr.del()
+
+	var (
+		ccfds []C.ColumnFamilyDescriptor_t
+		cttls []C.int32_t
+	)
+
+	if len(cfds) > 0 {
+		s := make([]interface{}, len(cfds))
+		for i, v := range cfds {
+			s[i] = v
+		}
+		ccfds = newCArrayFromColumnFamilyDescriptorArray(s...)
+		cttls = make([]C.int32_t, len(ttls))
+		for i, v := range ttls {
+			cttls[i] = C.int32_t(v)
+		}
+	}
+
+	var (
+		cdbttl *C.DBWithTTL_t = &dbttl.dbttl
+		opt *C.Options_t = &options.opt
+		cstr *C.String_t = &rstr.str
+		cfh *C.ColumnFamilyHandle_t
+	)
+
+	if ccfds != nil {
+		cstat := C.DBWithTTLOpenWithColumnFamilies(opt, cstr, &ccfds[0], C.int(len(ccfds)), &cfh, cdbttl, &cttls[0], toCBool(false))
+		stat = cstat.toStatus()
+		if stat.Ok() {
+			cfhs = newColumnFamilyHandleArrayFromCArray(dbttl.DB, cfh, uint(len(ccfds)))
+		} else {
+			C.DeleteColumnFamilyHandleTArray(cfh)
+		}
+	} else {
+		cstat := C.DBWithTTLOpen(opt, cstr, cdbttl, C.int32_t(ttl), toCBool(false))
+		stat = cstat.toStatus()
+	}
+
+	if stat.Ok() {
+		C.DBWithTTLTStaticCastToDBT(cdbttl, &dbttl.db)
+		runtime.SetFinalizer(dbttl.DB, finalize)
+	}
+
+	return
+}
+
+// Create a column_family with ttl and return the handle of column family
+// through the argument handle.
+func (dbttl *DBWithTTL) CreateColumnFamilyWithTTL(options *ColumnFamilyOptions, colfname *string, ttl int32) (cfh *ColumnFamilyHandle, stat *Status) {
+	if dbttl.closed {
+		stat = NewDBClosedStatus()
+		return
+	}
+
+	cstr := newCStringFromString(colfname)
+	defer cstr.del()
+
+	var (
+		cdbttl *C.DBWithTTL_t = &dbttl.dbttl
+		opt *C.ColumnFamilyOptions_t = &options.cfopt
+		ccstr *C.String_t = &cstr.str
+		ccfh C.ColumnFamilyHandle_t
+	)
+
+	cstat := C.DBWithTTLCreateColumnFamilyWithTtl(cdbttl, opt, ccstr, &ccfh, C.int(ttl))
+	stat = cstat.toStatus()
+	if stat.Ok() {
+		cfh = ccfh.toColumnFamilyHandle(dbttl.DB)
+	}
+	return
+}
@@ -0,0 +1,50 @@
+// Copyright (c) 2015, Dean ChaoJun Pan.  All rights reserved.
+// This source code is licensed under the BSD-style license found in the
+// LICENSE file in the root directory of this source tree.
+
+#ifndef GO_ROCKSDB_INCLUDE_DB_TTL_H_
+#define GO_ROCKSDB_INCLUDE_DB_TTL_H_
+
+#include "types.h"
+#include "db.h"
+
+#ifdef __cplusplus
+extern "C" {
+#endif
+
+// Database with TTL support.
+DEFINE_C_WRAP_STRUCT(DBWithTTL)
+DEFINE_C_WRAP_CONSTRUCTOR_DEC(DBWithTTL)
+DEFINE_C_WRAP_STATIC_CAST_DEC(DBWithTTL, DB)
+
+// Open a DBWithTTL similar to DB::Open(). Entries older than ttl
+// seconds are removed during compaction. ttl <= 0 means infinity.
+Status_t DBWithTTLOpen(const Options_t* options,
+                       const String_t* name,
+                       DBWithTTL_t* dbptr,
+                       int32_t ttl,
+                       bool read_only);
+
+// Open a DBWithTTL with column families similar to DB::Open().
+// ttls[i] is the ttl of column_families[i].
+Status_t DBWithTTLOpenWithColumnFamilies(const Options_t* options,
+                                         const String_t* name,
+                                         const ColumnFamilyDescriptor_t column_families[], const int size_col,
+                                         ColumnFamilyHandle_t **handles,
+                                         DBWithTTL_t* dbptr,
+                                         const int32_t ttls[],
+                                         bool read_only);
+
+// Create a column_family with ttl and return the handle of column family
+// through the argument handle.
+Status_t DBWithTTLCreateColumnFamilyWithTtl(DBWithTTL_t* dbptr,
+                                            const ColumnFamilyOptions_t* options,
+                                            const String_t* column_family_name,
+                                            ColumnFamilyHandle_t* handle,
+                                            int ttl);
+
+#ifdef __cplusplus
+}  /* end extern "C" */
+#endif
+
+#endif  // GO_ROCKSDB_INCLUDE_DB_TTL_H_
@@ -10,12 +10,18 @@
 // All Env implementations are safe for concurrent access from
 // multiple threads without any external synchronization.
 
+#include <stdarg.h>
+#include <stdio.h>
 #include <rocksdb/env.h>
 #include "env.h"
 #include "envPrivate.h"
 
 using namespace rocksdb;
 
+extern "C" {
+#include "_cgo_export.h"
+}
+
 DEFINE_C_WRAP_CONSTRUCTOR(Env)
 DEFINE_C_WRAP_DESTRUCTOR(Env)
 // Return a default environment suitable for the current operating
@@ -30,6 +36,17 @@ Env_t NewEnvDefault()
     return ret;
 }
 
+// Returns a new environment that stores its data in memory and delegates
+// all non-file-storage tasks to base. The Env::Default() is used if
+// base is NULL. The caller must delete the result when it is
+// no longer needed. Returns NULL in ROCKSDB_LITE.
+Env_t NewMemEnvT(Env_t* base)
+{
+    Env_t ret;
+    ret.rep = NewMemEnv((base && GET_REP(base, Env)) ? GET_REP(base, Env) : Env::Default());
+    return ret;
+}
+
 
 DEFINE_C_WRAP_CONSTRUCTOR(Logger)
 DEFINE_C_WRAP_DESTRUCTOR(Logger)
@@ -123,3 +140,82 @@ void LoggerFatal(Logger_t* info_log, const char* msg)
 DEFINE_C_WRAP_CONSTRUCTOR(PLogger)
 DEFINE_C_WRAP_DESTRUCTOR(PLogger)
 DEFINE_C_WRAP_CONSTRUCTOR_DEFAULT(PLogger)
+
+// C++ wrap class for go ILogger
+// The log lines are formatted and passed to go with their levels.
+class LoggerGo : public Logger {
+public:
+    LoggerGo(void* go_logger, const InfoLogLevel log_level)
+        : Logger(log_level)
+        , m_go_logger(go_logger)
+    {
+    }
+
+    // Destructor
+    ~LoggerGo()
+    {
+        if (m_go_logger)
+        {
+            InterfacesRemoveReference(m_go_logger);
+        }
+    }
+
+    // Write an entry to the log file with the specified format.
+    // The entries without a level are logged at INFO_LEVEL.
+    virtual void Logv(const char* format, va_list ap) override
+    {
+        Logv(InfoLogLevel::INFO_LEVEL, format, ap);
+    }
+
+    // Write an entry to the log file with the specified log level
+    // and format.  Any log with level under the internal log level
+    // of *this (see @SetInfoLogLevel and @GetInfoLogLevel) will not be
+    // printed.
+    virtual void Logv(const InfoLogLevel log_level, const char* format, va_list ap) override
+    {
+        if (log_level < GetInfoLogLevel())
+        {
+            return;
+        }
+
+        // Try a stack buffer first, and a heap buffer of the exact
+        // size if the line does not fit.
+        char buf[512];
+        va_list backup_ap;
+        va_copy(backup_ap, ap);
+        int len = vsnprintf(buf, sizeof(buf), format, backup_ap);
+        va_end(backup_ap);
+        if (len < 0)
+        {
+            return;
+        }
+
+        if (static_cast<size_t>(len) < sizeof(buf))
+        {
+            ILoggerLogv(m_go_logger, log_level, buf, len);
+        }
+        else
+        {
+            std::string line(len + 1, '\0');
+            va_copy(backup_ap, ap);
+            vsnprintf(&line[0], line.size(), format, backup_ap);
+            va_end(backup_ap);
+            ILoggerLogv(m_go_logger, log_level, &line[0], len);
+        }
+    }
+
+private:
+    // Wrapped go ILogger
+    void* m_go_logger;
+};
+
+// Return a PLogger calling back the go ILogger with the log lines
+// at a level no lower than log_level.
+PLogger_t NewPLoggerGo(void* go_logger, int log_level)
+{
+    PLogger_t wrap_t;
+    wrap_t.rep = (go_logger ?
+                  new PLogger(std::make_shared<LoggerGo>(go_logger, InfoLogLevel(log_level))) :
+                  new PLogger());
+    return wrap_t;
+}
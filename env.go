@@ -21,6 +21,7 @@ import "C"
 import (
 	"runtime"
 	"fmt"
+	"log"
 	"unsafe"
 )
 
@@ -36,6 +37,8 @@ const (
 // Wrap go Env
 type Env struct {
 	env C.Env_t
+	// Keep the Env wrapped by env from garbage collected
+	base *Env
 }
 
 // Release resources
@@ -65,6 +68,27 @@ func NewEnvDefault() (env *Env) {
 	return cenv.toEnv(false)
 }
 
+// Returns a new environment that stores its data in memory and delegates
+// all non-file-storage tasks to base, or the default environment if base
+// is nil. A DB opened with it does no disk I/O for its files and is lost
+// with the returned Env, which is kept alive by the options it is set to
+// and by the DBs opened with them.
+// Returns nil in lite mode.
+func NewMemEnv(base *Env) (env *Env) {
+	var cbase *C.Env_t = nil
+
+	if nil != base {
+		cbase = &base.env
+	}
+	cenv := C.NewMemEnvT(cbase)
+	if nil == cenv.rep {
+		return
+	}
+	env = cenv.toEnv(true)
+	env.base = base
+	return
+}
+
 // Wrap go Logger
 type Logger struct {
 	log C.Logger_t
@@ -92,8 +116,8 @@ func (log *Logger) Flush() {
 }
 
 // Return the log level    
-func (log *Logger) GetInfoLogLevel() {
-	C.LoggerGetInfoLogLevel(&log.log)
+func (log *Logger) GetInfoLogLevel() int {
+	return int(C.LoggerGetInfoLogLevel(&log.log))
 }
 
 // Set the log level. The level lower will not be logged.    
@@ -122,6 +146,13 @@ func (log *Logger) Info(format string, a ...interface{}) {
 	C.LoggerInfo(&log.log, str)
 }
 
+// log functions with Warn log levels.
+func (log *Logger) Warn(format string, a ...interface{}) {
+	str := C.CString(fmt.Sprintf(format, a...))
+	defer C.free(unsafe.Pointer(str))
+	C.LoggerWarn(&log.log, str)
+}
+
 // log functions with Error log levels.
 func (log *Logger) Error(format string, a ...interface{}) {
 	str := C.CString(fmt.Sprintf(format, a...))
@@ -163,3 +194,82 @@ func NewPLoggerDefault() (plog *PLogger) {
 	plog = cplog.toPLogger(true)
 	return
 }
+
+// ILogger receives the info log lines of rocksdb. Set it to the
+// options with SetInfoLog(NewPLogger(itf)) to route the info log
+// into a go logger instead of the LOG file in the DB directory.
+//
+// Logv is called from the threads of rocksdb concurrently, so it
+// must be thread-safe.
+type ILogger interface {
+	// Write a log line at the level, one of DEBUG_LEVEL,
+	// INFO_LEVEL, WARN_LEVEL, ERROR_LEVEL and FATAL_LEVEL.
+	Logv(level int, msg string)
+}
+
+// Wrap functions for ILogger
+
+//export ILoggerLogv
+func ILoggerLogv(clog unsafe.Pointer, level C.int, msg *C.char, n C.int) {
+	log := InterfacesGet(clog).(ILogger)
+	log.Logv(int(level), C.GoStringN(msg, n))
+}
+
+// Return a PLogger passing the log lines at a level no lower than
+// level to ILogger. The level is INFO_LEVEL if not given.
+func NewPLogger(itf ILogger, level ...int) (plog *PLogger) {
+	var (
+		citf unsafe.Pointer = nil
+		clevel C.int = INFO_LEVEL
+	)
+
+	if nil != itf {
+		citf = InterfacesAddReference(itf)
+	}
+	if len(level) > 0 {
+		clevel = C.int(level[0])
+	}
+	cplog := C.NewPLoggerGo(citf, clevel)
+	plog = cplog.toPLogger(true)
+	return
+}
+
+// The name of the log level
+func InfoLogLevelName(level int) string {
+	switch level {
+	case DEBUG_LEVEL:
+		return "DEBUG"
+	case INFO_LEVEL:
+		return "INFO"
+	case WARN_LEVEL:
+		return "WARN"
+	case ERROR_LEVEL:
+		return "ERROR"
+	case FATAL_LEVEL:
+		return "FATAL"
+	}
+	return "UNKNOWN"
+}
+
+// StdLogger is an ILogger writing the log lines prefixed with
+// their level names to a go log.Logger.
+type StdLogger struct {
+	logger *log.Logger
+}
+
+// Return a StdLogger writing to logger, or the standard logger
+// of the log package if logger is nil.
+func NewStdLogger(logger *log.Logger) *StdLogger {
+	return &StdLogger{logger: logger}
+}
+
+// Write a log line at the level.
+func (sl *StdLogger) Logv(level int, msg string) {
+	line := "[" + InfoLogLevelName(level) + "] " + msg
+	if nil == sl.logger {
+		log.Print(line)
+	} else {
+		sl.logger.Print(line)
+	}
+}
+
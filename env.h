@@ -20,6 +20,11 @@ DEFINE_C_WRAP_DESTRUCTOR_DEC(Env)
 //
 // The result of Default() belongs to rocksdb and must never be deleted.
 Env_t NewEnvDefault();
+// Returns a new environment that stores its data in memory and delegates
+// all non-file-storage tasks to base. The Env::Default() is used if
+// base is NULL. The caller must delete the result when it is
+// no longer needed. Returns NULL in ROCKSDB_LITE.
+Env_t NewMemEnvT(Env_t* base);
 
 DEFINE_C_WRAP_STRUCT(Logger)
 DEFINE_C_WRAP_CONSTRUCTOR_DEC(Logger)
@@ -43,6 +48,9 @@ DEFINE_C_WRAP_STRUCT(PLogger)
 DEFINE_C_WRAP_CONSTRUCTOR_DEC(PLogger)
 DEFINE_C_WRAP_CONSTRUCTOR_DEFAULT_DEC(PLogger)
 DEFINE_C_WRAP_DESTRUCTOR_DEC(PLogger)
+// Return a PLogger calling back the go ILogger with the log lines
+// at a level no lower than log_level.
+PLogger_t NewPLoggerGo(void* go_logger, int log_level);
 
 #ifdef __cplusplus
 }  /* end extern "C" */
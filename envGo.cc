@@ -0,0 +1,472 @@
+// Copyright (c) 2015, Dean ChaoJun Pan.  All rights reserved.
+// This source code is licensed under the BSD-style license found in the
+// LICENSE file in the root directory of this source tree.
+//
+// An Env backed by a go IEnv. The file system and clock operations are
+// called back to go, everything else is forwarded to the wrapped Env.
+
+#include <rocksdb/env.h>
+
+using namespace rocksdb;
+
+#include "envGo.h"
+#include "cstring.h"
+#include "slice.h"
+#include "status.h"
+
+extern "C" {
+#include "_cgo_export.h"
+}
+
+// Return the Status held by the Status_t returned by go and
+// delete the Status_t
+static Status MoveStatusT(Status_t& cstat)
+{
+    Status ret = (GET_REP(&cstat, Status) ? GET_REP_REF(&cstat, Status) : invalid_status);
+    DeleteStatusT(&cstat, false);
+    return ret;
+}
+
+// C++ wrap class for go ISequentialFile
+// A file abstraction for reading sequentially through a file
+class SequentialFileGo : public SequentialFile {
+public:
+    SequentialFileGo(void* go_file)
+        : m_go_file(go_file)
+    {
+    }
+
+    // Destructor
+    ~SequentialFileGo()
+    {
+        if (m_go_file)
+        {
+            InterfacesRemoveReference(m_go_file);
+        }
+    }
+
+    // Read up to "n" bytes from the file.  "scratch[0..n-1]" may be
+    // written by this routine.  Sets "*result" to the data that was
+    // read (including if fewer than "n" bytes were successfully read).
+    // If an error was encountered, returns a non-OK status.
+    virtual Status Read(size_t n, Slice* result, char* scratch) override
+    {
+        size_t sz = 0;
+        Status_t cstat = ISequentialFileRead(m_go_file, scratch, n, &sz);
+        *result = Slice(scratch, sz);
+        return MoveStatusT(cstat);
+    }
+
+    // Skip "n" bytes from the file. This is guaranteed to be no
+    // slower that reading the same data, but may be faster.
+    virtual Status Skip(uint64_t n) override
+    {
+        Status_t cstat = ISequentialFileSkip(m_go_file, n);
+        return MoveStatusT(cstat);
+    }
+
+private:
+    // Wrapped go ISequentialFile
+    void* m_go_file;
+};
+
+// C++ wrap class for go IRandomAccessFile
+// A file abstraction for randomly reading the contents of a file.
+class RandomAccessFileGo : public RandomAccessFile {
+public:
+    RandomAccessFileGo(void* go_file)
+        : m_go_file(go_file)
+    {
+    }
+
+    // Destructor
+    ~RandomAccessFileGo()
+    {
+        if (m_go_file)
+        {
+            InterfacesRemoveReference(m_go_file);
+        }
+    }
+
+    // Read up to "n" bytes from the file starting at "offset".
+    // "scratch[0..n-1]" may be written by this routine.  Sets "*result"
+    // to the data that was read (including if fewer than "n" bytes were
+    // successfully read).
+    //
+    // Safe for concurrent use by multiple threads.
+    virtual Status Read(uint64_t offset, size_t n, Slice* result, char* scratch) const override
+    {
+        size_t sz = 0;
+        Status_t cstat = IRandomAccessFileRead(m_go_file, offset, scratch, n, &sz);
+        *result = Slice(scratch, sz);
+        return MoveStatusT(cstat);
+    }
+
+private:
+    // Wrapped go IRandomAccessFile
+    void* m_go_file;
+};
+
+// C++ wrap class for go IWritableFile
+// A file abstraction for sequential writing.
+class WritableFileGo : public WritableFile {
+public:
+    WritableFileGo(void* go_file)
+        : m_go_file(go_file)
+    {
+    }
+
+    // Destructor
+    ~WritableFileGo()
+    {
+        if (m_go_file)
+        {
+            InterfacesRemoveReference(m_go_file);
+        }
+    }
+
+    // Append data to the end of the file
+    virtual Status Append(const Slice& data) override
+    {
+        Slice_t data_slc{const_cast<Slice *>(&data)};
+        Status_t cstat = IWritableFileAppend(m_go_file, &data_slc);
+        return MoveStatusT(cstat);
+    }
+
+    // Close the file
+    virtual Status Close() override
+    {
+        Status_t cstat = IWritableFileClose(m_go_file);
+        return MoveStatusT(cstat);
+    }
+
+    // Flush the buffered data
+    virtual Status Flush() override
+    {
+        Status_t cstat = IWritableFileFlush(m_go_file);
+        return MoveStatusT(cstat);
+    }
+
+    // Sync data to the storage
+    virtual Status Sync() override
+    {
+        Status_t cstat = IWritableFileSync(m_go_file);
+        return MoveStatusT(cstat);
+    }
+
+private:
+    // Wrapped go IWritableFile
+    void* m_go_file;
+};
+
+// C++ wrap class for go IDirectory
+// Directory object represents collection of files and implements
+// filesystem operations that can be executed on directories.
+class DirectoryGo : public Directory {
+public:
+    DirectoryGo(void* go_dir)
+        : m_go_dir(go_dir)
+    {
+    }
+
+    // Destructor
+    ~DirectoryGo()
+    {
+        if (m_go_dir)
+        {
+            InterfacesRemoveReference(m_go_dir);
+        }
+    }
+
+    // Fsync directory. Can be called concurrently from multiple threads.
+    virtual Status Fsync() override
+    {
+        Status_t cstat = IDirectoryFsync(m_go_dir);
+        return MoveStatusT(cstat);
+    }
+
+private:
+    // Wrapped go IDirectory
+    void* m_go_dir;
+};
+
+// C++ wrap class for the lock returned by go IEnv
+// Identifies a locked file.
+class FileLockGo : public FileLock {
+public:
+    FileLockGo(void* go_lock)
+        : m_go_lock(go_lock)
+    {
+    }
+
+    // Destructor
+    ~FileLockGo()
+    {
+        if (m_go_lock)
+        {
+            InterfacesRemoveReference(m_go_lock);
+        }
+    }
+
+    // Wrapped go lock
+    void* m_go_lock;
+};
+
+// C++ wrap class for go IEnv
+// The file system and clock operations are called back to go IEnv.
+// All the other operations are forwarded to the wrapped Env.
+class EnvGo : public EnvWrapper {
+public:
+    EnvGo(Env* base, void* go_env)
+        : EnvWrapper(base)
+        , m_go_env(go_env)
+    {
+    }
+
+    // Destructor
+    ~EnvGo()
+    {
+        if (m_go_env)
+        {
+            InterfacesRemoveReference(m_go_env);
+        }
+    }
+
+    // Create a brand new sequentially-readable file with the specified name.
+    virtual Status NewSequentialFile(const std::string& fname,
+                                     std::unique_ptr<SequentialFile>* result,
+                                     const EnvOptions& options) override
+    {
+        String_t fname_str{const_cast<String *>(&fname)};
+        void* go_file = nullptr;
+        Status_t cstat = IEnvNewSequentialFile(m_go_env, &fname_str, &go_file);
+        Status ret = MoveStatusT(cstat);
+        if (ret.ok())
+        {
+            result->reset(new SequentialFileGo(go_file));
+        }
+        else if (go_file)
+        {
+            InterfacesRemoveReference(go_file);
+        }
+        return ret;
+    }
+
+    // Create a brand new random access read-only file with the
+    // specified name.
+    virtual Status NewRandomAccessFile(const std::string& fname,
+                                       std::unique_ptr<RandomAccessFile>* result,
+                                       const EnvOptions& options) override
+    {
+        String_t fname_str{const_cast<String *>(&fname)};
+        void* go_file = nullptr;
+        Status_t cstat = IEnvNewRandomAccessFile(m_go_env, &fname_str, &go_file);
+        Status ret = MoveStatusT(cstat);
+        if (ret.ok())
+        {
+            result->reset(new RandomAccessFileGo(go_file));
+        }
+        else if (go_file)
+        {
+            InterfacesRemoveReference(go_file);
+        }
+        return ret;
+    }
+
+    // Create an object that writes to a new file with the specified
+    // name.  Deletes any existing file with the same name and creates a
+    // new file.
+    virtual Status NewWritableFile(const std::string& fname,
+                                   std::unique_ptr<WritableFile>* result,
+                                   const EnvOptions& options) override
+    {
+        String_t fname_str{const_cast<String *>(&fname)};
+        void* go_file = nullptr;
+        Status_t cstat = IEnvNewWritableFile(m_go_env, &fname_str, &go_file);
+        Status ret = MoveStatusT(cstat);
+        if (ret.ok())
+        {
+            result->reset(new WritableFileGo(go_file));
+        }
+        else if (go_file)
+        {
+            InterfacesRemoveReference(go_file);
+        }
+        return ret;
+    }
+
+    // Create an object that represents a directory. Will fail if directory
+    // doesn't exist.
+    virtual Status NewDirectory(const std::string& name,
+                                std::unique_ptr<Directory>* result) override
+    {
+        String_t name_str{const_cast<String *>(&name)};
+        void* go_dir = nullptr;
+        Status_t cstat = IEnvNewDirectory(m_go_env, &name_str, &go_dir);
+        Status ret = MoveStatusT(cstat);
+        if (ret.ok())
+        {
+            result->reset(new DirectoryGo(go_dir));
+        }
+        else if (go_dir)
+        {
+            InterfacesRemoveReference(go_dir);
+        }
+        return ret;
+    }
+
+    // Returns OK if the named file exists.
+    //         NotFound if the named file does not exist,
+    //                  the calling process does not have permission to determine
+    //                  whether this file exists, or if the path is invalid.
+    //         IOError if an IO Error was encountered
+    virtual Status FileExists(const std::string& fname) override
+    {
+        String_t fname_str{const_cast<String *>(&fname)};
+        Status_t cstat = IEnvFileExists(m_go_env, &fname_str);
+        return MoveStatusT(cstat);
+    }
+
+    // Store in *result the names of the children of the specified directory.
+    // The names are relative to "dir".
+    // Original contents of *results are dropped.
+    virtual Status GetChildren(const std::string& dir,
+                               std::vector<std::string>* result) override
+    {
+        String_t dir_str{const_cast<String *>(&dir)};
+        StringVector_t result_strv{result};
+        result->clear();
+        Status_t cstat = IEnvGetChildren(m_go_env, &dir_str, &result_strv);
+        return MoveStatusT(cstat);
+    }
+
+    // Delete the named file.
+    virtual Status DeleteFile(const std::string& fname) override
+    {
+        String_t fname_str{const_cast<String *>(&fname)};
+        Status_t cstat = IEnvDeleteFile(m_go_env, &fname_str);
+        return MoveStatusT(cstat);
+    }
+
+    // Create the specified directory. Returns error if directory exists.
+    virtual Status CreateDir(const std::string& dirname) override
+    {
+        String_t dirname_str{const_cast<String *>(&dirname)};
+        Status_t cstat = IEnvCreateDir(m_go_env, &dirname_str);
+        return MoveStatusT(cstat);
+    }
+
+    // Creates directory if missing. Return Ok if it exists, or successful in
+    // Creating.
+    virtual Status CreateDirIfMissing(const std::string& dirname) override
+    {
+        String_t dirname_str{const_cast<String *>(&dirname)};
+        Status_t cstat = IEnvCreateDirIfMissing(m_go_env, &dirname_str);
+        return MoveStatusT(cstat);
+    }
+
+    // Delete the specified directory.
+    virtual Status DeleteDir(const std::string& dirname) override
+    {
+        String_t dirname_str{const_cast<String *>(&dirname)};
+        Status_t cstat = IEnvDeleteDir(m_go_env, &dirname_str);
+        return MoveStatusT(cstat);
+    }
+
+    // Store the size of fname in *file_size.
+    virtual Status GetFileSize(const std::string& fname, uint64_t* file_size) override
+    {
+        String_t fname_str{const_cast<String *>(&fname)};
+        Status_t cstat = IEnvGetFileSize(m_go_env, &fname_str, file_size);
+        return MoveStatusT(cstat);
+    }
+
+    // Store the last modification time of fname in *file_mtime.
+    virtual Status GetFileModificationTime(const std::string& fname,
+                                           uint64_t* file_mtime) override
+    {
+        String_t fname_str{const_cast<String *>(&fname)};
+        Status_t cstat = IEnvGetFileModificationTime(m_go_env, &fname_str, file_mtime);
+        return MoveStatusT(cstat);
+    }
+
+    // Rename file src to target.
+    virtual Status RenameFile(const std::string& src,
+                              const std::string& target) override
+    {
+        String_t src_str{const_cast<String *>(&src)};
+        String_t target_str{const_cast<String *>(&target)};
+        Status_t cstat = IEnvRenameFile(m_go_env, &src_str, &target_str);
+        return MoveStatusT(cstat);
+    }
+
+    // Hard Link file src to target.
+    virtual Status LinkFile(const std::string& src,
+                            const std::string& target) override
+    {
+        String_t src_str{const_cast<String *>(&src)};
+        String_t target_str{const_cast<String *>(&target)};
+        Status_t cstat = IEnvLinkFile(m_go_env, &src_str, &target_str);
+        return MoveStatusT(cstat);
+    }
+
+    // Lock the specified file.  Used to prevent concurrent access to
+    // the same db by multiple processes.  On failure, stores nullptr in
+    // *lock and returns non-OK.
+    virtual Status LockFile(const std::string& fname, FileLock** lock) override
+    {
+        String_t fname_str{const_cast<String *>(&fname)};
+        void* go_lock = nullptr;
+        *lock = nullptr;
+        Status_t cstat = IEnvLockFile(m_go_env, &fname_str, &go_lock);
+        Status ret = MoveStatusT(cstat);
+        if (ret.ok())
+        {
+            *lock = new FileLockGo(go_lock);
+        }
+        else if (go_lock)
+        {
+            InterfacesRemoveReference(go_lock);
+        }
+        return ret;
+    }
+
+    // Release the lock acquired by a previous successful call to LockFile.
+    // REQUIRES: lock was returned by a successful LockFile() call
+    // REQUIRES: lock has not already been unlocked.
+    virtual Status UnlockFile(FileLock* lock) override
+    {
+        FileLockGo* go_lock = static_cast<FileLockGo *>(lock);
+        Status_t cstat = IEnvUnlockFile(m_go_env, go_lock->m_go_lock);
+        delete go_lock;
+        return MoveStatusT(cstat);
+    }
+
+    // Returns the number of micro-seconds since some fixed point in time.
+    virtual uint64_t NowMicros() override
+    {
+        return IEnvNowMicros(m_go_env);
+    }
+
+    // Returns the number of nano-seconds since some fixed point in time.
+    virtual uint64_t NowNanos() override
+    {
+        return IEnvNowNanos(m_go_env);
+    }
+
+private:
+    // Wrapped go IEnv
+    void* m_go_env;
+};
+
+// Return an Env calling back the go IEnv for the file system and
+// clock operations. All the other operations, e.g. scheduling the
+// background jobs and creating the info logs, are forwarded to base.
+// The Env::Default() is used if base is NULL.
+Env_t NewEnvGo(Env_t* base, void* go_env)
+{
+    Env_t wrap_t;
+    Env* base_env = ((base && GET_REP(base, Env)) ? GET_REP(base, Env) : Env::Default());
+    wrap_t.rep = (go_env ? new EnvGo(base_env, go_env) : NULL);
+    return wrap_t;
+}
@@ -0,0 +1,369 @@
+// Copyright (c) 2015, Dean ChaoJun Pan.  All rights reserved.
+// This source code is licensed under the BSD-style license found in the
+// LICENSE file in the root directory of this source tree.
+//
+// An Env backed by a go IEnv. The file system and clock operations are
+// called back to go, everything else is forwarded to the wrapped Env.
+
+package rocksdb
+
+/*
+#include <stdlib.h>
+#include "envGo.h"
+#include "cstring.h"
+#include "slice.h"
+#include "status.h"
+*/
+import "C"
+
+import (
+	"unsafe"
+)
+
+// A file abstraction for reading sequentially through a file
+type ISequentialFile interface {
+	// Read up to len(buf) bytes from the file into buf. Returns the
+	// number of bytes read, which is less than len(buf) only at the
+	// end of the file. buf must not be retained after Read returns.
+	Read(buf []byte) (n int, stat *Status)
+
+	// Skip "n" bytes from the file.
+	Skip(n uint64) *Status
+}
+
+// A file abstraction for randomly reading the contents of a file.
+type IRandomAccessFile interface {
+	// Read up to len(buf) bytes from the file starting at offset into
+	// buf. Returns the number of bytes read. buf must not be retained
+	// after ReadAt returns.
+	//
+	// Safe for concurrent use by multiple threads.
+	ReadAt(buf []byte, offset uint64) (n int, stat *Status)
+}
+
+// A file abstraction for sequential writing.
+type IWritableFile interface {
+	// Append data to the end of the file.
+	Append(data []byte) *Status
+	// Close the file.
+	Close() *Status
+	// Flush the buffered data.
+	Flush() *Status
+	// Sync data to the storage.
+	Sync() *Status
+}
+
+// Directory object represents collection of files and implements
+// filesystem operations that can be executed on directories.
+type IDirectory interface {
+	// Fsync directory. Can be called concurrently from multiple threads.
+	Fsync() *Status
+}
+
+// IEnv implements the file system and clock operations of an Env in go.
+// A nil *Status returned by any of the methods is a success status.
+//
+// All IEnv implementations must be safe for concurrent access from
+// multiple threads without any external synchronization.
+type IEnv interface {
+	// Create a brand new sequentially-readable file with the specified name.
+	// Returns a NotFound status if the file does not exist.
+	NewSequentialFile(name string) (ISequentialFile, *Status)
+
+	// Create a brand new random access read-only file with the
+	// specified name. Returns a NotFound status if the file does not exist.
+	NewRandomAccessFile(name string) (IRandomAccessFile, *Status)
+
+	// Create an object that writes to a new file with the specified
+	// name.  Deletes any existing file with the same name and creates a
+	// new file.
+	NewWritableFile(name string) (IWritableFile, *Status)
+
+	// Create an object that represents a directory. Will fail if directory
+	// doesn't exist.
+	NewDirectory(name string) (IDirectory, *Status)
+
+	// Returns OK if the named file exists, NotFound if it does not.
+	FileExists(name string) *Status
+
+	// The names of the children of the specified directory.
+	// The names are relative to "dir".
+	GetChildren(dir string) ([]string, *Status)
+
+	// Delete the named file.
+	DeleteFile(name string) *Status
+
+	// Create the specified directory. Returns error if directory exists.
+	CreateDir(name string) *Status
+
+	// Creates directory if missing. Return Ok if it exists, or successful in
+	// Creating.
+	CreateDirIfMissing(name string) *Status
+
+	// Delete the specified directory.
+	DeleteDir(name string) *Status
+
+	// The size of the named file.
+	GetFileSize(name string) (uint64, *Status)
+
+	// The last modification time of the named file.
+	GetFileModificationTime(name string) (uint64, *Status)
+
+	// Rename file src to target.
+	RenameFile(src, target string) *Status
+
+	// Hard Link file src to target.
+	LinkFile(src, target string) *Status
+
+	// Lock the specified file.  Used to prevent concurrent access to
+	// the same db by multiple processes. The returned lock is passed
+	// to UnlockFile to release it.
+	LockFile(name string) (lock interface{}, stat *Status)
+
+	// Release the lock acquired by a previous successful call to LockFile.
+	UnlockFile(lock interface{}) *Status
+
+	// Returns the number of micro-seconds since some fixed point in time.
+	NowMicros() uint64
+
+	// Returns the number of nano-seconds since some fixed point in time.
+	NowNanos() uint64
+}
+
+// Return a new Env that uses IEnv for the file system and clock
+// operations. All the other operations, e.g. scheduling the background
+// jobs and creating the info logs, are forwarded to base, or the
+// default Env if base is not given. Returns nil if itf is nil.
+func NewEnv(itf IEnv, base ...*Env) (env *Env) {
+	if nil == itf {
+		return
+	}
+
+	var (
+		citf unsafe.Pointer = InterfacesAddReference(itf)
+		cbase *C.Env_t = nil
+	)
+
+	if len(base) > 0 && nil != base[0] {
+		cbase = &base[0].env
+	}
+	cenv := C.NewEnvGo(cbase, citf)
+	env = cenv.toEnv(true)
+	if nil != cbase {
+		env.base = base[0]
+	}
+	return
+}
+
+// Go bytes over the C buffer of size n
+func newBytesFromCBuffer(cbuf *C.char, n C.size_t) []byte {
+	return (*[arrayDimenMax]byte)(unsafe.Pointer(cbuf))[:n:n]
+}
+
+// Wrap functions for ISequentialFile
+
+//export ISequentialFileRead
+func ISequentialFileRead(cfile unsafe.Pointer, scratch *C.char, n C.size_t, cread *C.size_t) C.Status_t {
+	file := InterfacesGet(cfile).(ISequentialFile)
+	read, stat := file.Read(newBytesFromCBuffer(scratch, n))
+	*cread = C.size_t(read)
+	return stat.newCStatus()
+}
+
+//export ISequentialFileSkip
+func ISequentialFileSkip(cfile unsafe.Pointer, n C.uint64_t) C.Status_t {
+	file := InterfacesGet(cfile).(ISequentialFile)
+	return file.Skip(uint64(n)).newCStatus()
+}
+
+// Wrap functions for IRandomAccessFile
+
+//export IRandomAccessFileRead
+func IRandomAccessFileRead(cfile unsafe.Pointer, offset C.uint64_t, scratch *C.char, n C.size_t, cread *C.size_t) C.Status_t {
+	file := InterfacesGet(cfile).(IRandomAccessFile)
+	read, stat := file.ReadAt(newBytesFromCBuffer(scratch, n), uint64(offset))
+	*cread = C.size_t(read)
+	return stat.newCStatus()
+}
+
+// Wrap functions for IWritableFile
+
+//export IWritableFileAppend
+func IWritableFileAppend(cfile unsafe.Pointer, data *C.Slice_t) C.Status_t {
+	file := InterfacesGet(cfile).(IWritableFile)
+	return file.Append(data.cToBytes(false)).newCStatus()
+}
+
+//export IWritableFileClose
+func IWritableFileClose(cfile unsafe.Pointer) C.Status_t {
+	file := InterfacesGet(cfile).(IWritableFile)
+	return file.Close().newCStatus()
+}
+
+//export IWritableFileFlush
+func IWritableFileFlush(cfile unsafe.Pointer) C.Status_t {
+	file := InterfacesGet(cfile).(IWritableFile)
+	return file.Flush().newCStatus()
+}
+
+//export IWritableFileSync
+func IWritableFileSync(cfile unsafe.Pointer) C.Status_t {
+	file := InterfacesGet(cfile).(IWritableFile)
+	return file.Sync().newCStatus()
+}
+
+// Wrap functions for IDirectory
+
+//export IDirectoryFsync
+func IDirectoryFsync(cdir unsafe.Pointer) C.Status_t {
+	dir := InterfacesGet(cdir).(IDirectory)
+	return dir.Fsync().newCStatus()
+}
+
+// Wrap functions for IEnv
+
+//export IEnvNewSequentialFile
+func IEnvNewSequentialFile(cenv unsafe.Pointer, cname *C.String_t, cfile *unsafe.Pointer) C.Status_t {
+	env := InterfacesGet(cenv).(IEnv)
+	file, stat := env.NewSequentialFile(string(cname.cToBytes(false)))
+	if (nil == stat || stat.Ok()) && nil != file {
+		*cfile = InterfacesAddReference(file)
+	} else if nil == stat || stat.Ok() {
+		stat = NewIOErrorStatus("IEnv.NewSequentialFile returned no file")
+	}
+	return stat.newCStatus()
+}
+
+//export IEnvNewRandomAccessFile
+func IEnvNewRandomAccessFile(cenv unsafe.Pointer, cname *C.String_t, cfile *unsafe.Pointer) C.Status_t {
+	env := InterfacesGet(cenv).(IEnv)
+	file, stat := env.NewRandomAccessFile(string(cname.cToBytes(false)))
+	if (nil == stat || stat.Ok()) && nil != file {
+		*cfile = InterfacesAddReference(file)
+	} else if nil == stat || stat.Ok() {
+		stat = NewIOErrorStatus("IEnv.NewRandomAccessFile returned no file")
+	}
+	return stat.newCStatus()
+}
+
+//export IEnvNewWritableFile
+func IEnvNewWritableFile(cenv unsafe.Pointer, cname *C.String_t, cfile *unsafe.Pointer) C.Status_t {
+	env := InterfacesGet(cenv).(IEnv)
+	file, stat := env.NewWritableFile(string(cname.cToBytes(false)))
+	if (nil == stat || stat.Ok()) && nil != file {
+		*cfile = InterfacesAddReference(file)
+	} else if nil == stat || stat.Ok() {
+		stat = NewIOErrorStatus("IEnv.NewWritableFile returned no file")
+	}
+	return stat.newCStatus()
+}
+
+//export IEnvNewDirectory
+func IEnvNewDirectory(cenv unsafe.Pointer, cname *C.String_t, cdir *unsafe.Pointer) C.Status_t {
+	env := InterfacesGet(cenv).(IEnv)
+	dir, stat := env.NewDirectory(string(cname.cToBytes(false)))
+	if (nil == stat || stat.Ok()) && nil != dir {
+		*cdir = InterfacesAddReference(dir)
+	} else if nil == stat || stat.Ok() {
+		stat = NewIOErrorStatus("IEnv.NewDirectory returned no directory")
+	}
+	return stat.newCStatus()
+}
+
+//export IEnvFileExists
+func IEnvFileExists(cenv unsafe.Pointer, cname *C.String_t) C.Status_t {
+	env := InterfacesGet(cenv).(IEnv)
+	return env.FileExists(string(cname.cToBytes(false))).newCStatus()
+}
+
+//export IEnvGetChildren
+func IEnvGetChildren(cenv unsafe.Pointer, cdir *C.String_t, cresult *C.StringVector_t) C.Status_t {
+	env := InterfacesGet(cenv).(IEnv)
+	children, stat := env.GetChildren(string(cdir.cToBytes(false)))
+	for _, child := range children {
+		cchild := C.CString(child)
+		C.StringVectorPushBack(cresult, cchild, C.size_t(len(child)))
+		C.free(unsafe.Pointer(cchild))
+	}
+	return stat.newCStatus()
+}
+
+//export IEnvDeleteFile
+func IEnvDeleteFile(cenv unsafe.Pointer, cname *C.String_t) C.Status_t {
+	env := InterfacesGet(cenv).(IEnv)
+	return env.DeleteFile(string(cname.cToBytes(false))).newCStatus()
+}
+
+//export IEnvCreateDir
+func IEnvCreateDir(cenv unsafe.Pointer, cname *C.String_t) C.Status_t {
+	env := InterfacesGet(cenv).(IEnv)
+	return env.CreateDir(string(cname.cToBytes(false))).newCStatus()
+}
+
+//export IEnvCreateDirIfMissing
+func IEnvCreateDirIfMissing(cenv unsafe.Pointer, cname *C.String_t) C.Status_t {
+	env := InterfacesGet(cenv).(IEnv)
+	return env.CreateDirIfMissing(string(cname.cToBytes(false))).newCStatus()
+}
+
+//export IEnvDeleteDir
+func IEnvDeleteDir(cenv unsafe.Pointer, cname *C.String_t) C.Status_t {
+	env := InterfacesGet(cenv).(IEnv)
+	return env.DeleteDir(string(cname.cToBytes(false))).newCStatus()
+}
+
+//export IEnvGetFileSize
+func IEnvGetFileSize(cenv unsafe.Pointer, cname *C.String_t, csize *C.uint64_t) C.Status_t {
+	env := InterfacesGet(cenv).(IEnv)
+	size, stat := env.GetFileSize(string(cname.cToBytes(false)))
+	*csize = C.uint64_t(size)
+	return stat.newCStatus()
+}
+
+//export IEnvGetFileModificationTime
+func IEnvGetFileModificationTime(cenv unsafe.Pointer, cname *C.String_t, cmtime *C.uint64_t) C.Status_t {
+	env := InterfacesGet(cenv).(IEnv)
+	mtime, stat := env.GetFileModificationTime(string(cname.cToBytes(false)))
+	*cmtime = C.uint64_t(mtime)
+	return stat.newCStatus()
+}
+
+//export IEnvRenameFile
+func IEnvRenameFile(cenv unsafe.Pointer, csrc, ctarget *C.String_t) C.Status_t {
+	env := InterfacesGet(cenv).(IEnv)
+	return env.RenameFile(string(csrc.cToBytes(false)), string(ctarget.cToBytes(false))).newCStatus()
+}
+
+//export IEnvLinkFile
+func IEnvLinkFile(cenv unsafe.Pointer, csrc, ctarget *C.String_t) C.Status_t {
+	env := InterfacesGet(cenv).(IEnv)
+	return env.LinkFile(string(csrc.cToBytes(false)), string(ctarget.cToBytes(false))).newCStatus()
+}
+
+//export IEnvLockFile
+func IEnvLockFile(cenv unsafe.Pointer, cname *C.String_t, clock *unsafe.Pointer) C.Status_t {
+	env := InterfacesGet(cenv).(IEnv)
+	lock, stat := env.LockFile(string(cname.cToBytes(false)))
+	if nil == stat || stat.Ok() {
+		*clock = InterfacesAddReference(lock)
+	}
+	return stat.newCStatus()
+}
+
+//export IEnvUnlockFile
+func IEnvUnlockFile(cenv unsafe.Pointer, clock unsafe.Pointer) C.Status_t {
+	env := InterfacesGet(cenv).(IEnv)
+	return env.UnlockFile(InterfacesGet(clock)).newCStatus()
+}
+
+//export IEnvNowMicros
+func IEnvNowMicros(cenv unsafe.Pointer) C.uint64_t {
+	env := InterfacesGet(cenv).(IEnv)
+	return C.uint64_t(env.NowMicros())
+}
+
+//export IEnvNowNanos
+func IEnvNowNanos(cenv unsafe.Pointer) C.uint64_t {
+	env := InterfacesGet(cenv).(IEnv)
+	return C.uint64_t(env.NowNanos())
+}
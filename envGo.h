@@ -0,0 +1,25 @@
+// Copyright (c) 2015, Dean ChaoJun Pan.  All rights reserved.
+// This source code is licensed under the BSD-style license found in the
+// LICENSE file in the root directory of this source tree.
+
+#ifndef GO_ROCKSDB_INCLUDE_ENV_GO_H_
+#define GO_ROCKSDB_INCLUDE_ENV_GO_H_
+
+#include "types.h"
+#include "env.h"
+
+#ifdef __cplusplus
+extern "C" {
+#endif
+
+// Return an Env calling back the go IEnv for the file system and
+// clock operations. All the other operations, e.g. scheduling the
+// background jobs and creating the info logs, are forwarded to base.
+// The Env::Default() is used if base is NULL.
+Env_t NewEnvGo(Env_t* base, void* go_env);
+
+#ifdef __cplusplus
+}  /* end extern "C" */
+#endif
+
+#endif  // GO_ROCKSDB_INCLUDE_ENV_GO_H_
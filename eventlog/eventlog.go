@@ -0,0 +1,215 @@
+// Copyright (c) 2015, Dean ChaoJun Pan.  All rights reserved.
+// This source code is licensed under the BSD-style license found in the
+// LICENSE file in the root directory of this source tree.
+//
+// Package eventlog parses the structured EVENT_LOG_v1 records rocksdb
+// writes into its info LOG, e.g. flush_started, compaction_finished
+// and table_file_creation, into typed go events. The records can be
+// read from the LOG files with Tailer and ReadFile, or received live
+// through Logger set as the info log of a database.
+
+package eventlog
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// The prefix of the event log records in the info LOG
+const Prefix = "EVENT_LOG_v1"
+
+// The event types written by rocksdb
+const (
+	TypeFlushStarted = "flush_started"
+	TypeFlushFinished = "flush_finished"
+	TypeCompactionStarted = "compaction_started"
+	TypeCompactionFinished = "compaction_finished"
+	TypeTableFileCreation = "table_file_creation"
+	TypeTableFileDeletion = "table_file_deletion"
+	TypeRecoveryStarted = "recovery_started"
+	TypeRecoveryFinished = "recovery_finished"
+)
+
+// Record is one of the typed events, e.g. *FlushStarted, or *Event
+// for the event types not known.
+type Record interface {
+	// The fields common to all the events
+	EventHeader() *Event
+}
+
+// The fields common to all the events
+type Event struct {
+	// The time of the event in microseconds since the epoch
+	TimeMicros int64 `json:"time_micros"`
+	// The id of the flush or compaction job
+	Job int `json:"job"`
+	// The type of the event, e.g. TypeFlushStarted
+	Type string `json:"event"`
+	// The JSON of the whole record
+	Raw json.RawMessage `json:"-"`
+}
+
+func (ev *Event) EventHeader() *Event {
+	return ev
+}
+
+// A memtable flush has started
+type FlushStarted struct {
+	Event
+	NumMemtables int `json:"num_memtables"`
+	NumEntries uint64 `json:"num_entries"`
+	NumDeletes uint64 `json:"num_deletes"`
+	MemoryUsage uint64 `json:"memory_usage"`
+}
+
+// A memtable flush has finished
+type FlushFinished struct {
+	Event
+	// The number of files of each level after the flush
+	LsmState []int `json:"lsm_state"`
+	ImmutableMemtables int `json:"immutable_memtables"`
+}
+
+// A compaction has started
+type CompactionStarted struct {
+	Event
+	// The input file numbers by the level
+	InputFiles map[int][]uint64 `json:"-"`
+	Score float64 `json:"score"`
+	InputDataSize uint64 `json:"input_data_size"`
+}
+
+// A compaction has finished
+type CompactionFinished struct {
+	Event
+	CompactionTimeMicros uint64 `json:"compaction_time_micros"`
+	OutputLevel int `json:"output_level"`
+	NumOutputFiles int `json:"num_output_files"`
+	TotalOutputSize uint64 `json:"total_output_size"`
+	NumInputRecords uint64 `json:"num_input_records"`
+	NumOutputRecords uint64 `json:"num_output_records"`
+	NumSubcompactions int `json:"num_subcompactions"`
+	// The number of files of each level after the compaction
+	LsmState []int `json:"lsm_state"`
+}
+
+// The properties of a table file logged with its creation
+type TableProperties struct {
+	DataSize uint64 `json:"data_size"`
+	IndexSize uint64 `json:"index_size"`
+	FilterSize uint64 `json:"filter_size"`
+	RawKeySize uint64 `json:"raw_key_size"`
+	RawAverageKeySize float64 `json:"raw_average_key_size"`
+	RawValueSize uint64 `json:"raw_value_size"`
+	RawAverageValueSize float64 `json:"raw_average_value_size"`
+	NumDataBlocks uint64 `json:"num_data_blocks"`
+	NumEntries uint64 `json:"num_entries"`
+	FilterPolicyName string `json:"filter_policy_name"`
+}
+
+// A table file has been created
+type TableFileCreation struct {
+	Event
+	ColumnFamilyName string `json:"cf_name"`
+	FileNumber uint64 `json:"file_number"`
+	FileSize uint64 `json:"file_size"`
+	TableProperties TableProperties `json:"table_properties"`
+}
+
+// A table file has been deleted
+type TableFileDeletion struct {
+	Event
+	FileNumber uint64 `json:"file_number"`
+}
+
+// The recovery of the database has started
+type RecoveryStarted struct {
+	Event
+	// The numbers of the WAL files to recover
+	LogFiles []uint64 `json:"log_files"`
+}
+
+// The recovery of the database has finished
+type RecoveryFinished struct {
+	Event
+}
+
+// The key prefix of the input files of each level in compaction_started
+const filesKeyPrefix = "files_L"
+
+// Parse the EVENT_LOG_v1 record in the line of the info LOG. Returns
+// nil without error if the line holds no record.
+func ParseLine(line string) (rec Record, err error) {
+	idx := strings.Index(line, Prefix+" ")
+	if idx < 0 {
+		return
+	}
+	return Parse([]byte(strings.TrimSpace(line[idx+len(Prefix)+1:])))
+}
+
+// Parse the JSON of an EVENT_LOG_v1 record
+func Parse(data []byte) (rec Record, err error) {
+	var ev Event
+	if err = json.Unmarshal(data, &ev); nil != err {
+		return nil, fmt.Errorf("eventlog: %v", err)
+	}
+
+	switch ev.Type {
+	case TypeFlushStarted:
+		rec = &FlushStarted{}
+	case TypeFlushFinished:
+		rec = &FlushFinished{}
+	case TypeCompactionStarted:
+		rec = &CompactionStarted{}
+	case TypeCompactionFinished:
+		rec = &CompactionFinished{}
+	case TypeTableFileCreation:
+		rec = &TableFileCreation{}
+	case TypeTableFileDeletion:
+		rec = &TableFileDeletion{}
+	case TypeRecoveryStarted:
+		rec = &RecoveryStarted{}
+	case TypeRecoveryFinished:
+		rec = &RecoveryFinished{}
+	}
+
+	if nil == rec {
+		rec = &ev
+	} else if err = json.Unmarshal(data, rec); nil != err {
+		return nil, fmt.Errorf("eventlog: %s: %v", ev.Type, err)
+	}
+	if cs, ok := rec.(*CompactionStarted); ok {
+		if cs.InputFiles, err = parseInputFiles(data); nil != err {
+			return nil, err
+		}
+	}
+	rec.EventHeader().Raw = json.RawMessage(append([]byte(nil), data...))
+	return
+}
+
+// Parse the files_L<level> arrays of compaction_started
+func parseInputFiles(data []byte) (files map[int][]uint64, err error) {
+	var fields map[string]json.RawMessage
+	if err = json.Unmarshal(data, &fields); nil != err {
+		return nil, fmt.Errorf("eventlog: %s: %v", TypeCompactionStarted, err)
+	}
+
+	files = make(map[int][]uint64)
+	for key, val := range fields {
+		if !strings.HasPrefix(key, filesKeyPrefix) {
+			continue
+		}
+		level, perr := strconv.Atoi(key[len(filesKeyPrefix):])
+		if nil != perr {
+			continue
+		}
+		var nums []uint64
+		if err = json.Unmarshal(val, &nums); nil != err {
+			return nil, fmt.Errorf("eventlog: %s: %s: %v", TypeCompactionStarted, key, err)
+		}
+		files[level] = nums
+	}
+	return
+}
@@ -0,0 +1,159 @@
+// Copyright (c) 2015, Dean ChaoJun Pan.  All rights reserved.
+// This source code is licensed under the BSD-style license found in the
+// LICENSE file in the root directory of this source tree.
+//
+
+package eventlog
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	rocksdb "github.com/pcjdean/rocksdb-golang"
+)
+
+func TestParseLine(t *testing.T) {
+	rec, err := ParseLine("2016/03/01-12:00:00.000001 7f0 [default] [JOB 3] Flushing memtable")
+	if nil != err || nil != rec {
+		t.Fatalf("eventlog: not an event: rec = %v, err = %v", rec, err)
+	}
+
+	rec, err = ParseLine(`2016/03/01-12:00:00.000002 7f0 EVENT_LOG_v1 {"time_micros": 1456833600000002, "job": 3, "event": "flush_started", "num_memtables": 1, "num_entries": 10, "num_deletes": 2, "memory_usage": 4096}`)
+	if nil != err {
+		t.Fatalf("eventlog: flush_started: err = %v", err)
+	}
+	fs, ok := rec.(*FlushStarted)
+	if !ok || 3 != fs.Job || 1456833600000002 != fs.TimeMicros || 10 != fs.NumEntries || 2 != fs.NumDeletes || 4096 != fs.MemoryUsage {
+		t.Errorf("eventlog: flush_started: rec = %+v", rec)
+	}
+
+	rec, err = ParseLine(`EVENT_LOG_v1 {"time_micros": 1, "job": 4, "event": "compaction_started", "files_L0": [12, 13], "files_L1": [7], "score": 1.5, "input_data_size": 2048}`)
+	if nil != err {
+		t.Fatalf("eventlog: compaction_started: err = %v", err)
+	}
+	cs, ok := rec.(*CompactionStarted)
+	if !ok || 2 != len(cs.InputFiles[0]) || 13 != cs.InputFiles[0][1] || 7 != cs.InputFiles[1][0] || 1.5 != cs.Score {
+		t.Errorf("eventlog: compaction_started: rec = %+v", rec)
+	}
+
+	rec, err = ParseLine(`EVENT_LOG_v1 {"time_micros": 1, "cf_name": "default", "job": 4, "event": "table_file_creation", "file_number": 14, "file_size": 1024, "table_properties": {"data_size": 900, "num_entries": 10, "filter_policy_name": ""}}`)
+	if nil != err {
+		t.Fatalf("eventlog: table_file_creation: err = %v", err)
+	}
+	tfc, ok := rec.(*TableFileCreation)
+	if !ok || "default" != tfc.ColumnFamilyName || 14 != tfc.FileNumber || 900 != tfc.TableProperties.DataSize {
+		t.Errorf("eventlog: table_file_creation: rec = %+v", rec)
+	}
+
+	rec, err = ParseLine(`EVENT_LOG_v1 {"time_micros": 1, "event": "some_new_event"}`)
+	if nil != err {
+		t.Fatalf("eventlog: unknown event: err = %v", err)
+	}
+	if ev, ok := rec.(*Event); !ok || "some_new_event" != ev.Type || 0 == len(ev.Raw) {
+		t.Errorf("eventlog: unknown event: rec = %+v", rec)
+	}
+
+	if _, err = ParseLine(`EVENT_LOG_v1 {"time_micros": `); nil == err {
+		t.Errorf("eventlog: truncated event parsed")
+	}
+}
+
+func TestTailer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "eventlog_test")
+	if nil != err {
+		t.Fatalf("eventlog: TempDir: err = %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, logFileName)
+	line := func(job int) string {
+		return fmt.Sprintf(`2016/03/01-12:00:00.000000 7f0 EVENT_LOG_v1 {"time_micros": 1, "job": %d, "event": "flush_finished", "lsm_state": [1, 0]}`, job)
+	}
+
+	if err = ioutil.WriteFile(path, []byte(line(1)+"\nnot an event\n"+line(2)[:40]), 0644); nil != err {
+		t.Fatalf("eventlog: WriteFile: err = %v", err)
+	}
+	tailer, err := NewTailer(dir)
+	if nil != err {
+		t.Fatalf("eventlog: NewTailer: err = %v", err)
+	}
+	defer tailer.Close()
+	recs, err := tailer.Next()
+	if nil != err || 1 != len(recs) || 1 != recs[0].EventHeader().Job {
+		t.Fatalf("eventlog: Next: recs = %v, err = %v", recs, err)
+	}
+
+	// Complete the partial line
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0644)
+	if nil != err {
+		t.Fatalf("eventlog: OpenFile: err = %v", err)
+	}
+	f.WriteString(line(2)[40:] + "\n")
+	f.Close()
+	recs, err = tailer.Next()
+	if nil != err || 1 != len(recs) || 2 != recs[0].EventHeader().Job {
+		t.Fatalf("eventlog: Next: recs = %v, err = %v", recs, err)
+	}
+
+	// Replace the LOG file
+	if err = os.Rename(path, path+".old.1"); nil != err {
+		t.Fatalf("eventlog: Rename: err = %v", err)
+	}
+	if err = ioutil.WriteFile(path, []byte(line(3)+"\n"), 0644); nil != err {
+		t.Fatalf("eventlog: WriteFile: err = %v", err)
+	}
+	recs, err = tailer.Next()
+	if nil != err || 1 != len(recs) || 3 != recs[0].EventHeader().Job {
+		t.Fatalf("eventlog: Next after rotation: recs = %v, err = %v", recs, err)
+	}
+
+	recs, err = ReadFile(path + ".old.1")
+	if nil != err || 2 != len(recs) {
+		t.Fatalf("eventlog: ReadFile: recs = %v, err = %v", recs, err)
+	}
+}
+
+func TestLogger(t *testing.T) {
+	dbname := fmt.Sprintf("%s/rocksdb_go_eventlog_test-%d", os.TempDir(), os.Geteuid())
+	var (
+		mtx sync.Mutex
+		types = make(map[string]int)
+	)
+	handler := func(rec Record) {
+		mtx.Lock()
+		types[rec.EventHeader().Type]++
+		mtx.Unlock()
+	}
+
+	options := rocksdb.NewOptions()
+	options.SetCreateIfMissing(true)
+	options.SetInfoLog(rocksdb.NewPLogger(NewLogger(handler, nil)))
+	db, stat, _ := rocksdb.Open(options, &dbname)
+	if !stat.Ok() {
+		t.Fatalf("eventlog: open: stat = %s", stat)
+	}
+	defer rocksdb.DestroyDB(options, &dbname)
+
+	woptions := rocksdb.NewWriteOptions()
+	stat = db.Put(woptions, []byte("foo"), []byte("bar"))
+	if !stat.Ok() {
+		t.Fatalf("eventlog: Put: stat = %s", stat)
+	}
+	stat = db.Flush(rocksdb.NewFlushOptions())
+	if !stat.Ok() {
+		t.Fatalf("eventlog: Flush: stat = %s", stat)
+	}
+	db.Close()
+
+	mtx.Lock()
+	defer mtx.Unlock()
+	t.Logf("eventlog: events = %v", types)
+	for _, typ := range []string{TypeFlushStarted, TypeFlushFinished, TypeTableFileCreation} {
+		if 0 == types[typ] {
+			t.Errorf("eventlog: no %s event", typ)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+// Copyright (c) 2015, Dean ChaoJun Pan.  All rights reserved.
+// This source code is licensed under the BSD-style license found in the
+// LICENSE file in the root directory of this source tree.
+//
+
+package eventlog
+
+import (
+	rocksdb "github.com/pcjdean/rocksdb-golang"
+)
+
+// Logger is an ILogger receiving the records live. Set it as the info
+// log of a database with
+//	options.SetInfoLog(rocksdb.NewPLogger(eventlog.NewLogger(handler, next)))
+// handler is called with each record, and every log line is passed
+// on to next if it is not nil, so the records can be received
+// without losing the info log.
+type Logger struct {
+	handler func(Record)
+	next rocksdb.ILogger
+}
+
+// Return a Logger calling handler with the records and passing all the
+// log lines to next. handler is called from the threads of rocksdb
+// concurrently, and should return quickly as the database may wait
+// for it.
+func NewLogger(handler func(Record), next rocksdb.ILogger) *Logger {
+	return &Logger{handler: handler, next: next}
+}
+
+// Write a log line at the level.
+func (l *Logger) Logv(level int, msg string) {
+	if nil != l.handler {
+		if rec, err := ParseLine(msg); nil == err && nil != rec {
+			l.handler(rec)
+		}
+	}
+	if nil != l.next {
+		l.next.Logv(level, msg)
+	}
+}
@@ -0,0 +1,183 @@
+// Copyright (c) 2015, Dean ChaoJun Pan.  All rights reserved.
+// This source code is licensed under the BSD-style license found in the
+// LICENSE file in the root directory of this source tree.
+//
+
+package eventlog
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// The name of the info LOG file in the DB directory
+const logFileName = "LOG"
+
+// Default interval between two polls of Tail
+const defaultTailInterval = time.Second
+
+// Tailer reads the records appended to the info LOG file of a DB
+// directory. The LOG file is reopened when it is replaced, e.g.
+// after the database is reopened, so the records of the new file
+// are read from its beginning.
+type Tailer struct {
+	// The path of the LOG file
+	path string
+	f *os.File
+	r *bufio.Reader
+	// The incomplete last line read
+	partial string
+}
+
+// Return a Tailer of the LOG file in the DB directory dbdir, reading
+// from the beginning of the file.
+func NewTailer(dbdir string) (t *Tailer, err error) {
+	t = &Tailer{path: filepath.Join(dbdir, logFileName)}
+	if err = t.open(); nil != err {
+		return nil, err
+	}
+	return
+}
+
+// Open the LOG file from its beginning
+func (t *Tailer) open() (err error) {
+	if t.f, err = os.Open(t.path); nil != err {
+		return
+	}
+	t.r = bufio.NewReader(t.f)
+	t.partial = ""
+	return
+}
+
+// Close the LOG file
+func (t *Tailer) Close() error {
+	return t.f.Close()
+}
+
+// Return the records appended since the last call. Lines failing to
+// parse are skipped.
+func (t *Tailer) Next() (recs []Record, err error) {
+	if recs, err = t.readAll(); nil != err {
+		return
+	}
+
+	// Switch to the new LOG file if it is replaced
+	oldfi, err := t.f.Stat()
+	if nil != err {
+		return
+	}
+	newfi, err := os.Stat(t.path)
+	if nil != err {
+		if os.IsNotExist(err) {
+			err = nil
+		}
+		return
+	}
+	if !os.SameFile(oldfi, newfi) {
+		t.f.Close()
+		if err = t.open(); nil != err {
+			return
+		}
+		var more []Record
+		more, err = t.readAll()
+		recs = append(recs, more...)
+	}
+	return
+}
+
+// Read the records until the end of the file. An incomplete last
+// line is kept until the rest of it is written.
+func (t *Tailer) readAll() (recs []Record, err error) {
+	for {
+		var line string
+		line, err = t.r.ReadString('\n')
+		t.partial += line
+		if err == io.EOF {
+			return recs, nil
+		} else if nil != err {
+			return
+		}
+
+		if rec, perr := ParseLine(strings.TrimRight(t.partial, "\n")); nil == perr && nil != rec {
+			recs = append(recs, rec)
+		}
+		t.partial = ""
+	}
+}
+
+// Tail delivers the records appended to the LOG file in the DB
+// directory dbdir, polling the file every interval, one second if
+// not given. The existing records are delivered first. Both channels
+// are closed when ctx is cancelled or the file can no longer be read,
+// in which case the error is sent on errs first.
+func Tail(ctx context.Context, dbdir string, interval ...time.Duration) (records <-chan Record, errs <-chan error) {
+	var (
+		recch = make(chan Record)
+		errch = make(chan error, 1)
+		ival = defaultTailInterval
+	)
+
+	if len(interval) > 0 && interval[0] > 0 {
+		ival = interval[0]
+	}
+
+	go func() {
+		defer close(errch)
+		defer close(recch)
+
+		t, err := NewTailer(dbdir)
+		if nil != err {
+			errch <- err
+			return
+		}
+		defer t.Close()
+
+		for {
+			recs, err := t.Next()
+			for _, rec := range recs {
+				select {
+				case <-ctx.Done():
+					return
+				case recch <- rec:
+				}
+			}
+			if nil != err {
+				errch <- err
+				return
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(ival):
+			}
+		}
+	}()
+	return recch, errch
+}
+
+// Return all the records in the info LOG file at path, e.g. LOG or
+// one of the LOG.old.* files for post-mortems.
+func ReadFile(path string) (recs []Record, err error) {
+	f, err := os.Open(path)
+	if nil != err {
+		return
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	// The records of large compactions can be long
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if rec, perr := ParseLine(scanner.Text()); nil == perr && nil != rec {
+			recs = append(recs, rec)
+		}
+	}
+	err = scanner.Err()
+	return
+}
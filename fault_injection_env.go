@@ -0,0 +1,600 @@
+// Copyright (c) 2015, Dean ChaoJun Pan.  All rights reserved.
+// This source code is licensed under the BSD-style license found in the
+// LICENSE file in the root directory of this source tree.
+//
+// FaultInjectionEnv is an Env on the local file system which can
+// simulate a crash by dropping the data not synced, fail the writes
+// after some bytes, and fail the I/O on chosen file types. It is used
+// to test the durability of the databases.
+
+package rocksdb
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The type of a file in the database directory
+type FileType int
+
+const (
+	// Write ahead log file, *.log
+	WalFile FileType = iota
+	// Table file, *.sst
+	TableFile
+	// Descriptor file, MANIFEST-*
+	DescriptorFile
+	// The CURRENT file
+	CurrentFile
+	// Info log file, LOG and LOG.old.*
+	InfoLogFile
+	// Temporary file, *.dbtmp
+	TempFile
+	// Any other file, e.g. IDENTITY, LOCK and OPTIONS-*
+	OtherFile
+)
+
+func (ft FileType) String() string {
+	switch ft {
+	case WalFile:
+		return "WalFile"
+	case TableFile:
+		return "TableFile"
+	case DescriptorFile:
+		return "DescriptorFile"
+	case CurrentFile:
+		return "CurrentFile"
+	case InfoLogFile:
+		return "InfoLogFile"
+	case TempFile:
+		return "TempFile"
+	}
+	return "OtherFile"
+}
+
+// Return the type of the file by its name
+func GetFileType(name string) FileType {
+	base := filepath.Base(name)
+	switch {
+	case strings.HasSuffix(base, ".log"):
+		return WalFile
+	case strings.HasSuffix(base, ".sst"):
+		return TableFile
+	case strings.HasPrefix(base, "MANIFEST-"):
+		return DescriptorFile
+	case base == "CURRENT":
+		return CurrentFile
+	case base == "LOG" || strings.HasPrefix(base, "LOG.old."):
+		return InfoLogFile
+	case strings.HasSuffix(base, ".dbtmp"):
+		return TempFile
+	}
+	return OtherFile
+}
+
+// The state of a file written since the last reset
+type faultFileState struct {
+	// The size written
+	pos int64
+	// The size synced
+	syncedPos int64
+}
+
+// An Env on the local file system which can simulate a crash and
+// inject I/O errors. Set it to the options with SetEnv(fienv.Env()).
+type FaultInjectionEnv struct {
+	env *Env
+	// Mutex to protect the fields below
+	mtx sync.Mutex
+	// False to fail the writes, as if the process has crashed
+	active bool
+	// The bytes can be appended before the writes fail, negative for no limit
+	writeLimit int64
+	// The file types to fail the I/O on
+	failTypes map[FileType]bool
+	// The state of the files written by the name
+	files map[string]*faultFileState
+	// The files created since the last fsync of their directory by the directory
+	newFiles map[string]map[string]bool
+	// The files locked
+	locks map[string]bool
+}
+
+// Return a new FaultInjectionEnv
+func NewFaultInjectionEnv() (fienv *FaultInjectionEnv) {
+	fienv = &FaultInjectionEnv{
+		active: true,
+		writeLimit: -1,
+		failTypes: make(map[FileType]bool),
+		files: make(map[string]*faultFileState),
+		newFiles: make(map[string]map[string]bool),
+		locks: make(map[string]bool),
+	}
+	fienv.env = NewEnv(&faultInjectionEnv{fienv: fienv})
+	return
+}
+
+// The Env to set to the options.
+func (fienv *FaultInjectionEnv) Env() *Env {
+	return fienv.env
+}
+
+// Set false to fail all the writes, as if the process has crashed.
+func (fienv *FaultInjectionEnv) SetFilesystemActive(active bool) {
+	defer fienv.mtx.Unlock()
+	fienv.mtx.Lock()
+	fienv.active = active
+}
+
+// Return false if the writes are failed.
+func (fienv *FaultInjectionEnv) IsFilesystemActive() bool {
+	defer fienv.mtx.Unlock()
+	fienv.mtx.Lock()
+	return fienv.active
+}
+
+// Fail the writes after n more bytes have been appended. A negative n
+// removes the limit.
+func (fienv *FaultInjectionEnv) FailWritesAfter(n int64) {
+	defer fienv.mtx.Unlock()
+	fienv.mtx.Lock()
+	fienv.writeLimit = n
+}
+
+// Return IOError for the creation, reads, writes and syncs of the
+// files of the types. Call without types to stop failing.
+func (fienv *FaultInjectionEnv) FailFileTypes(types ...FileType) {
+	defer fienv.mtx.Unlock()
+	fienv.mtx.Lock()
+	fienv.failTypes = make(map[FileType]bool, len(types))
+	for _, ft := range types {
+		fienv.failTypes[ft] = true
+	}
+}
+
+// Truncate the files written since the last reset to the size
+// synced, and delete the files created since the last fsync of
+// their directory.
+func (fienv *FaultInjectionEnv) DropUnsyncedData() (stat *Status) {
+	defer fienv.mtx.Unlock()
+	fienv.mtx.Lock()
+	for name, state := range fienv.files {
+		if err := os.Truncate(name, state.syncedPos); nil != err && !os.IsNotExist(err) {
+			return NewIOErrorStatus(err.Error())
+		}
+		state.pos = state.syncedPos
+	}
+	for _, names := range fienv.newFiles {
+		for name := range names {
+			if err := os.Remove(name); nil != err && !os.IsNotExist(err) {
+				return NewIOErrorStatus(err.Error())
+			}
+			delete(fienv.files, name)
+		}
+	}
+	fienv.newFiles = make(map[string]map[string]bool)
+	stat = NewOKStatus()
+	return
+}
+
+// Forget the files written and remove all the faults.
+func (fienv *FaultInjectionEnv) ResetState() {
+	defer fienv.mtx.Unlock()
+	fienv.mtx.Lock()
+	fienv.active = true
+	fienv.writeLimit = -1
+	fienv.failTypes = make(map[FileType]bool)
+	fienv.files = make(map[string]*faultFileState)
+	fienv.newFiles = make(map[string]map[string]bool)
+}
+
+// Simulate a crash of db opened with the env: the writes fail from now
+// on and db is closed. Then the data not synced are dropped and the
+// env is reset.
+func (fienv *FaultInjectionEnv) SimulateCrash(db *DB) (stat *Status) {
+	fienv.SetFilesystemActive(false)
+	db.Close()
+	if stat = fienv.DropUnsyncedData(); stat.Ok() {
+		fienv.ResetState()
+	}
+	return
+}
+
+// Return the IOError if the I/O on the file should fail
+func (fienv *FaultInjectionEnv) checkFailure(name string, write bool) (stat *Status) {
+	defer fienv.mtx.Unlock()
+	fienv.mtx.Lock()
+	if write && !fienv.active {
+		stat = NewIOErrorStatus(fmt.Sprintf("%s: filesystem is not active", name))
+	} else if fienv.failTypes[GetFileType(name)] {
+		stat = NewIOErrorStatus(fmt.Sprintf("%s: injected error on %s", name, GetFileType(name)))
+	}
+	return
+}
+
+// Record a file created
+func (fienv *FaultInjectionEnv) fileCreated(name string) {
+	defer fienv.mtx.Unlock()
+	fienv.mtx.Lock()
+	fienv.files[name] = &faultFileState{}
+	dir := filepath.Dir(name)
+	if nil == fienv.newFiles[dir] {
+		fienv.newFiles[dir] = make(map[string]bool)
+	}
+	fienv.newFiles[dir][name] = true
+}
+
+// Record a file removed
+func (fienv *FaultInjectionEnv) fileRemoved(name string) {
+	defer fienv.mtx.Unlock()
+	fienv.mtx.Lock()
+	delete(fienv.files, name)
+	delete(fienv.newFiles[filepath.Dir(name)], name)
+}
+
+// Record a file renamed
+func (fienv *FaultInjectionEnv) fileRenamed(src, target string) {
+	defer fienv.mtx.Unlock()
+	fienv.mtx.Lock()
+	if state, ok := fienv.files[src]; ok {
+		fienv.files[target] = state
+		delete(fienv.files, src)
+	}
+	if srcdir := filepath.Dir(src); fienv.newFiles[srcdir][src] {
+		delete(fienv.newFiles[srcdir], src)
+		dir := filepath.Dir(target)
+		if nil == fienv.newFiles[dir] {
+			fienv.newFiles[dir] = make(map[string]bool)
+		}
+		fienv.newFiles[dir][target] = true
+	}
+}
+
+// Take up to n bytes from the write limit. Returns the bytes can
+// be written.
+func (fienv *FaultInjectionEnv) takeWriteLimit(n int64) int64 {
+	defer fienv.mtx.Unlock()
+	fienv.mtx.Lock()
+	if fienv.writeLimit < 0 {
+		return n
+	}
+	if n > fienv.writeLimit {
+		n = fienv.writeLimit
+	}
+	fienv.writeLimit -= n
+	return n
+}
+
+// Record data appended to a file
+func (fienv *FaultInjectionEnv) fileAppended(name string, n int64) {
+	defer fienv.mtx.Unlock()
+	fienv.mtx.Lock()
+	if state, ok := fienv.files[name]; ok {
+		state.pos += n
+	}
+}
+
+// Record a file synced
+func (fienv *FaultInjectionEnv) fileSynced(name string) {
+	defer fienv.mtx.Unlock()
+	fienv.mtx.Lock()
+	if state, ok := fienv.files[name]; ok {
+		state.syncedPos = state.pos
+	}
+}
+
+// Record a directory synced
+func (fienv *FaultInjectionEnv) dirSynced(dir string) {
+	defer fienv.mtx.Unlock()
+	fienv.mtx.Lock()
+	delete(fienv.newFiles, filepath.Clean(dir))
+}
+
+// Convert the go error to a status
+func faultStatus(err error) *Status {
+	if nil == err {
+		return nil
+	} else if os.IsNotExist(err) {
+		return NewNotFoundStatus(err.Error())
+	}
+	return NewIOErrorStatus(err.Error())
+}
+
+// IEnv of FaultInjectionEnv
+type faultInjectionEnv struct {
+	fienv *FaultInjectionEnv
+}
+
+// A file opened by faultInjectionEnv
+type faultInjectionFile struct {
+	fienv *FaultInjectionEnv
+	name string
+	f *os.File
+}
+
+func (fif *faultInjectionFile) Read(buf []byte) (int, *Status) {
+	if stat := fif.fienv.checkFailure(fif.name, false); nil != stat {
+		return 0, stat
+	}
+	n, err := io.ReadFull(fif.f, buf)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		err = nil
+	}
+	return n, faultStatus(err)
+}
+
+func (fif *faultInjectionFile) Skip(n uint64) *Status {
+	_, err := fif.f.Seek(int64(n), io.SeekCurrent)
+	return faultStatus(err)
+}
+
+func (fif *faultInjectionFile) ReadAt(buf []byte, offset uint64) (int, *Status) {
+	if stat := fif.fienv.checkFailure(fif.name, false); nil != stat {
+		return 0, stat
+	}
+	n, err := fif.f.ReadAt(buf, int64(offset))
+	if err == io.EOF {
+		err = nil
+	}
+	return n, faultStatus(err)
+}
+
+func (fif *faultInjectionFile) Append(data []byte) *Status {
+	if stat := fif.fienv.checkFailure(fif.name, true); nil != stat {
+		return stat
+	}
+	limit := fif.fienv.takeWriteLimit(int64(len(data)))
+	n, err := fif.f.Write(data[:limit])
+	fif.fienv.fileAppended(fif.name, int64(n))
+	if nil == err && limit < int64(len(data)) {
+		return NewIOErrorStatus(fmt.Sprintf("%s: injected error after write limit", fif.name))
+	}
+	return faultStatus(err)
+}
+
+func (fif *faultInjectionFile) Close() *Status {
+	return faultStatus(fif.f.Close())
+}
+
+func (fif *faultInjectionFile) Flush() *Status {
+	return fif.fienv.checkFailure(fif.name, true)
+}
+
+func (fif *faultInjectionFile) Sync() *Status {
+	if stat := fif.fienv.checkFailure(fif.name, true); nil != stat {
+		return stat
+	}
+	if err := fif.f.Sync(); nil != err {
+		return faultStatus(err)
+	}
+	fif.fienv.fileSynced(fif.name)
+	return nil
+}
+
+func (fif *faultInjectionFile) Fsync() *Status {
+	if stat := fif.fienv.checkFailure(fif.name, true); nil != stat {
+		return stat
+	}
+	if err := fif.f.Sync(); nil != err {
+		return faultStatus(err)
+	}
+	fif.fienv.dirSynced(fif.name)
+	return nil
+}
+
+func (fie *faultInjectionEnv) openFile(name string, flag int) (*faultInjectionFile, *Status) {
+	f, err := os.OpenFile(name, flag, 0644)
+	if nil != err {
+		return nil, faultStatus(err)
+	}
+	return &faultInjectionFile{fienv: fie.fienv, name: name, f: f}, nil
+}
+
+func (fie *faultInjectionEnv) NewSequentialFile(name string) (ISequentialFile, *Status) {
+	if stat := fie.fienv.checkFailure(name, false); nil != stat {
+		return nil, stat
+	}
+	return fie.openFile(name, os.O_RDONLY)
+}
+
+func (fie *faultInjectionEnv) NewRandomAccessFile(name string) (IRandomAccessFile, *Status) {
+	if stat := fie.fienv.checkFailure(name, false); nil != stat {
+		return nil, stat
+	}
+	return fie.openFile(name, os.O_RDONLY)
+}
+
+func (fie *faultInjectionEnv) NewWritableFile(name string) (IWritableFile, *Status) {
+	if stat := fie.fienv.checkFailure(name, true); nil != stat {
+		return nil, stat
+	}
+	file, stat := fie.openFile(name, os.O_WRONLY|os.O_CREATE|os.O_TRUNC)
+	if nil == stat {
+		fie.fienv.fileCreated(name)
+	}
+	return file, stat
+}
+
+func (fie *faultInjectionEnv) NewDirectory(name string) (IDirectory, *Status) {
+	return fie.openFile(name, os.O_RDONLY)
+}
+
+func (fie *faultInjectionEnv) FileExists(name string) *Status {
+	_, err := os.Stat(name)
+	return faultStatus(err)
+}
+
+func (fie *faultInjectionEnv) GetChildren(dir string) ([]string, *Status) {
+	f, err := os.Open(dir)
+	if nil != err {
+		return nil, faultStatus(err)
+	}
+	defer f.Close()
+	names, err := f.Readdirnames(-1)
+	return names, faultStatus(err)
+}
+
+func (fie *faultInjectionEnv) DeleteFile(name string) *Status {
+	if stat := fie.fienv.checkFailure(name, true); nil != stat {
+		return stat
+	}
+	if err := os.Remove(name); nil != err {
+		return faultStatus(err)
+	}
+	fie.fienv.fileRemoved(name)
+	return nil
+}
+
+func (fie *faultInjectionEnv) CreateDir(name string) *Status {
+	return faultStatus(os.Mkdir(name, 0755))
+}
+
+func (fie *faultInjectionEnv) CreateDirIfMissing(name string) *Status {
+	if err := os.Mkdir(name, 0755); nil != err && !os.IsExist(err) {
+		return faultStatus(err)
+	}
+	return nil
+}
+
+func (fie *faultInjectionEnv) DeleteDir(name string) *Status {
+	return faultStatus(os.Remove(name))
+}
+
+func (fie *faultInjectionEnv) GetFileSize(name string) (uint64, *Status) {
+	fi, err := os.Stat(name)
+	if nil != err {
+		return 0, faultStatus(err)
+	}
+	return uint64(fi.Size()), nil
+}
+
+func (fie *faultInjectionEnv) GetFileModificationTime(name string) (uint64, *Status) {
+	fi, err := os.Stat(name)
+	if nil != err {
+		return 0, faultStatus(err)
+	}
+	return uint64(fi.ModTime().Unix()), nil
+}
+
+func (fie *faultInjectionEnv) RenameFile(src, target string) *Status {
+	if stat := fie.fienv.checkFailure(target, true); nil != stat {
+		return stat
+	}
+	if err := os.Rename(src, target); nil != err {
+		return faultStatus(err)
+	}
+	fie.fienv.fileRenamed(src, target)
+	return nil
+}
+
+func (fie *faultInjectionEnv) LinkFile(src, target string) *Status {
+	if stat := fie.fienv.checkFailure(target, true); nil != stat {
+		return stat
+	}
+	return faultStatus(os.Link(src, target))
+}
+
+func (fie *faultInjectionEnv) LockFile(name string) (interface{}, *Status) {
+	defer fie.fienv.mtx.Unlock()
+	fie.fienv.mtx.Lock()
+	if fie.fienv.locks[name] {
+		return nil, NewIOErrorStatus(fmt.Sprintf("lock %s: already held by process", name))
+	}
+	fie.fienv.locks[name] = true
+	return name, nil
+}
+
+func (fie *faultInjectionEnv) UnlockFile(lock interface{}) *Status {
+	defer fie.fienv.mtx.Unlock()
+	fie.fienv.mtx.Lock()
+	delete(fie.fienv.locks, lock.(string))
+	return nil
+}
+
+func (fie *faultInjectionEnv) NowMicros() uint64 {
+	return uint64(time.Now().UnixNano() / 1000)
+}
+
+func (fie *faultInjectionEnv) NowNanos() uint64 {
+	return uint64(time.Now().UnixNano())
+}
+
+// The result of CrashTest for one WriteOptions.SetSync setting
+type CrashTestResult struct {
+	// The WriteOptions.SetSync setting of the writes
+	Sync bool
+	// The number of keys written before the crash
+	Written int
+	// The number of keys found after the DB is reopened
+	Survived int
+}
+
+// CrashTest writes n keys into a new DB named name with each
+// WriteOptions.SetSync setting, simulates a crash with fienv, reopens
+// the DB and counts the keys survived. The DB is opened with options
+// whose env is set to fienv temporarily, and destroyed afterwards.
+// create_if_missing is set on options.
+func CrashTest(options *Options, name string, fienv *FaultInjectionEnv, n int) (results []CrashTestResult, stat *Status) {
+	oldenv := options.Env()
+	defer options.SetEnv(oldenv)
+	options.SetEnv(fienv.Env())
+	options.SetCreateIfMissing(true)
+
+	for _, sync := range []bool{false, true} {
+		var res CrashTestResult
+		if res, stat = crashTestOnce(options, name, fienv, sync, n); !stat.Ok() {
+			return
+		}
+		results = append(results, res)
+	}
+	return
+}
+
+// The key of the ith write of CrashTest
+func crashTestKey(i int) []byte {
+	return []byte(fmt.Sprintf("crashtest%08d", i))
+}
+
+// Run CrashTest with one WriteOptions.SetSync setting
+func crashTestOnce(options *Options, name string, fienv *FaultInjectionEnv, sync bool, n int) (res CrashTestResult, stat *Status) {
+	res.Sync = sync
+	fienv.ResetState()
+	DestroyDB(options, &name)
+	defer DestroyDB(options, &name)
+
+	db, stat, _ := Open(options, &name)
+	if !stat.Ok() {
+		return
+	}
+	wopts := NewWriteOptions()
+	defer wopts.Close()
+	wopts.SetSync(sync)
+	for ; res.Written < n; res.Written++ {
+		if stat = db.Put(wopts, crashTestKey(res.Written), crashTestKey(res.Written)); !stat.Ok() {
+			db.Close()
+			return
+		}
+	}
+	if stat = fienv.SimulateCrash(db); !stat.Ok() {
+		return
+	}
+
+	db, stat, _ = Open(options, &name)
+	if !stat.Ok() {
+		return
+	}
+	defer db.Close()
+	ropts := NewReadOptions()
+	defer ropts.Close()
+	for i := 0; i < n; i++ {
+		if _, gstat := db.Get(ropts, crashTestKey(i)); gstat.Ok() {
+			res.Survived++
+		}
+	}
+	return
+}
@@ -31,6 +31,7 @@ type Iterator struct {
 	// Thread safe
 	mutex sync.Mutex
 	db *DB // make sure the iterator is deleted before the db
+	wbwi *WriteBatchWithIndex // make sure the iterator is deleted before the batch
 	// true if the underlying c object is deleted
 	closed bool
 }
@@ -40,6 +41,9 @@ func (it *Iterator) finalize() {
 	if !it.closed {
 		it.closed = true
 		it.db.removeFromItmap(it)
+		if nil != it.wbwi {
+			it.wbwi.removeFromItmap(it)
+		}
 		var cit *C.Iterator_t = &it.it
 		C.DeleteIteratorT(cit, toCBool(false))
 	}
@@ -0,0 +1,305 @@
+// Copyright (c) 2015, Dean ChaoJun Pan.  All rights reserved.
+// This source code is licensed under the BSD-style license found in the
+// LICENSE file in the root directory of this source tree.
+//
+// EventListener class contains a set of call-back functions that will
+// be called when specific RocksDB event happens such as flush.
+
+#include <rocksdb/db.h>
+#include <rocksdb/listener.h>
+
+using namespace rocksdb;
+
+#include "listener.h"
+
+extern "C" {
+#include "_cgo_export.h"
+}
+
+// The size of the file
+DEFINE_C_WRAP_GETTER(TableFileCreationInfo, file_size, uint64_t)
+// The id of the job (which could be flush or compaction) that
+// created the file.
+DEFINE_C_WRAP_GETTER(TableFileCreationInfo, job_id, int)
+// The name of the db
+String_t TableFileCreationInfo_get_db_name(TableFileCreationInfo_t* ptr)
+{
+    String ret;
+    if (ptr && GET_REP(ptr, TableFileCreationInfo))
+    {
+        ret = GET_REP(ptr, TableFileCreationInfo)->db_name;
+    }
+    return NewStringTCopy(&ret);
+}
+
+// The name of the column family
+String_t TableFileCreationInfo_get_cf_name(TableFileCreationInfo_t* ptr)
+{
+    String ret;
+    if (ptr && GET_REP(ptr, TableFileCreationInfo))
+    {
+        ret = GET_REP(ptr, TableFileCreationInfo)->cf_name;
+    }
+    return NewStringTCopy(&ret);
+}
+
+// The path to the created file
+String_t TableFileCreationInfo_get_file_path(TableFileCreationInfo_t* ptr)
+{
+    String ret;
+    if (ptr && GET_REP(ptr, TableFileCreationInfo))
+    {
+        ret = GET_REP(ptr, TableFileCreationInfo)->file_path;
+    }
+    return NewStringTCopy(&ret);
+}
+
+// Detailed properties of the created file. The returned properties
+// remain the property of the TableFileCreationInfo.
+TableProperties_t TableFileCreationInfo_get_table_properties(TableFileCreationInfo_t* ptr)
+{
+    TableProperties_t wrap_t;
+    wrap_t.rep = (ptr && GET_REP(ptr, TableFileCreationInfo)) ?
+        &GET_REP(ptr, TableFileCreationInfo)->table_properties :
+        nullptr;
+    return wrap_t;
+}
+
+// The id of the job which deleted the file
+DEFINE_C_WRAP_GETTER(TableFileDeletionInfo, job_id, int)
+// The name of the db
+String_t TableFileDeletionInfo_get_db_name(TableFileDeletionInfo_t* ptr)
+{
+    String ret;
+    if (ptr && GET_REP(ptr, TableFileDeletionInfo))
+    {
+        ret = GET_REP(ptr, TableFileDeletionInfo)->db_name;
+    }
+    return NewStringTCopy(&ret);
+}
+
+// The path to the deleted file
+String_t TableFileDeletionInfo_get_file_path(TableFileDeletionInfo_t* ptr)
+{
+    String ret;
+    if (ptr && GET_REP(ptr, TableFileDeletionInfo))
+    {
+        ret = GET_REP(ptr, TableFileDeletionInfo)->file_path;
+    }
+    return NewStringTCopy(&ret);
+}
+
+// The status indicating whether the deletion was successful or not
+Status_t TableFileDeletionInfo_get_status(TableFileDeletionInfo_t* ptr)
+{
+    Status ret = (ptr && GET_REP(ptr, TableFileDeletionInfo)) ?
+        GET_REP(ptr, TableFileDeletionInfo)->status :
+        invalid_status;
+    return NewStatusTCopy(&ret);
+}
+
+// the id of the thread that completed this flush job
+DEFINE_C_WRAP_GETTER(FlushJobInfo, thread_id, uint64_t)
+// the job id, which is unique in the same thread
+DEFINE_C_WRAP_GETTER(FlushJobInfo, job_id, int)
+// If true, then rocksdb is currently slowing-down all writes to prevent
+// creating too many Level 0 files as compaction seems not able to
+// catch up the write request speed.
+DEFINE_C_WRAP_GETTER(FlushJobInfo, triggered_writes_slowdown, bool)
+// If true, then rocksdb is currently blocking any writes to prevent
+// creating more L0 files.
+DEFINE_C_WRAP_GETTER(FlushJobInfo, triggered_writes_stop, bool)
+// The smallest sequence number in the newly created file
+DEFINE_C_WRAP_GETTER(FlushJobInfo, smallest_seqno, SequenceNumber)
+// The largest sequence number in the newly created file
+DEFINE_C_WRAP_GETTER(FlushJobInfo, largest_seqno, SequenceNumber)
+// the name of the column family
+String_t FlushJobInfo_get_cf_name(FlushJobInfo_t* ptr)
+{
+    String ret;
+    if (ptr && GET_REP(ptr, FlushJobInfo))
+    {
+        ret = GET_REP(ptr, FlushJobInfo)->cf_name;
+    }
+    return NewStringTCopy(&ret);
+}
+
+// the path to the newly created file
+String_t FlushJobInfo_get_file_path(FlushJobInfo_t* ptr)
+{
+    String ret;
+    if (ptr && GET_REP(ptr, FlushJobInfo))
+    {
+        ret = GET_REP(ptr, FlushJobInfo)->file_path;
+    }
+    return NewStringTCopy(&ret);
+}
+
+DEFINE_C_WRAP_GETTER(CompactionJobStats, elapsed_micros, uint64_t)
+DEFINE_C_WRAP_GETTER(CompactionJobStats, num_input_records, uint64_t)
+DEFINE_C_WRAP_GETTER(CompactionJobStats, num_input_files, size_t)
+DEFINE_C_WRAP_GETTER(CompactionJobStats, num_input_files_at_output_level, size_t)
+DEFINE_C_WRAP_GETTER(CompactionJobStats, num_output_records, uint64_t)
+DEFINE_C_WRAP_GETTER(CompactionJobStats, num_output_files, size_t)
+DEFINE_C_WRAP_GETTER(CompactionJobStats, is_manual_compaction, bool)
+DEFINE_C_WRAP_GETTER(CompactionJobStats, total_input_bytes, uint64_t)
+DEFINE_C_WRAP_GETTER(CompactionJobStats, total_output_bytes, uint64_t)
+DEFINE_C_WRAP_GETTER(CompactionJobStats, num_records_replaced, uint64_t)
+DEFINE_C_WRAP_GETTER(CompactionJobStats, total_input_raw_key_bytes, uint64_t)
+DEFINE_C_WRAP_GETTER(CompactionJobStats, total_input_raw_value_bytes, uint64_t)
+DEFINE_C_WRAP_GETTER(CompactionJobStats, num_input_deletion_records, uint64_t)
+DEFINE_C_WRAP_GETTER(CompactionJobStats, num_expired_deletion_records, uint64_t)
+DEFINE_C_WRAP_GETTER(CompactionJobStats, num_corrupt_keys, uint64_t)
+
+// the id of the thread that completed this compaction job
+DEFINE_C_WRAP_GETTER(CompactionJobInfo, thread_id, uint64_t)
+// the job id, which is unique in the same thread
+DEFINE_C_WRAP_GETTER(CompactionJobInfo, job_id, int)
+// the smallest input level of the compaction
+DEFINE_C_WRAP_GETTER(CompactionJobInfo, base_input_level, int)
+// the output level of the compaction
+DEFINE_C_WRAP_GETTER(CompactionJobInfo, output_level, int)
+// the name of the column family where the compaction happened
+String_t CompactionJobInfo_get_cf_name(CompactionJobInfo_t* ptr)
+{
+    String ret;
+    if (ptr && GET_REP(ptr, CompactionJobInfo))
+    {
+        ret = GET_REP(ptr, CompactionJobInfo)->cf_name;
+    }
+    return NewStringTCopy(&ret);
+}
+
+// the status indicating whether the compaction was successful or not
+Status_t CompactionJobInfo_get_status(CompactionJobInfo_t* ptr)
+{
+    Status ret = (ptr && GET_REP(ptr, CompactionJobInfo)) ?
+        GET_REP(ptr, CompactionJobInfo)->status :
+        invalid_status;
+    return NewStatusTCopy(&ret);
+}
+
+// Information about the compaction. The returned stats remain the
+// property of the CompactionJobInfo.
+CompactionJobStats_t CompactionJobInfo_get_stats(CompactionJobInfo_t* ptr)
+{
+    CompactionJobStats_t wrap_t;
+    wrap_t.rep = (ptr && GET_REP(ptr, CompactionJobInfo)) ?
+        &GET_REP(ptr, CompactionJobInfo)->stats :
+        nullptr;
+    return wrap_t;
+}
+
+// the names of the compaction input files
+void CompactionJobInfoGetInputFiles(CompactionJobInfo_t* ptr, String_t** files, int* n)
+{
+    *n = 0;
+    if (ptr && GET_REP(ptr, CompactionJobInfo))
+    {
+        const std::vector<std::string>& vec = GET_REP(ptr, CompactionJobInfo)->input_files;
+        *files = new String_t[vec.size()];
+        for (auto& file : vec)
+        {
+            (*files)[*n].rep = new String(file);
+            (*n)++;
+        }
+    }
+}
+
+// the names of the compaction output files
+void CompactionJobInfoGetOutputFiles(CompactionJobInfo_t* ptr, String_t** files, int* n)
+{
+    *n = 0;
+    if (ptr && GET_REP(ptr, CompactionJobInfo))
+    {
+        const std::vector<std::string>& vec = GET_REP(ptr, CompactionJobInfo)->output_files;
+        *files = new String_t[vec.size()];
+        for (auto& file : vec)
+        {
+            (*files)[*n].rep = new String(file);
+            (*n)++;
+        }
+    }
+}
+
+// C++ wrap class for go IEventListener
+// EventListener class contains a set of call-back functions that will
+// be called when specific RocksDB event happens such as flush.
+class EventListenerGo : public EventListener {
+public:
+    EventListenerGo(void* go_listener)
+        : m_go_listener(go_listener)
+    {
+    }
+
+    // Destructor
+    ~EventListenerGo()
+    {
+        if (m_go_listener)
+        {
+            InterfacesRemoveReference(m_go_listener);
+        }
+    }
+
+    // A call-back function to RocksDB which will be called whenever a
+    // registered RocksDB flushes a file.
+    virtual void OnFlushCompleted(DB* db, const FlushJobInfo& flush_job_info) override
+    {
+        String db_name(db->GetName());
+        String_t db_name_t{&db_name};
+        FlushJobInfo_t info{const_cast<FlushJobInfo *>(&flush_job_info)};
+        IEventListenerOnFlushCompleted(m_go_listener, &db_name_t, &info);
+    }
+
+    // A call-back function for RocksDB which will be called whenever
+    // a SST file is deleted.
+    virtual void OnTableFileDeleted(const TableFileDeletionInfo& deletion_info) override
+    {
+        TableFileDeletionInfo_t info{const_cast<TableFileDeletionInfo *>(&deletion_info)};
+        IEventListenerOnTableFileDeleted(m_go_listener, &info);
+    }
+
+    // A call-back function for RocksDB which will be called whenever
+    // a registered RocksDB compacts a file.
+    virtual void OnCompactionCompleted(DB* db, const CompactionJobInfo& ci) override
+    {
+        String db_name(db->GetName());
+        String_t db_name_t{&db_name};
+        CompactionJobInfo_t info{const_cast<CompactionJobInfo *>(&ci)};
+        IEventListenerOnCompactionCompleted(m_go_listener, &db_name_t, &info);
+    }
+
+    // A call-back function for RocksDB which will be called whenever
+    // a SST file is created.
+    virtual void OnTableFileCreated(const TableFileCreationInfo& creation_info) override
+    {
+        TableFileCreationInfo_t info{const_cast<TableFileCreationInfo *>(&creation_info)};
+        IEventListenerOnTableFileCreated(m_go_listener, &info);
+    }
+
+    // A call-back function for RocksDB which will be called before
+    // a column family handle is deleted.
+    // Not declared override as rocksdb 4.5 does not declare it in
+    // EventListener and so never calls it. Only the later versions
+    // declaring it do.
+    virtual void OnColumnFamilyHandleDeletionStarted(ColumnFamilyHandle* handle)
+    {
+        String cf_name(handle->GetName());
+        String_t cf_name_t{&cf_name};
+        IEventListenerOnColumnFamilyHandleDeletionStarted(m_go_listener, &cf_name_t, handle->GetID());
+    }
+
+private:
+    // Wrapped go IEventListener
+    void* m_go_listener;
+};
+
+// Add an EventListener calling back the go IEventListener to
+// the listeners of options
+void DBOptionsAddListener(DBOptions_t* options, void* go_listener)
+{
+    if (options && GET_REP(options, DBOptions) && go_listener)
+    {
+        GET_REP(options, DBOptions)->listeners.push_back(std::make_shared<EventListenerGo>(go_listener));
+    }
+}
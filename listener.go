@@ -0,0 +1,335 @@
+// Copyright (c) 2015, Dean ChaoJun Pan.  All rights reserved.
+// This source code is licensed under the BSD-style license found in the
+// LICENSE file in the root directory of this source tree.
+
+package rocksdb
+
+/*
+#include "listener.h"
+*/
+import "C"
+
+import (
+	"unsafe"
+)
+
+// Information about a newly created table file
+type TableFileCreationInfo struct {
+	// the name of the database where the file was created
+	DBName string
+	// the name of the column family where the file was created.
+	ColumnFamilyName string
+	// the path to the created file.
+	FilePath string
+	// the size of the file.
+	FileSize uint64
+	// the id of the job (which could be flush or compaction) that
+	// created the file.
+	JobID int
+	// Detailed properties of the created file.
+	TableProperties *TableProperties
+}
+
+// C TableFileCreationInfo to go TableFileCreationInfo
+func (ctfci *C.TableFileCreationInfo_t) toTableFileCreationInfo() (tfci *TableFileCreationInfo) {
+	var (
+		cdbname C.String_t = C.TableFileCreationInfo_get_db_name(ctfci)
+		ccfname C.String_t = C.TableFileCreationInfo_get_cf_name(ctfci)
+		cfpath C.String_t = C.TableFileCreationInfo_get_file_path(ctfci)
+		ctp C.TableProperties_t = C.TableFileCreationInfo_get_table_properties(ctfci)
+	)
+
+	tfci = &TableFileCreationInfo{}
+	tfci.DBName = cdbname.cToString()
+	tfci.ColumnFamilyName = ccfname.cToString()
+	tfci.FilePath = cfpath.cToString()
+	tfci.FileSize = uint64(C.TableFileCreationInfo_get_file_size(ctfci))
+	tfci.JobID = int(C.TableFileCreationInfo_get_job_id(ctfci))
+	tfci.TableProperties = ctp.toTableProperties()
+	return
+}
+
+// Information about a deleted table file
+type TableFileDeletionInfo struct {
+	// The name of the database where the file was deleted.
+	DBName string
+	// The path to the deleted file.
+	FilePath string
+	// The id of the job which deleted the file.
+	JobID int
+	// The status indicating whether the deletion was successful or not.
+	Status *Status
+}
+
+// C TableFileDeletionInfo to go TableFileDeletionInfo
+func (ctfdi *C.TableFileDeletionInfo_t) toTableFileDeletionInfo() (tfdi *TableFileDeletionInfo) {
+	var (
+		cdbname C.String_t = C.TableFileDeletionInfo_get_db_name(ctfdi)
+		cfpath C.String_t = C.TableFileDeletionInfo_get_file_path(ctfdi)
+		cstat C.Status_t = C.TableFileDeletionInfo_get_status(ctfdi)
+	)
+
+	tfdi = &TableFileDeletionInfo{}
+	tfdi.DBName = cdbname.cToString()
+	tfdi.FilePath = cfpath.cToString()
+	tfdi.JobID = int(C.TableFileDeletionInfo_get_job_id(ctfdi))
+	tfdi.Status = cstat.toStatus()
+	return
+}
+
+// Information about a completed flush
+type FlushJobInfo struct {
+	// the name of the database the flush happened in
+	DBName string
+	// the name of the column family
+	ColumnFamilyName string
+	// the path to the newly created file
+	FilePath string
+	// the id of the thread that completed this flush job.
+	ThreadID uint64
+	// the job id, which is unique in the same thread.
+	JobID int
+	// If true, then rocksdb is currently slowing-down all writes to prevent
+	// creating too many Level 0 files as compaction seems not able to
+	// catch up the write request speed.  This indicates that there are
+	// too many files in Level 0.
+	TriggeredWritesSlowdown bool
+	// If true, then rocksdb is currently blocking any writes to prevent
+	// creating more L0 files.  This indicates that there are too many
+	// files in level 0.  Compactions should try to compact L0 files down
+	// to lower levels as soon as possible.
+	TriggeredWritesStop bool
+	// The smallest sequence number in the newly created file
+	SmallestSeqno SequenceNumber
+	// The largest sequence number in the newly created file
+	LargestSeqno SequenceNumber
+}
+
+// C FlushJobInfo to go FlushJobInfo
+func (cfji *C.FlushJobInfo_t) toFlushJobInfo() (fji *FlushJobInfo) {
+	var (
+		ccfname C.String_t = C.FlushJobInfo_get_cf_name(cfji)
+		cfpath C.String_t = C.FlushJobInfo_get_file_path(cfji)
+	)
+
+	fji = &FlushJobInfo{}
+	fji.ColumnFamilyName = ccfname.cToString()
+	fji.FilePath = cfpath.cToString()
+	fji.ThreadID = uint64(C.FlushJobInfo_get_thread_id(cfji))
+	fji.JobID = int(C.FlushJobInfo_get_job_id(cfji))
+	fji.TriggeredWritesSlowdown = C.FlushJobInfo_get_triggered_writes_slowdown(cfji).toBool()
+	fji.TriggeredWritesStop = C.FlushJobInfo_get_triggered_writes_stop(cfji).toBool()
+	fji.SmallestSeqno = SequenceNumber(C.FlushJobInfo_get_smallest_seqno(cfji))
+	fji.LargestSeqno = SequenceNumber(C.FlushJobInfo_get_largest_seqno(cfji))
+	return
+}
+
+// The statistics of a compaction job
+type CompactionJobStats struct {
+	// the elapsed time in micro of this compaction.
+	ElapsedMicros uint64
+
+	// the number of compaction input records.
+	NumInputRecords uint64
+	// the number of compaction input files.
+	NumInputFiles uint64
+	// the number of compaction input files at the output level.
+	NumInputFilesAtOutputLevel uint64
+
+	// the number of compaction output records.
+	NumOutputRecords uint64
+	// the number of compaction output files.
+	NumOutputFiles uint64
+
+	// true if the compaction is a manual compaction
+	IsManualCompaction bool
+
+	// the size of the compaction input in bytes.
+	TotalInputBytes uint64
+	// the size of the compaction output in bytes.
+	TotalOutputBytes uint64
+
+	// number of records being replaced by newer record associated with same key.
+	// this could be a new value or a deletion entry for that key so this field
+	// sums up all updated and deleted keys
+	NumRecordsReplaced uint64
+
+	// the sum of the uncompressed input keys in bytes.
+	TotalInputRawKeyBytes uint64
+	// the sum of the uncompressed input values in bytes.
+	TotalInputRawValueBytes uint64
+
+	// the number of deletion entries before compaction. Deletion entries
+	// can disappear after compaction because they expired
+	NumInputDeletionRecords uint64
+	// number of deletion records that were found obsolete and discarded
+	// because it is not possible to delete any more keys with this entry
+	// (i.e. all possible deletions resulting from it have been completed)
+	NumExpiredDeletionRecords uint64
+
+	// number of corrupt keys (ParseInternalKey returned false when applied to
+	// the key) encountered and written out.
+	NumCorruptKeys uint64
+}
+
+// C CompactionJobStats to go CompactionJobStats
+func (ccjs *C.CompactionJobStats_t) toCompactionJobStats() (cjs CompactionJobStats) {
+	cjs.ElapsedMicros = uint64(C.CompactionJobStats_get_elapsed_micros(ccjs))
+	cjs.NumInputRecords = uint64(C.CompactionJobStats_get_num_input_records(ccjs))
+	cjs.NumInputFiles = uint64(C.CompactionJobStats_get_num_input_files(ccjs))
+	cjs.NumInputFilesAtOutputLevel = uint64(C.CompactionJobStats_get_num_input_files_at_output_level(ccjs))
+	cjs.NumOutputRecords = uint64(C.CompactionJobStats_get_num_output_records(ccjs))
+	cjs.NumOutputFiles = uint64(C.CompactionJobStats_get_num_output_files(ccjs))
+	cjs.IsManualCompaction = C.CompactionJobStats_get_is_manual_compaction(ccjs).toBool()
+	cjs.TotalInputBytes = uint64(C.CompactionJobStats_get_total_input_bytes(ccjs))
+	cjs.TotalOutputBytes = uint64(C.CompactionJobStats_get_total_output_bytes(ccjs))
+	cjs.NumRecordsReplaced = uint64(C.CompactionJobStats_get_num_records_replaced(ccjs))
+	cjs.TotalInputRawKeyBytes = uint64(C.CompactionJobStats_get_total_input_raw_key_bytes(ccjs))
+	cjs.TotalInputRawValueBytes = uint64(C.CompactionJobStats_get_total_input_raw_value_bytes(ccjs))
+	cjs.NumInputDeletionRecords = uint64(C.CompactionJobStats_get_num_input_deletion_records(ccjs))
+	cjs.NumExpiredDeletionRecords = uint64(C.CompactionJobStats_get_num_expired_deletion_records(ccjs))
+	cjs.NumCorruptKeys = uint64(C.CompactionJobStats_get_num_corrupt_keys(ccjs))
+	return
+}
+
+// Information about a completed compaction
+type CompactionJobInfo struct {
+	// the name of the database the compaction happened in
+	DBName string
+	// the name of the column family where the compaction happened.
+	ColumnFamilyName string
+	// the status indicating whether the compaction was successful or not.
+	Status *Status
+	// the id of the thread that completed this compaction job.
+	ThreadID uint64
+	// the job id, which is unique in the same thread.
+	JobID int
+	// the smallest input level of the compaction.
+	BaseInputLevel int
+	// the output level of the compaction.
+	OutputLevel int
+	// the names of the compaction input files.
+	InputFiles []string
+	// the names of the compaction output files.
+	OutputFiles []string
+	// Information about the compaction.
+	Stats CompactionJobStats
+}
+
+// C CompactionJobInfo to go CompactionJobInfo
+func (ccji *C.CompactionJobInfo_t) toCompactionJobInfo() (cji *CompactionJobInfo) {
+	var (
+		ccfname C.String_t = C.CompactionJobInfo_get_cf_name(ccji)
+		cstat C.Status_t = C.CompactionJobInfo_get_status(ccji)
+		ccjs C.CompactionJobStats_t = C.CompactionJobInfo_get_stats(ccji)
+		cinfiles *C.String_t
+		coutfiles *C.String_t
+		nin C.int
+		nout C.int
+	)
+
+	cji = &CompactionJobInfo{}
+	cji.ColumnFamilyName = ccfname.cToString()
+	cji.Status = cstat.toStatus()
+	cji.ThreadID = uint64(C.CompactionJobInfo_get_thread_id(ccji))
+	cji.JobID = int(C.CompactionJobInfo_get_job_id(ccji))
+	cji.BaseInputLevel = int(C.CompactionJobInfo_get_base_input_level(ccji))
+	cji.OutputLevel = int(C.CompactionJobInfo_get_output_level(ccji))
+	C.CompactionJobInfoGetInputFiles(ccji, &cinfiles, &nin)
+	cji.InputFiles = newStringArrayFromCArray(cinfiles, uint(nin))
+	C.CompactionJobInfoGetOutputFiles(ccji, &coutfiles, &nout)
+	cji.OutputFiles = newStringArrayFromCArray(coutfiles, uint(nout))
+	cji.Stats = ccjs.toCompactionJobStats()
+	return
+}
+
+// EventListener class contains a set of call-back functions that will
+// be called when specific RocksDB event happens such as flush.  It can
+// be used as a building block for developing custom features such as
+// stats-collector or external compaction algorithm.
+//
+// Note that call-back functions should not run for an extended period of
+// time before the function returns, otherwise RocksDB may be blocked.
+// The call-backs are called from the threads of RocksDB, so they must be
+// thread-safe if the listener is shared.
+type IEventListener interface {
+	// A call-back function to RocksDB which will be called whenever a
+	// registered RocksDB flushes a file.
+	OnFlushCompleted(info *FlushJobInfo)
+
+	// A call-back function for RocksDB which will be called whenever
+	// a registered RocksDB compacts a file.
+	OnCompactionCompleted(info *CompactionJobInfo)
+
+	// A call-back function for RocksDB which will be called whenever
+	// a SST file is created.
+	OnTableFileCreated(info *TableFileCreationInfo)
+
+	// A call-back function for RocksDB which will be called whenever
+	// a SST file is deleted.
+	OnTableFileDeleted(info *TableFileDeletionInfo)
+
+	// A call-back function for RocksDB which will be called before
+	// a column family handle is deleted. Unsupported by the tested
+	// rocksdb 4.5, which never calls it. Only the later versions
+	// declaring EventListener::OnColumnFamilyHandleDeletionStarted do.
+	OnColumnFamilyHandleDeletionStarted(name string, id uint32)
+}
+
+// EventListener implements IEventListener with call-backs doing
+// nothing. Embed it to only implement the call-backs of interest.
+type EventListener struct{}
+
+func (el EventListener) OnFlushCompleted(info *FlushJobInfo) {}
+func (el EventListener) OnCompactionCompleted(info *CompactionJobInfo) {}
+func (el EventListener) OnTableFileCreated(info *TableFileCreationInfo) {}
+func (el EventListener) OnTableFileDeleted(info *TableFileDeletionInfo) {}
+func (el EventListener) OnColumnFamilyHandleDeletionStarted(name string, id uint32) {}
+
+// Wrap functions for IEventListener
+
+//export IEventListenerOnFlushCompleted
+func IEventListenerOnFlushCompleted(cel unsafe.Pointer, cdbname *C.String_t, cfji *C.FlushJobInfo_t) {
+	el := InterfacesGet(cel).(IEventListener)
+	fji := cfji.toFlushJobInfo()
+	fji.DBName = string(cdbname.cToBytes(false))
+	el.OnFlushCompleted(fji)
+}
+
+//export IEventListenerOnCompactionCompleted
+func IEventListenerOnCompactionCompleted(cel unsafe.Pointer, cdbname *C.String_t, ccji *C.CompactionJobInfo_t) {
+	el := InterfacesGet(cel).(IEventListener)
+	cji := ccji.toCompactionJobInfo()
+	cji.DBName = string(cdbname.cToBytes(false))
+	el.OnCompactionCompleted(cji)
+}
+
+//export IEventListenerOnTableFileCreated
+func IEventListenerOnTableFileCreated(cel unsafe.Pointer, ctfci *C.TableFileCreationInfo_t) {
+	el := InterfacesGet(cel).(IEventListener)
+	el.OnTableFileCreated(ctfci.toTableFileCreationInfo())
+}
+
+//export IEventListenerOnTableFileDeleted
+func IEventListenerOnTableFileDeleted(cel unsafe.Pointer, ctfdi *C.TableFileDeletionInfo_t) {
+	el := InterfacesGet(cel).(IEventListener)
+	el.OnTableFileDeleted(ctfdi.toTableFileDeletionInfo())
+}
+
+//export IEventListenerOnColumnFamilyHandleDeletionStarted
+func IEventListenerOnColumnFamilyHandleDeletionStarted(cel unsafe.Pointer, ccfname *C.String_t, cfid C.uint32_t) {
+	el := InterfacesGet(cel).(IEventListener)
+	el.OnColumnFamilyHandleDeletionStarted(string(ccfname.cToBytes(false)), uint32(cfid))
+}
+
+// Add the IEventListener whose call-back functions will be called
+// when specific RocksDB event happens. The listener is kept alive
+// until the options and every DB opened with them are released.
+func (dbopt *DBOptions) AddListener(itf IEventListener) {
+	if nil == itf {
+		return
+	}
+
+	var cdbopt *C.DBOptions_t = &dbopt.dbopt
+	C.DBOptionsAddListener(cdbopt, InterfacesAddReference(itf))
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2015, Dean ChaoJun Pan.  All rights reserved.
+// This source code is licensed under the BSD-style license found in the
+// LICENSE file in the root directory of this source tree.
+
+#ifndef GO_ROCKSDB_INCLUDE_LISTENER_H_
+#define GO_ROCKSDB_INCLUDE_LISTENER_H_
+
+#include "types.h"
+#include "cstring.h"
+#include "status.h"
+#include "table_properties.h"
+#include "options.h"
+
+#ifdef __cplusplus
+extern "C" {
+#endif
+
+DEFINE_C_WRAP_STRUCT(TableFileCreationInfo)
+// Get methods
+DEFINE_C_WRAP_GETTER_DEC(TableFileCreationInfo, file_size, uint64_t)
+DEFINE_C_WRAP_GETTER_DEC(TableFileCreationInfo, job_id, int)
+String_t TableFileCreationInfo_get_db_name(TableFileCreationInfo_t* ptr);
+String_t TableFileCreationInfo_get_cf_name(TableFileCreationInfo_t* ptr);
+String_t TableFileCreationInfo_get_file_path(TableFileCreationInfo_t* ptr);
+TableProperties_t TableFileCreationInfo_get_table_properties(TableFileCreationInfo_t* ptr);
+
+DEFINE_C_WRAP_STRUCT(TableFileDeletionInfo)
+// Get methods
+DEFINE_C_WRAP_GETTER_DEC(TableFileDeletionInfo, job_id, int)
+String_t TableFileDeletionInfo_get_db_name(TableFileDeletionInfo_t* ptr);
+String_t TableFileDeletionInfo_get_file_path(TableFileDeletionInfo_t* ptr);
+Status_t TableFileDeletionInfo_get_status(TableFileDeletionInfo_t* ptr);
+
+DEFINE_C_WRAP_STRUCT(FlushJobInfo)
+// Get methods
+DEFINE_C_WRAP_GETTER_DEC(FlushJobInfo, thread_id, uint64_t)
+DEFINE_C_WRAP_GETTER_DEC(FlushJobInfo, job_id, int)
+DEFINE_C_WRAP_GETTER_DEC(FlushJobInfo, triggered_writes_slowdown, bool)
+DEFINE_C_WRAP_GETTER_DEC(FlushJobInfo, triggered_writes_stop, bool)
+DEFINE_C_WRAP_GETTER_DEC(FlushJobInfo, smallest_seqno, SequenceNumber)
+DEFINE_C_WRAP_GETTER_DEC(FlushJobInfo, largest_seqno, SequenceNumber)
+String_t FlushJobInfo_get_cf_name(FlushJobInfo_t* ptr);
+String_t FlushJobInfo_get_file_path(FlushJobInfo_t* ptr);
+
+DEFINE_C_WRAP_STRUCT(CompactionJobStats)
+// Get methods
+DEFINE_C_WRAP_GETTER_DEC(CompactionJobStats, elapsed_micros, uint64_t)
+DEFINE_C_WRAP_GETTER_DEC(CompactionJobStats, num_input_records, uint64_t)
+DEFINE_C_WRAP_GETTER_DEC(CompactionJobStats, num_input_files, size_t)
+DEFINE_C_WRAP_GETTER_DEC(CompactionJobStats, num_input_files_at_output_level, size_t)
+DEFINE_C_WRAP_GETTER_DEC(CompactionJobStats, num_output_records, uint64_t)
+DEFINE_C_WRAP_GETTER_DEC(CompactionJobStats, num_output_files, size_t)
+DEFINE_C_WRAP_GETTER_DEC(CompactionJobStats, is_manual_compaction, bool)
+DEFINE_C_WRAP_GETTER_DEC(CompactionJobStats, total_input_bytes, uint64_t)
+DEFINE_C_WRAP_GETTER_DEC(CompactionJobStats, total_output_bytes, uint64_t)
+DEFINE_C_WRAP_GETTER_DEC(CompactionJobStats, num_records_replaced, uint64_t)
+DEFINE_C_WRAP_GETTER_DEC(CompactionJobStats, total_input_raw_key_bytes, uint64_t)
+DEFINE_C_WRAP_GETTER_DEC(CompactionJobStats, total_input_raw_value_bytes, uint64_t)
+DEFINE_C_WRAP_GETTER_DEC(CompactionJobStats, num_input_deletion_records, uint64_t)
+DEFINE_C_WRAP_GETTER_DEC(CompactionJobStats, num_expired_deletion_records, uint64_t)
+DEFINE_C_WRAP_GETTER_DEC(CompactionJobStats, num_corrupt_keys, uint64_t)
+
+DEFINE_C_WRAP_STRUCT(CompactionJobInfo)
+// Get methods
+DEFINE_C_WRAP_GETTER_DEC(CompactionJobInfo, thread_id, uint64_t)
+DEFINE_C_WRAP_GETTER_DEC(CompactionJobInfo, job_id, int)
+DEFINE_C_WRAP_GETTER_DEC(CompactionJobInfo, base_input_level, int)
+DEFINE_C_WRAP_GETTER_DEC(CompactionJobInfo, output_level, int)
+String_t CompactionJobInfo_get_cf_name(CompactionJobInfo_t* ptr);
+Status_t CompactionJobInfo_get_status(CompactionJobInfo_t* ptr);
+CompactionJobStats_t CompactionJobInfo_get_stats(CompactionJobInfo_t* ptr);
+// The names of the compaction input and output files
+void CompactionJobInfoGetInputFiles(CompactionJobInfo_t* ptr, String_t** files, int* n);
+void CompactionJobInfoGetOutputFiles(CompactionJobInfo_t* ptr, String_t** files, int* n);
+
+// Add an EventListener calling back the go IEventListener to
+// the listeners of options
+void DBOptionsAddListener(DBOptions_t* options, void* go_listener);
+
+#ifdef __cplusplus
+}  /* end extern "C" */
+#endif
+
+#endif  // GO_ROCKSDB_INCLUDE_LISTENER_H_
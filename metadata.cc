@@ -7,9 +7,134 @@
 
 using namespace rocksdb;
 
+// The metadata associated with each SST file.
+DEFINE_C_WRAP_CONSTRUCTOR(SstFileMetaData)
+DEFINE_C_WRAP_DESTRUCTOR(SstFileMetaData)
+DEFINE_C_WRAP_GETTER(SstFileMetaData, size, uint64_t)
+DEFINE_C_WRAP_GETTER(SstFileMetaData, smallest_seqno, SequenceNumber)
+DEFINE_C_WRAP_GETTER(SstFileMetaData, largest_seqno, SequenceNumber)
+DEFINE_C_WRAP_GETTER(SstFileMetaData, being_compacted, bool)
+
+// The name of the file.
+String_t SstFileMetaData_get_name(SstFileMetaData_t* ptr)
+{
+    String ret;
+    if (ptr && GET_REP(ptr, SstFileMetaData))
+    {
+        ret = GET_REP(ptr, SstFileMetaData)->name;
+    }
+    return NewStringTCopy(&ret);
+}
+
+// The full path where the file locates.
+String_t SstFileMetaData_get_db_path(SstFileMetaData_t* ptr)
+{
+    String ret;
+    if (ptr && GET_REP(ptr, SstFileMetaData))
+    {
+        ret = GET_REP(ptr, SstFileMetaData)->db_path;
+    }
+    return NewStringTCopy(&ret);
+}
+
+// Smallest user defined key in the file.
+String_t SstFileMetaData_get_smallestkey(SstFileMetaData_t* ptr)
+{
+    String ret;
+    if (ptr && GET_REP(ptr, SstFileMetaData))
+    {
+        ret = GET_REP(ptr, SstFileMetaData)->smallestkey;
+    }
+    return NewStringTCopy(&ret);
+}
+
+// Largest user defined key in the file.
+String_t SstFileMetaData_get_largestkey(SstFileMetaData_t* ptr)
+{
+    String ret;
+    if (ptr && GET_REP(ptr, SstFileMetaData))
+    {
+        ret = GET_REP(ptr, SstFileMetaData)->largestkey;
+    }
+    return NewStringTCopy(&ret);
+}
+
+// The metadata that describes a level.
+DEFINE_C_WRAP_CONSTRUCTOR(LevelMetaData)
+DEFINE_C_WRAP_DESTRUCTOR(LevelMetaData)
+DEFINE_C_WRAP_GETTER(LevelMetaData, level, int)
+DEFINE_C_WRAP_GETTER(LevelMetaData, size, uint64_t)
+
+// The number of the files in this level.
+size_t LevelMetaDataFilesSize(LevelMetaData_t* ptr)
+{
+    return ((ptr && GET_REP(ptr, LevelMetaData)) ?
+            GET_REP(ptr, LevelMetaData)->files.size() :
+            0);
+}
+
+// The i-th file in this level. The result remains the property
+// of the LevelMetaData and must not be deleted.
+SstFileMetaData_t LevelMetaDataGetFile(LevelMetaData_t* ptr, size_t i)
+{
+    SstFileMetaData_t wrap_t;
+    wrap_t.rep = ((ptr && GET_REP(ptr, LevelMetaData) && i < GET_REP(ptr, LevelMetaData)->files.size()) ?
+                  &GET_REP(ptr, LevelMetaData)->files[i] :
+                  nullptr);
+    return wrap_t;
+}
+
+// The metadata that describes a column family.
 DEFINE_C_WRAP_CONSTRUCTOR(ColumnFamilyMetaData)
+DEFINE_C_WRAP_CONSTRUCTOR_DEFAULT(ColumnFamilyMetaData)
 DEFINE_C_WRAP_DESTRUCTOR(ColumnFamilyMetaData)
+DEFINE_C_WRAP_GETTER(ColumnFamilyMetaData, size, uint64_t)
+DEFINE_C_WRAP_GETTER(ColumnFamilyMetaData, file_count, size_t)
+
+// The name of the column family.
+String_t ColumnFamilyMetaData_get_name(ColumnFamilyMetaData_t* ptr)
+{
+    String ret;
+    if (ptr && GET_REP(ptr, ColumnFamilyMetaData))
+    {
+        ret = GET_REP(ptr, ColumnFamilyMetaData)->name;
+    }
+    return NewStringTCopy(&ret);
+}
 
+// The number of the levels of the column family.
+size_t ColumnFamilyMetaDataLevelsSize(ColumnFamilyMetaData_t* ptr)
+{
+    return ((ptr && GET_REP(ptr, ColumnFamilyMetaData)) ?
+            GET_REP(ptr, ColumnFamilyMetaData)->levels.size() :
+            0);
+}
+
+// The metadata of the i-th level. The result remains the property
+// of the ColumnFamilyMetaData and must not be deleted.
+LevelMetaData_t ColumnFamilyMetaDataGetLevel(ColumnFamilyMetaData_t* ptr, size_t i)
+{
+    LevelMetaData_t wrap_t;
+    wrap_t.rep = ((ptr && GET_REP(ptr, ColumnFamilyMetaData) && i < GET_REP(ptr, ColumnFamilyMetaData)->levels.size()) ?
+                  &GET_REP(ptr, ColumnFamilyMetaData)->levels[i] :
+                  nullptr);
+    return wrap_t;
+}
+
+// The full set of metadata associated with each SST file.
 DEFINE_C_WRAP_CONSTRUCTOR(LiveFileMetaData)
 DEFINE_C_WRAP_DESTRUCTOR(LiveFileMetaData)
 DEFINE_C_WRAP_DESTRUCTOR_ARRAY(LiveFileMetaData)
+DEFINE_C_WRAP_STATIC_CAST(LiveFileMetaData, SstFileMetaData)
+DEFINE_C_WRAP_GETTER(LiveFileMetaData, level, int)
+
+// Name of the column family
+String_t LiveFileMetaData_get_column_family_name(LiveFileMetaData_t* ptr)
+{
+    String ret;
+    if (ptr && GET_REP(ptr, LiveFileMetaData))
+    {
+        ret = GET_REP(ptr, LiveFileMetaData)->column_family_name;
+    }
+    return NewStringTCopy(&ret);
+}
@@ -10,40 +10,139 @@ package rocksdb
 import "C"
 
 import (
-	"runtime"
 	"unsafe"
 )
 
-type ColumnFamilyMetaData struct {
-	cfmd C.ColumnFamilyMetaData_t
+// The metadata that describes a SST file.
+type SstFileMetaData struct {
+	// File size in bytes.
+	Size uint64
+	// The name of the file.
+	Name string
+	// The full path where the file locates.
+	DbPath string
+
+	// Smallest sequence number in file.
+	SmallestSeqno SequenceNumber
+	// Largest sequence number in file.
+	LargestSeqno SequenceNumber
+	// Smallest user defined key in the file.
+	SmallestKey []byte
+	// Largest user defined key in the file.
+	LargestKey []byte
+	// true if the file is currently being compacted.
+	BeingCompacted bool
+}
+
+// C SstFileMetaData to go SstFileMetaData
+func (csfmd *C.SstFileMetaData_t) toSstFileMetaData() (sfmd SstFileMetaData) {
+	var (
+		cname C.String_t = C.SstFileMetaData_get_name(csfmd)
+		cdbpath C.String_t = C.SstFileMetaData_get_db_path(csfmd)
+		csmallestkey C.String_t = C.SstFileMetaData_get_smallestkey(csfmd)
+		clargestkey C.String_t = C.SstFileMetaData_get_largestkey(csfmd)
+	)
+
+	sfmd.Size = uint64(C.SstFileMetaData_get_size(csfmd))
+	sfmd.Name = cname.cToString()
+	sfmd.DbPath = cdbpath.cToString()
+	sfmd.SmallestSeqno = SequenceNumber(C.SstFileMetaData_get_smallest_seqno(csfmd))
+	sfmd.LargestSeqno = SequenceNumber(C.SstFileMetaData_get_largest_seqno(csfmd))
+	sfmd.SmallestKey = csmallestkey.cToBytes(true)
+	sfmd.LargestKey = clargestkey.cToBytes(true)
+	sfmd.BeingCompacted = C.SstFileMetaData_get_being_compacted(csfmd).toBool()
+	return
+}
+
+// The metadata that describes a level.
+type LevelMetaData struct {
+	// The level which this meta data describes.
+	Level int
+	// The size of this level in bytes, which is equal to the sum of
+	// the file size of its "files".
+	Size uint64
+	// The metadata of all sst files in this level.
+	Files []SstFileMetaData
+}
+
+// C LevelMetaData to go LevelMetaData
+func (clmd *C.LevelMetaData_t) toLevelMetaData() (lmd LevelMetaData) {
+	lmd.Level = int(C.LevelMetaData_get_level(clmd))
+	lmd.Size = uint64(C.LevelMetaData_get_size(clmd))
+	sz := C.LevelMetaDataFilesSize(clmd)
+	lmd.Files = make([]SstFileMetaData, sz)
+	for i := C.size_t(0); i < sz; i++ {
+		csfmd := C.LevelMetaDataGetFile(clmd, i)
+		lmd.Files[i] = csfmd.toSstFileMetaData()
+	}
+	return
 }
 
-func (cfmd *ColumnFamilyMetaData) finalize() {
-	var ccfmd *C.ColumnFamilyMetaData_t = &cfmd.cfmd
-	C.DeleteColumnFamilyMetaDataT(ccfmd, toCBool(false))
+// The metadata that describes a column family.
+type ColumnFamilyMetaData struct {
+	// The size of this column family in bytes, which is equal to the sum of
+	// the file size of its "levels".
+	Size uint64
+	// The number of files in this column family.
+	FileCount uint64
+	// The name of the column family.
+	Name string
+	// The metadata of all levels in this column family.
+	Levels []LevelMetaData
 }
 
-func (ccfmd *C.ColumnFamilyMetaData_t) toColumnFamilyMetaData() (cfmd *ColumnFamilyMetaData) {
-	cfmd = &ColumnFamilyMetaData{cfmd: *ccfmd}	
-	runtime.SetFinalizer(cfmd, finalize)
+// C ColumnFamilyMetaData to go ColumnFamilyMetaData.
+// Delete the underlying @ccfmd if del is true.
+func (ccfmd *C.ColumnFamilyMetaData_t) toColumnFamilyMetaData(del bool) (cfmd *ColumnFamilyMetaData) {
+	if del {
+		defer C.DeleteColumnFamilyMetaDataT(ccfmd, toCBool(false))
+	}
+
+	var cname C.String_t = C.ColumnFamilyMetaData_get_name(ccfmd)
+	cfmd = &ColumnFamilyMetaData{}
+	cfmd.Size = uint64(C.ColumnFamilyMetaData_get_size(ccfmd))
+	cfmd.FileCount = uint64(C.ColumnFamilyMetaData_get_file_count(ccfmd))
+	cfmd.Name = cname.cToString()
+	sz := C.ColumnFamilyMetaDataLevelsSize(ccfmd)
+	cfmd.Levels = make([]LevelMetaData, sz)
+	for i := C.size_t(0); i < sz; i++ {
+		clmd := C.ColumnFamilyMetaDataGetLevel(ccfmd, i)
+		cfmd.Levels[i] = clmd.toLevelMetaData()
+	}
 	return
 }
 
+// The full set of metadata associated with each SST file.
 type LiveFileMetaData struct {
-	lfmd C.LiveFileMetaData_t
+	SstFileMetaData
+	// Name of the column family
+	ColumnFamilyName string
+	// Level at which this file resides.
+	Level int
 }
 
-func (lfmd *LiveFileMetaData) finalize() {
-	var clfmd *C.LiveFileMetaData_t = &lfmd.lfmd
-	C.DeleteLiveFileMetaDataT(clfmd, toCBool(false))
+// C LiveFileMetaData to go LiveFileMetaData
+func (clfmd *C.LiveFileMetaData_t) toLiveFileMetaData() (lfmd *LiveFileMetaData) {
+	var (
+		csfmd C.SstFileMetaData_t
+		ccfname C.String_t = C.LiveFileMetaData_get_column_family_name(clfmd)
+	)
+
+	C.LiveFileMetaDataTStaticCastToSstFileMetaDataT(clfmd, &csfmd)
+	lfmd = &LiveFileMetaData{SstFileMetaData: csfmd.toSstFileMetaData()}
+	lfmd.ColumnFamilyName = ccfname.cToString()
+	lfmd.Level = int(C.LiveFileMetaData_get_level(clfmd))
+	return
 }
 
+// C LiveFileMetaData array to go LiveFileMetaData array
 func newLiveFileMetaDataArrayFromCArray(clfmd *C.LiveFileMetaData_t, sz uint) (lfmds []*LiveFileMetaData) {
 	defer C.DeleteLiveFileMetaDataTArray(clfmd)
 	lfmds = make([]*LiveFileMetaData, sz)
 	for i := uint(0); i < sz; i++ {
-		lfmds[i] = &LiveFileMetaData{lfmd: (*[arrayDimenMax]C.LiveFileMetaData_t)(unsafe.Pointer(clfmd))[i]}
-		runtime.SetFinalizer(lfmds[i], finalize)
+		var clfmdi *C.LiveFileMetaData_t = &(*[arrayDimenMax]C.LiveFileMetaData_t)(unsafe.Pointer(clfmd))[i]
+		lfmds[i] = clfmdi.toLiveFileMetaData()
+		C.DeleteLiveFileMetaDataT(clfmdi, toCBool(false))
 	}
 	return
 }
@@ -6,19 +6,53 @@
 #define GO_ROCKSDB_INCLUDE_METADATA_H_
 
 #include "types.h"
+#include "cstring.h"
 
 #ifdef __cplusplus
 extern "C" {
 #endif
 
+DEFINE_C_WRAP_STRUCT(SstFileMetaData)
+DEFINE_C_WRAP_CONSTRUCTOR_DEC(SstFileMetaData)
+DEFINE_C_WRAP_DESTRUCTOR_DEC(SstFileMetaData)
+// Get methods
+DEFINE_C_WRAP_GETTER_DEC(SstFileMetaData, size, uint64_t)
+DEFINE_C_WRAP_GETTER_DEC(SstFileMetaData, smallest_seqno, SequenceNumber)
+DEFINE_C_WRAP_GETTER_DEC(SstFileMetaData, largest_seqno, SequenceNumber)
+DEFINE_C_WRAP_GETTER_DEC(SstFileMetaData, being_compacted, bool)
+String_t SstFileMetaData_get_name(SstFileMetaData_t* ptr);
+String_t SstFileMetaData_get_db_path(SstFileMetaData_t* ptr);
+String_t SstFileMetaData_get_smallestkey(SstFileMetaData_t* ptr);
+String_t SstFileMetaData_get_largestkey(SstFileMetaData_t* ptr);
+
+DEFINE_C_WRAP_STRUCT(LevelMetaData)
+DEFINE_C_WRAP_CONSTRUCTOR_DEC(LevelMetaData)
+DEFINE_C_WRAP_DESTRUCTOR_DEC(LevelMetaData)
+// Get methods
+DEFINE_C_WRAP_GETTER_DEC(LevelMetaData, level, int)
+DEFINE_C_WRAP_GETTER_DEC(LevelMetaData, size, uint64_t)
+size_t LevelMetaDataFilesSize(LevelMetaData_t* ptr);
+SstFileMetaData_t LevelMetaDataGetFile(LevelMetaData_t* ptr, size_t i);
+
 DEFINE_C_WRAP_STRUCT(ColumnFamilyMetaData)
 DEFINE_C_WRAP_CONSTRUCTOR_DEC(ColumnFamilyMetaData)
+DEFINE_C_WRAP_CONSTRUCTOR_DEFAULT_DEC(ColumnFamilyMetaData)
 DEFINE_C_WRAP_DESTRUCTOR_DEC(ColumnFamilyMetaData)
+// Get methods
+DEFINE_C_WRAP_GETTER_DEC(ColumnFamilyMetaData, size, uint64_t)
+DEFINE_C_WRAP_GETTER_DEC(ColumnFamilyMetaData, file_count, size_t)
+String_t ColumnFamilyMetaData_get_name(ColumnFamilyMetaData_t* ptr);
+size_t ColumnFamilyMetaDataLevelsSize(ColumnFamilyMetaData_t* ptr);
+LevelMetaData_t ColumnFamilyMetaDataGetLevel(ColumnFamilyMetaData_t* ptr, size_t i);
 
 DEFINE_C_WRAP_STRUCT(LiveFileMetaData)
 DEFINE_C_WRAP_CONSTRUCTOR_DEC(LiveFileMetaData)
 DEFINE_C_WRAP_DESTRUCTOR_DEC(LiveFileMetaData)
 DEFINE_C_WRAP_DESTRUCTOR_ARRAY_DEC(LiveFileMetaData)
+DEFINE_C_WRAP_STATIC_CAST_DEC(LiveFileMetaData, SstFileMetaData)
+// Get methods
+DEFINE_C_WRAP_GETTER_DEC(LiveFileMetaData, level, int)
+String_t LiveFileMetaData_get_column_family_name(LiveFileMetaData_t* ptr);
 
 #ifdef __cplusplus
 }  /* end extern "C" */
@@ -0,0 +1,11 @@
+// Copyright (c) 2015, Dean ChaoJun Pan.  All rights reserved.
+// This source code is licensed under the BSD-style license found in the
+// LICENSE file in the root directory of this source tree.
+
+// +build lite
+
+package metrics
+
+// The metadata of the column families is not supported in ROCKSDB_LITE
+func (h *Handler) writeColumnFamilySizes(mw *metricWriter) {
+}
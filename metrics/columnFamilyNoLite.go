@@ -0,0 +1,45 @@
+// Copyright (c) 2015, Dean ChaoJun Pan.  All rights reserved.
+// This source code is licensed under the BSD-style license found in the
+// LICENSE file in the root directory of this source tree.
+
+// +build !lite
+
+package metrics
+
+import (
+	"strconv"
+)
+
+// Write the sizes of every column family and its levels
+func (h *Handler) writeColumnFamilySizes(mw *metricWriter) {
+	var (
+		sizename = mw.ns + "_column_family_size_bytes"
+		filesname = mw.ns + "_column_family_files"
+		levelname = mw.ns + "_level_size_bytes"
+		sizes []string
+		files []string
+		levels []string
+	)
+
+	// Collect the samples first to keep the samples of a metric together
+	for _, cf := range h.columnFamilies() {
+		md := h.DB.GetColumnFamilyMetaData(cf.cfh...)
+		if nil == md {
+			continue
+		}
+		cflabel := []label{{"column_family", cf.name}}
+		sizes = append(sizes, sampleLine(sizename, cflabel, float64(md.Size)))
+		files = append(files, sampleLine(filesname, cflabel, float64(md.FileCount)))
+		for _, lmd := range md.Levels {
+			lvlabels := []label{{"column_family", cf.name}, {"level", strconv.Itoa(lmd.Level)}}
+			levels = append(levels, sampleLine(levelname, lvlabels, float64(lmd.Size)))
+		}
+	}
+
+	mw.header(sizename, "gauge", "total size of the SST files of the column family")
+	mw.lines(sizes)
+	mw.header(filesname, "gauge", "number of SST files of the column family")
+	mw.lines(files)
+	mw.header(levelname, "gauge", "total size of the SST files of the level")
+	mw.lines(levels)
+}
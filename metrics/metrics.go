@@ -0,0 +1,266 @@
+// Copyright (c) 2015, Dean ChaoJun Pan.  All rights reserved.
+// This source code is licensed under the BSD-style license found in the
+// LICENSE file in the root directory of this source tree.
+//
+// Package metrics exports the metrics of a rocksdb database in the
+// Prometheus text exposition format. The metrics are read from the
+// database each time they are scraped:
+//  * the tickers and histograms of the Statistics set to the database,
+//  * the well-known integer properties of each column family,
+//  * the usage and capacity of the block cache,
+//  * the sizes of each column family and its levels.
+
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	rocksdb "github.com/pcjdean/rocksdb-golang"
+)
+
+// Content type of the Prometheus text exposition format
+const contentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Default prefix of the metric names
+const defaultNamespace = "rocksdb"
+
+// The well-known properties exported by GetIntProperty
+var intProperties = []string{
+	"rocksdb.num-immutable-mem-table",
+	"rocksdb.mem-table-flush-pending",
+	"rocksdb.compaction-pending",
+	"rocksdb.background-errors",
+	"rocksdb.cur-size-active-mem-table",
+	"rocksdb.cur-size-all-mem-tables",
+	"rocksdb.num-entries-active-mem-table",
+	"rocksdb.num-entries-imm-mem-tables",
+	"rocksdb.num-deletes-active-mem-table",
+	"rocksdb.num-deletes-imm-mem-tables",
+	"rocksdb.estimate-num-keys",
+	"rocksdb.estimate-table-readers-mem",
+	"rocksdb.is-file-deletions-enabled",
+	"rocksdb.num-snapshots",
+	"rocksdb.oldest-snapshot-time",
+	"rocksdb.num-live-versions",
+}
+
+// The quantiles of HistogramData
+var quantiles = []struct {
+	name string
+	value func(hd *rocksdb.HistogramData) float64
+}{
+	{"0.5", func(hd *rocksdb.HistogramData) float64 { return hd.Median }},
+	{"0.95", func(hd *rocksdb.HistogramData) float64 { return hd.Percentile95 }},
+	{"0.99", func(hd *rocksdb.HistogramData) float64 { return hd.Percentile99 }},
+}
+
+// Handler is an http.Handler rendering the metrics of a database.
+// Statistics and Cache are optional.
+type Handler struct {
+	// The database to export
+	DB *rocksdb.DB
+	// The column families to export besides the default column family
+	ColumnFamilies []*rocksdb.ColumnFamilyHandle
+	// The Statistics set to the DBOptions of DB
+	Statistics *rocksdb.Statistics
+	// The block cache set to the BlockBasedTableOptions of DB
+	Cache *rocksdb.Cache
+	// Prefix of the metric names. Default: "rocksdb"
+	Namespace string
+}
+
+// Create a Handler exporting the metrics of db and the column
+// families cfhs.
+func NewHandler(db *rocksdb.DB, stats *rocksdb.Statistics, cache *rocksdb.Cache, cfhs ...*rocksdb.ColumnFamilyHandle) *Handler {
+	return &Handler{DB: db, ColumnFamilies: cfhs, Statistics: stats, Cache: cache}
+}
+
+// Render the metrics for a scrape
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", contentType)
+	h.WriteTo(w)
+}
+
+// Write the metrics in the Prometheus text exposition format to w
+func (h *Handler) WriteTo(w io.Writer) (n int64, err error) {
+	bw := bufio.NewWriter(w)
+	mw := &metricWriter{w: bw, ns: h.Namespace}
+	if mw.ns == "" {
+		mw.ns = defaultNamespace
+	}
+
+	h.writeStatistics(mw)
+	h.writeProperties(mw)
+	h.writeCache(mw)
+	h.writeColumnFamilySizes(mw)
+
+	if err = bw.Flush(); nil == err {
+		err = mw.err
+	}
+	return mw.n, err
+}
+
+// Write the tickers as counters and the histograms as summaries
+func (h *Handler) writeStatistics(mw *metricWriter) {
+	if nil == h.Statistics {
+		return
+	}
+
+	for _, tk := range rocksdb.Tickers() {
+		name := mw.name(tk.String()) + "_total"
+		mw.header(name, "counter", tk.String())
+		mw.sample(name, nil, float64(h.Statistics.GetTickerCount(tk)))
+	}
+
+	for _, hg := range rocksdb.Histograms() {
+		hd := h.Statistics.HistogramData(hg)
+		if nil == hd {
+			continue
+		}
+		name := mw.name(hg.String())
+		mw.header(name, "summary", hg.String())
+		for _, q := range quantiles {
+			mw.sample(name, []label{{"quantile", q.name}}, q.value(hd))
+		}
+	}
+}
+
+// Write the well-known integer properties of every column family
+func (h *Handler) writeProperties(mw *metricWriter) {
+	cfs := h.columnFamilies()
+	for _, prop := range intProperties {
+		name := mw.name(prop)
+		mw.header(name, "gauge", prop)
+		for _, cf := range cfs {
+			if val, ok := h.DB.GetIntProperty([]byte(prop), cf.cfh...); ok {
+				mw.sample(name, []label{{"column_family", cf.name}}, float64(val))
+			}
+		}
+	}
+}
+
+// Write the usage and capacity of the block cache
+func (h *Handler) writeCache(mw *metricWriter) {
+	if nil == h.Cache {
+		return
+	}
+
+	name := mw.ns + "_block_cache_usage_bytes"
+	mw.header(name, "gauge", "memory size for the entries residing in the block cache")
+	mw.sample(name, nil, float64(h.Cache.GetUsage()))
+	name = mw.ns + "_block_cache_capacity_bytes"
+	mw.header(name, "gauge", "maximum configured capacity of the block cache")
+	mw.sample(name, nil, float64(h.Cache.GetCapacity()))
+}
+
+// A column family to export
+type columnFamily struct {
+	name string
+	// nil for the default column family
+	cfh []*rocksdb.ColumnFamilyHandle
+}
+
+// The default column family followed by h.ColumnFamilies
+func (h *Handler) columnFamilies() (cfs []columnFamily) {
+	cfs = append(cfs, columnFamily{name: "default"})
+	for _, cfh := range h.ColumnFamilies {
+		name := cfh.GetName()
+		if name == "default" {
+			continue
+		}
+		cfs = append(cfs, columnFamily{name: name, cfh: []*rocksdb.ColumnFamilyHandle{cfh}})
+	}
+	return
+}
+
+// A label of a sample
+type label struct {
+	name string
+	value string
+}
+
+// Write metrics in the Prometheus text exposition format.
+// The first error is kept and the following writes are skipped.
+type metricWriter struct {
+	w io.Writer
+	ns string
+	n int64
+	err error
+}
+
+// Metric name of a rocksdb ticker, histogram or property name,
+// e.g. "rocksdb.block.cache.hit" to "rocksdb_block_cache_hit"
+func (mw *metricWriter) name(rname string) string {
+	rname = strings.TrimPrefix(rname, defaultNamespace+".")
+	return mw.ns + "_" + sanitizeName(rname)
+}
+
+// Write the HELP and TYPE lines of a metric
+func (mw *metricWriter) header(name, typ, help string) {
+	mw.printf("# HELP %s %s\n", name, escapeHelp(help))
+	mw.printf("# TYPE %s %s\n", name, typ)
+}
+
+// Write a sample of a metric
+func (mw *metricWriter) sample(name string, labels []label, val float64) {
+	mw.printf("%s", sampleLine(name, labels, val))
+}
+
+// Write the sample lines formatted by sampleLine
+func (mw *metricWriter) lines(lines []string) {
+	for _, line := range lines {
+		mw.printf("%s", line)
+	}
+}
+
+// Format a sample line of a metric
+func sampleLine(name string, labels []label, val float64) string {
+	if len(labels) == 0 {
+		return fmt.Sprintf("%s %v\n", name, val)
+	}
+
+	strs := make([]string, len(labels))
+	for i, l := range labels {
+		strs[i] = fmt.Sprintf("%s=\"%s\"", l.name, escapeLabelValue(l.value))
+	}
+	return fmt.Sprintf("%s{%s} %v\n", name, strings.Join(strs, ","), val)
+}
+
+func (mw *metricWriter) printf(format string, args ...interface{}) {
+	if nil != mw.err {
+		return
+	}
+
+	n, err := fmt.Fprintf(mw.w, format, args...)
+	mw.n += int64(n)
+	mw.err = err
+}
+
+// Replace the characters not allowed in a metric name with '_'
+func sanitizeName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == ':':
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+// Escape '\' and '\n' in a HELP line
+var helpReplacer = strings.NewReplacer("\\", "\\\\", "\n", "\\n")
+
+func escapeHelp(help string) string {
+	return helpReplacer.Replace(help)
+}
+
+// Escape '\', '"' and '\n' in a label value
+var labelValueReplacer = strings.NewReplacer("\\", "\\\\", "\"", "\\\"", "\n", "\\n")
+
+func escapeLabelValue(val string) string {
+	return labelValueReplacer.Replace(val)
+}
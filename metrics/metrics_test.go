@@ -0,0 +1,72 @@
+// Copyright (c) 2015, Dean ChaoJun Pan.  All rights reserved.
+// This source code is licensed under the BSD-style license found in the
+// LICENSE file in the root directory of this source tree.
+//
+
+package metrics
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	rocksdb "github.com/pcjdean/rocksdb-golang"
+)
+
+func TestHandler(t *testing.T) {
+	dbname := fmt.Sprintf("%s/rocksdb_go_metrics_test-%d", os.TempDir(), os.Geteuid())
+	options := rocksdb.NewOptions()
+	options.SetCreateIfMissing(true)
+	stats := rocksdb.NewStatistics()
+	options.SetStatistics(stats)
+	cache := rocksdb.NewLRUCache(1 << 20)
+	db, stat, _ := rocksdb.Open(options, &dbname)
+	if !stat.Ok() {
+		t.Fatalf("metrics: open: stat = %s", stat)
+	}
+	defer rocksdb.DestroyDB(options, &dbname)
+	defer db.Close()
+
+	cf1 := "cf1"
+	cfh, stat := db.CreateColumnFamily(&options.ColumnFamilyOptions, &cf1)
+	if !stat.Ok() {
+		t.Fatalf("metrics: CreateColumnFamily: stat = %s", stat)
+	}
+	defer cfh.Close()
+	woptions := rocksdb.NewWriteOptions()
+	db.Put(woptions, []byte("foo"), []byte("bar"))
+	db.Put(woptions, []byte("foo"), []byte("bar"), cfh)
+
+	rec := httptest.NewRecorder()
+	NewHandler(db, stats, cache, cfh).ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body := rec.Body.String()
+	t.Logf("metrics:\n%s", body)
+
+	if !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/plain; version=0.0.4") {
+		t.Errorf("metrics: Content-Type = %s", rec.Header().Get("Content-Type"))
+	}
+	for _, exp := range []string{
+		"# TYPE rocksdb_number_keys_written_total counter\nrocksdb_number_keys_written_total 2\n",
+		"# TYPE rocksdb_db_get_micros summary\n",
+		"rocksdb_db_write_micros{quantile=\"0.99\"} ",
+		"rocksdb_num_entries_active_mem_table{column_family=\"default\"} 1\n",
+		"rocksdb_num_entries_active_mem_table{column_family=\"cf1\"} 1\n",
+		"rocksdb_block_cache_capacity_bytes 1.048576e+06\n",
+	} {
+		if !strings.Contains(body, exp) {
+			t.Errorf("metrics: missing %q", exp)
+		}
+	}
+}
+
+func TestSanitizeName(t *testing.T) {
+	mw := &metricWriter{ns: "app"}
+	if name := mw.name("rocksdb.estimate-num-keys"); name != "app_estimate_num_keys" {
+		t.Errorf("metrics: name = %s", name)
+	}
+	if val := escapeLabelValue("a\"b\\c\nd"); val != `a\"b\\c\nd` {
+		t.Errorf("metrics: escapeLabelValue = %s", val)
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 2015, Dean ChaoJun Pan.  All rights reserved.
+// This source code is licensed under the BSD-style license found in the
+// LICENSE file in the root directory of this source tree.
+
+#include <rocksdb/utilities/optimistic_transaction_db.h>
+
+using namespace rocksdb;
+
+#include "optimistic_transaction_db.h"
+
+DEFINE_C_WRAP_CONSTRUCTOR(OptimisticTransactionOptions)
+DEFINE_C_WRAP_CONSTRUCTOR_DEFAULT(OptimisticTransactionOptions)
+DEFINE_C_WRAP_DESTRUCTOR(OptimisticTransactionOptions)
+
+// Setting set_snapshot=true is the same as calling SetSnapshot().
+DEFINE_C_WRAP_GETTER(OptimisticTransactionOptions, set_snapshot, bool)
+DEFINE_C_WRAP_SETTER(OptimisticTransactionOptions, set_snapshot, bool)
+
+// Should be set if the DB has a non-default comparator.
+// See comment in WriteBatchWithIndex constructor.
+DEFINE_C_WRAP_SETTER_PTR_WRAP(OptimisticTransactionOptions, cmp, Comparator)
+
+DEFINE_C_WRAP_CONSTRUCTOR(OptimisticTransactionDB)
+DEFINE_C_WRAP_DESTRUCTOR(OptimisticTransactionDB)
+
+// Open an OptimisticTransactionDB similar to DB::Open().
+Status_t OptimisticTransactionDBOpen(const Options_t* options,
+                                     const String_t* name,
+                                     OptimisticTransactionDB_t* dbptr)
+{
+    assert(dbptr != NULL);
+    OptimisticTransactionDB** rdbptr = GET_REP_ADDR(dbptr, OptimisticTransactionDB);
+    assert(rdbptr != NULL);
+    assert(GET_REP(options, Options) != NULL);
+    assert(GET_REP(name, String) != NULL);
+    Status stat = OptimisticTransactionDB::Open(GET_REP_REF(options, Options), GET_REP_REF(name, String), rdbptr);
+    return NewStatusTCopy(&stat);
+}
+
+// Open an OptimisticTransactionDB with column families similar to DB::Open().
+Status_t OptimisticTransactionDBOpenWithColumnFamilies(const Options_t* options,
+                                                       const String_t* name,
+                                                       const ColumnFamilyDescriptor_t column_families[], const int size_col,
+                                                       ColumnFamilyHandle_t **handles,
+                                                       OptimisticTransactionDB_t* dbptr)
+{
+    std::vector<ColumnFamilyDescriptor> column_families_vec = std::vector<ColumnFamilyDescriptor>();
+    for (int i = 0; i < size_col; i++)
+        column_families_vec.push_back(*(ColumnFamilyDescriptor*)column_families[i].rep);
+    std::vector<ColumnFamilyHandle*> handles_vec;
+    assert(dbptr != NULL);
+    OptimisticTransactionDB** rdbptr = GET_REP_ADDR(dbptr, OptimisticTransactionDB);
+    assert(rdbptr != NULL);
+    assert(GET_REP(options, Options) != NULL);
+    assert(GET_REP(name, String) != NULL);
+    Status stat = OptimisticTransactionDB::Open(GET_REP_REF(options, Options), GET_REP_REF(name, String), column_families_vec, &handles_vec, rdbptr);
+    Status_t ret = NewStatusTCopy(&stat);
+    *handles = new ColumnFamilyHandle_t[size_col];
+    for (int j = 0; j < size_col; j++)
+    {
+        ColumnFamilyHandle** rcfh = GET_REP_ADDR(&(*handles)[j], ColumnFamilyHandle);
+        *rcfh = (j < (int)handles_vec.size()) ? handles_vec[j] : nullptr;
+    }
+    return ret;
+}
+
+// Starts a new Transaction.  Passing set_snapshot=true has the same effect
+// as calling SetSnapshot().
+//
+// Caller should delete the returned transaction after calling
+// Commit() or Rollback().
+Transaction_t OptimisticTransactionDBBeginTransaction(OptimisticTransactionDB_t* dbptr,
+                                                      const WriteOptions_t* write_options,
+                                                      const OptimisticTransactionOptions_t* txn_options)
+{
+    Transaction* txn = nullptr;
+    if (dbptr && GET_REP(dbptr, OptimisticTransactionDB))
+    {
+        assert(GET_REP(write_options, WriteOptions) != NULL);
+        txn = (txn_options ?
+               GET_REP(dbptr, OptimisticTransactionDB)->BeginTransaction(GET_REP_REF(write_options, WriteOptions), GET_REP_REF(txn_options, OptimisticTransactionOptions)) :
+               GET_REP(dbptr, OptimisticTransactionDB)->BeginTransaction(GET_REP_REF(write_options, WriteOptions)));
+    }
+    return NewTransactionT(txn);
+}
+
+// Return the underlying Database that was opened. It's owned by the
+// OptimisticTransactionDB.
+DB_t OptimisticTransactionDBGetBaseDB(OptimisticTransactionDB_t* dbptr)
+{
+    DB_t db;
+    db.rep = (dbptr && GET_REP(dbptr, OptimisticTransactionDB)) ?
+        GET_REP(dbptr, OptimisticTransactionDB)->GetBaseDB() :
+        nullptr;
+    return db;
+}
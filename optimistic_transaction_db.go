@@ -0,0 +1,160 @@
+// Copyright (c) 2015, Dean ChaoJun Pan.  All rights reserved.
+// This source code is licensed under the BSD-style license found in the
+// LICENSE file in the root directory of this source tree.
+
+package rocksdb
+
+/*
+#include "optimistic_transaction_db.h"
+*/
+import "C"
+
+import (
+	"runtime"
+)
+
+// Wrap go OptimisticTransactionOptions
+type OptimisticTransactionOptions struct {
+	otopt C.OptimisticTransactionOptions_t
+	// Keep cmp from garbage collected
+	cmp *Comparator
+	// true if otopt is deleted
+	closed bool
+}
+
+// Release resources
+func (otopt *OptimisticTransactionOptions) finalize() {
+	if !otopt.closed {
+		otopt.closed = true
+		var cotopt *C.OptimisticTransactionOptions_t = &otopt.otopt
+		C.DeleteOptimisticTransactionOptionsT(cotopt, toCBool(false))
+	}
+}
+
+// Close the @OptimisticTransactionOptions
+func (otopt *OptimisticTransactionOptions) Close() {
+	runtime.SetFinalizer(otopt, nil)
+	otopt.finalize()
+}
+
+// C OptimisticTransactionOptions to go OptimisticTransactionOptions
+func (cotopt *C.OptimisticTransactionOptions_t) toOptimisticTransactionOptions() (otopt *OptimisticTransactionOptions) {
+	otopt = &OptimisticTransactionOptions{otopt: *cotopt}
+	runtime.SetFinalizer(otopt, finalize)
+	return
+}
+
+// Create a default OptimisticTransactionOptions
+func NewOptimisticTransactionOptions() *OptimisticTransactionOptions {
+	cotopt := C.NewOptimisticTransactionOptionsTDefault()
+	return cotopt.toOptimisticTransactionOptions()
+}
+
+// Setting set_snapshot=true is the same as calling
+// Transaction.SetSnapshot().
+// Default: false
+func (otopt *OptimisticTransactionOptions) SetSetSnapshot(val bool) {
+	var cotopt *C.OptimisticTransactionOptions_t = &otopt.otopt
+	C.OptimisticTransactionOptions_set_set_snapshot(cotopt, toCBool(val))
+}
+
+func (otopt *OptimisticTransactionOptions) SetSnapshot() bool {
+	var cotopt *C.OptimisticTransactionOptions_t = &otopt.otopt
+	return C.OptimisticTransactionOptions_get_set_snapshot(cotopt).toBool()
+}
+
+// Should be set if the DB has a non-default comparator, as the keys
+// written in a transaction are indexed with it.
+// Default: a comparator that uses lexicographic byte-wise ordering
+func (otopt *OptimisticTransactionOptions) SetComparator(cmp *Comparator) {
+	otopt.cmp = cmp
+	var cotopt *C.OptimisticTransactionOptions_t = &otopt.otopt
+	C.OptimisticTransactionOptions_set_cmp(cotopt, &cmp.cmp)
+}
+
+// A OptimisticTransactionDB is a DB that supports optimistic transactions.
+// Transactions track the keys they read and write without locking them,
+// and Commit fails with a Busy status if any of the keys has been written
+// outside of the transaction meanwhile.
+// All the DB methods can be called on an OptimisticTransactionDB.
+type OptimisticTransactionDB struct {
+	*DB
+	otdb C.OptimisticTransactionDB_t
+}
+
+// Open an OptimisticTransactionDB similar to Open(). The transactions
+// created from the OptimisticTransactionDB that are still open are
+// closed, i.e. rolled back, before the base db is released when the
+// OptimisticTransactionDB is closed.
+func OpenOptimisticTransactionDB(options *Options, name *string, cfds ...*ColumnFamilyDescriptor) (otdb *OptimisticTransactionDB, stat *Status, cfhs []*ColumnFamilyHandle) {
+	otdb = &OptimisticTransactionDB{DB: newDB(&options.DBOptions)}
+	rstr := newCStringFromString(name)
+	defer rstr.del()
+
+	var ccfds []C.ColumnFamilyDescriptor_t
+
+	if cfds != nil {
+		s := make([]interface{}, len(cfds))
+		for i, v := range cfds {
+			s[i] = v
+		}
+		ccfds = newCArrayFromColumnFamilyDescriptorArray(s...)
+	}
+
+	var (
+		cotdb *C.OptimisticTransactionDB_t = &otdb.otdb
+		opt *C.Options_t = &options.opt
+		cstr *C.String_t = &rstr.str
+		cfh *C.ColumnFamilyHandle_t
+	)
+
+	if ccfds != nil {
+		cstat := C.OptimisticTransactionDBOpenWithColumnFamilies(opt, cstr, &ccfds[0], C.int(len(ccfds)), &cfh, cotdb)
+		stat = cstat.toStatus()
+		if stat.Ok() {
+			cfhs = newColumnFamilyHandleArrayFromCArray(otdb.DB, cfh, uint(len(ccfds)))
+		} else {
+			C.DeleteColumnFamilyHandleTArray(cfh)
+		}
+	} else {
+		cstat := C.OptimisticTransactionDBOpen(opt, cstr, cotdb)
+		stat = cstat.toStatus()
+	}
+
+	if stat.Ok() {
+		// The base db is owned by the OptimisticTransactionDB
+		otdb.db = C.OptimisticTransactionDBGetBaseDB(cotdb)
+		cotdbcopy := otdb.otdb
+		otdb.release = func() {
+			C.DeleteOptimisticTransactionDBT(&cotdbcopy, toCBool(false))
+		}
+		runtime.SetFinalizer(otdb.DB, finalize)
+	}
+
+	return
+}
+
+// Starts a new Transaction. otopt is optional. Setting set_snapshot of
+// otopt to true has the same effect as calling Transaction.SetSnapshot().
+//
+// Caller should close the returned transaction after calling
+// Transaction.Commit() or Transaction.Rollback().
+func (otdb *OptimisticTransactionDB) BeginTransaction(wopt *WriteOptions, otopt ...*OptimisticTransactionOptions) (txn *Transaction) {
+	if otdb.closed {
+		return
+	}
+
+	var (
+		cotdb *C.OptimisticTransactionDB_t = &otdb.otdb
+		cwopt *C.WriteOptions_t = &wopt.wopt
+		cotopt *C.OptimisticTransactionOptions_t
+	)
+
+	if otopt != nil {
+		cotopt = &otopt[0].otopt
+	}
+
+	ctxn := C.OptimisticTransactionDBBeginTransaction(cotdb, cwopt, cotopt)
+	txn = ctxn.toTransaction(otdb.DB)
+	return
+}
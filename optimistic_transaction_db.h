@@ -0,0 +1,60 @@
+// Copyright (c) 2015, Dean ChaoJun Pan.  All rights reserved.
+// This source code is licensed under the BSD-style license found in the
+// LICENSE file in the root directory of this source tree.
+
+#ifndef GO_ROCKSDB_INCLUDE_OPTIMISTIC_TRANSACTION_DB_H_
+#define GO_ROCKSDB_INCLUDE_OPTIMISTIC_TRANSACTION_DB_H_
+
+#include "types.h"
+#include "db.h"
+#include "comparator.h"
+#include "transaction.h"
+
+#ifdef __cplusplus
+extern "C" {
+#endif
+
+DEFINE_C_WRAP_STRUCT(OptimisticTransactionOptions)
+DEFINE_C_WRAP_CONSTRUCTOR_DEC(OptimisticTransactionOptions)
+DEFINE_C_WRAP_CONSTRUCTOR_DEFAULT_DEC(OptimisticTransactionOptions)
+DEFINE_C_WRAP_DESTRUCTOR_DEC(OptimisticTransactionOptions)
+// Get/Set methods
+DEFINE_C_WRAP_GETTER_DEC(OptimisticTransactionOptions, set_snapshot, bool)
+DEFINE_C_WRAP_SETTER_DEC(OptimisticTransactionOptions, set_snapshot, bool)
+DEFINE_C_WRAP_SETTER_WRAP_DEC(OptimisticTransactionOptions, cmp, Comparator)
+
+// A OptimisticTransactionDB is a DB that supports optimistic transactions.
+DEFINE_C_WRAP_STRUCT(OptimisticTransactionDB)
+DEFINE_C_WRAP_CONSTRUCTOR_DEC(OptimisticTransactionDB)
+DEFINE_C_WRAP_DESTRUCTOR_DEC(OptimisticTransactionDB)
+
+// Open an OptimisticTransactionDB similar to DB::Open().
+Status_t OptimisticTransactionDBOpen(const Options_t* options,
+                                     const String_t* name,
+                                     OptimisticTransactionDB_t* dbptr);
+
+// Open an OptimisticTransactionDB with column families similar to DB::Open().
+Status_t OptimisticTransactionDBOpenWithColumnFamilies(const Options_t* options,
+                                                       const String_t* name,
+                                                       const ColumnFamilyDescriptor_t column_families[], const int size_col,
+                                                       ColumnFamilyHandle_t **handles,
+                                                       OptimisticTransactionDB_t* dbptr);
+
+// Starts a new Transaction.  Passing set_snapshot=true has the same effect
+// as calling SetSnapshot().
+//
+// Caller should delete the returned transaction after calling
+// Commit() or Rollback().
+Transaction_t OptimisticTransactionDBBeginTransaction(OptimisticTransactionDB_t* dbptr,
+                                                      const WriteOptions_t* write_options,
+                                                      const OptimisticTransactionOptions_t* txn_options);
+
+// Return the underlying Database that was opened. It's owned by the
+// OptimisticTransactionDB.
+DB_t OptimisticTransactionDBGetBaseDB(OptimisticTransactionDB_t* dbptr);
+
+#ifdef __cplusplus
+}  /* end extern "C" */
+#endif
+
+#endif  // GO_ROCKSDB_INCLUDE_OPTIMISTIC_TRANSACTION_DB_H_
@@ -6,6 +6,7 @@
 #include "mergeOperatorPrivate.h"
 #include "sliceTransformPrivate.h"
 #include "envPrivate.h"
+#include "table_propertiesPrivate.h"
 #include "options.h"
 
 DEFINE_C_WRAP_STATIC_CAST(Options, DBOptions)
@@ -92,6 +93,7 @@ void ColumnFamilyOptions_set_compression_options(
 // REQUIRES: The client must ensure that the comparator supplied
 // here has the same name and orders keys *exactly* the same as the
 // comparator provided to previous open calls on the same DB.
+DEFINE_C_WRAP_GETTER_WRAP(ColumnFamilyOptions, comparator, Comparator)
 DEFINE_C_WRAP_SETTER_PTR_WRAP(ColumnFamilyOptions, comparator, Comparator)
 
 // A single CompactionFilter instance to call into during compaction.
@@ -121,6 +123,19 @@ DEFINE_C_WRAP_SETTER_PTR_WRAP(ColumnFamilyOptions, compaction_filter, Compaction
 // Default: a factory that doesn't provide any object
 DEFINE_C_WRAP_SETTER_WRAP(ColumnFamilyOptions, compaction_filter_factory, PCompactionFilterFactory)
 
+// This option allows user to collect their own interested statistics of
+// the tables.
+// Default: empty vector -- no user-defined statistics collection will be
+// performed.
+void ColumnFamilyOptionsAddTablePropertiesCollectorFactory(ColumnFamilyOptions_t* opt,
+                                                           PTablePropertiesCollectorFactory_t* factory)
+{
+    if (opt && GET_REP(opt, ColumnFamilyOptions) && factory && GET_REP(factory, PTablePropertiesCollectorFactory))
+    {
+        GET_REP(opt, ColumnFamilyOptions)->table_properties_collector_factories.push_back(GET_REP_REF(factory, PTablePropertiesCollectorFactory));
+    }
+}
+
 DEFINE_C_WRAP_CONSTRUCTOR(DBOptions)
 DEFINE_C_WRAP_CONSTRUCTOR_RAW_ARGS(DBOptions, const Options&)
 DEFINE_C_WRAP_CONSTRUCTOR_DEFAULT(DBOptions)
@@ -155,6 +170,14 @@ DEFINE_C_WRAP_SETTER(DBOptions, paranoid_checks, bool)
 // compaction. For universal-style compaction, you can usually set it to -1.
 // Default: 5000
 DEFINE_C_WRAP_SETTER(DBOptions, max_open_files, int)
+// If non-null, then we should collect metrics about database operations
+// Statistics objects should not be shared between DB instances as
+// it does not use any locks to prevent concurrent updates.
+DEFINE_C_WRAP_SETTER_WRAP(DBOptions, statistics, PStatistics)
+// Use to control write rate of flush and compaction. Flush has higher
+// priority than compaction. Rate limiting is disabled if nullptr.
+// Default: nullptr
+DEFINE_C_WRAP_SETTER_WRAP(DBOptions, rate_limiter, PRateLimiter)
 
 
 DEFINE_C_WRAP_CONSTRUCTOR(Options)
@@ -31,6 +31,8 @@ const (
 
 type ColumnFamilyOptions struct {
 	cfopt C.ColumnFamilyOptions_t
+	// The comparator set by SetComparator
+	cmp *Comparator
 	// true if cfopt is deleted
 	closed bool
 }
@@ -211,9 +213,18 @@ func (cfopt *ColumnFamilyOptions) SetComparator(cmp *Comparator) {
 	if nil == cmp {
 		cmp = NewDefaultComparator()
 	}
+	cfopt.cmp = cmp
 	C.ColumnFamilyOptions_set_comparator(ccfopt, &cmp.cmp)
 }
 
+// Return the comparator set by SetComparator. The result remains the
+// property of the options.
+func (cfopt *ColumnFamilyOptions) Comparator() *Comparator {
+	var ccfopt *C.ColumnFamilyOptions_t = &cfopt.cfopt
+	ccmp := C.ColumnFamilyOptions_get_comparator(ccfopt)
+	return ccmp.toComparator(false)
+}
+
 // A single CompactionFilter instance to call into during compaction.
 // Allows an application to modify/delete a key-value during background
 // compaction.
@@ -254,8 +265,22 @@ func (cfopt *ColumnFamilyOptions) SetCompactionFilterFactory(cff *CompactionFilt
 	C.ColumnFamilyOptions_set_compaction_filter_factory(ccfopt, &cff.cff)
 }
 
+// This option allows user to collect their own interested statistics of
+// the tables. Every factory added is asked for a new collector for each
+// table built.
+// Default: empty -- no user-defined statistics collection will be
+// performed.
+func (cfopt *ColumnFamilyOptions) AddTablePropertiesCollectorFactory(tpcf *TablePropertiesCollectorFactory) {
+	var ccfopt *C.ColumnFamilyOptions_t = &cfopt.cfopt
+	if nil != tpcf {
+		C.ColumnFamilyOptionsAddTablePropertiesCollectorFactory(ccfopt, &tpcf.tpcf)
+	}
+}
+
 type DBOptions struct {
 	dbopt C.DBOptions_t
+	// Keep env from garbage collected
+	env *Env
 }
 
 func (dbopt *DBOptions) finalize() {
@@ -297,13 +322,19 @@ func (dbopt *DBOptions) SetErrorIfExists(val bool) {
 // e.g. to read/write files, schedule background work, etc.
 // Default: Env::Default()
 func (dbopt *DBOptions) Env() (env *Env) {
+	if nil != dbopt.env {
+		return dbopt.env
+	}
+
 	var cdbopt *C.DBOptions_t = &dbopt.dbopt
 	cenv := C.DBOptions_get_env(cdbopt)
 	// The wrapped Env is not deleted by garbage collector
 	return cenv.toEnv(false)
 }
 
+// The Env is kept alive by the options and by the DBs opened with them.
 func (dbopt *DBOptions) SetEnv(env *Env) {
+	dbopt.env = env
 	var cdbopt *C.DBOptions_t = &dbopt.dbopt
 	C.DBOptions_set_env(cdbopt, &env.env)
 }
@@ -336,6 +367,38 @@ func (dbopt *DBOptions) SetMaxOpenFiles(val int) {
 	C.DBOptions_set_max_open_files(cdbopt, C.int(val))
 }
 
+// If non-nil, then we should collect metrics about database operations
+// Statistics objects should not be shared between DB instances as
+// it does not use any locks to prevent concurrent updates.
+func (dbopt *DBOptions) SetStatistics(stats *Statistics) {
+	var (
+		cdbopt *C.DBOptions_t = &dbopt.dbopt
+		cstats *C.PStatistics_t
+	)
+
+	if nil != stats {
+		cstats = &stats.stats
+	}
+	C.DBOptions_set_statistics(cdbopt, cstats)
+}
+
+// Use to control write rate of flush and compaction. Flush has higher
+// priority than compaction. Rate limiting is disabled if nil.
+// The same rate limiter can be set to several DBOptions to limit the
+// total write rate of the databases.
+// Default: nil
+func (dbopt *DBOptions) SetRateLimiter(rl *RateLimiter) {
+	var (
+		cdbopt *C.DBOptions_t = &dbopt.dbopt
+		crl *C.PRateLimiter_t
+	)
+
+	if nil != rl {
+		crl = &rl.rl
+	}
+	C.DBOptions_set_rate_limiter(cdbopt, crl)
+}
+
 // Any internal progress/error information generated by the db will
 // be written to info_log if it is non-nullptr, or to a file stored
 // in the same directory as the DB contents if info_log is nullptr.
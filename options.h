@@ -19,6 +19,9 @@ using namespace rocksdb;
 #include "sliceTransform.h"
 #include "memtablerep.h"
 #include "env.h"
+#include "table_properties.h"
+#include "statistics.h"
+#include "rate_limiter.h"
 
 #ifdef __cplusplus
 extern "C" {
@@ -57,10 +60,13 @@ DEFINE_C_WRAP_SETTER_WRAP_DEC(ColumnFamilyOptions, merge_operator, PMergeOperato
 // Set method for prefix extractor.
 DEFINE_C_WRAP_SETTER_WRAP_DEC(ColumnFamilyOptions, prefix_extractor, PConstSliceTransform);
 // Get/Set methods for comparator
+DEFINE_C_WRAP_GETTER_WRAP_DEC(ColumnFamilyOptions, comparator, Comparator)
 DEFINE_C_WRAP_SETTER_WRAP_DEC(ColumnFamilyOptions, comparator, Comparator)
 // Get/Set methods for compaction filter
 DEFINE_C_WRAP_SETTER_WRAP_DEC(ColumnFamilyOptions, compaction_filter, CompactionFilter)
 DEFINE_C_WRAP_SETTER_WRAP_DEC(ColumnFamilyOptions, compaction_filter_factory, PCompactionFilterFactory)
+void ColumnFamilyOptionsAddTablePropertiesCollectorFactory(ColumnFamilyOptions_t* opt,
+                                                           PTablePropertiesCollectorFactory_t* factory);
 void ColumnFamilyOptions_set_compression_per_level(ColumnFamilyOptions_t* opt,
                                                    int* level_values,
                                                    size_t num_levels);
@@ -90,6 +96,10 @@ DEFINE_C_WRAP_SETTER_WRAP_DEC(DBOptions, info_log, PLogger)
 DEFINE_C_WRAP_SETTER_DEC(DBOptions, paranoid_checks, bool)
 // Get/Set methods for @max_open_files
 DEFINE_C_WRAP_SETTER_DEC(DBOptions, max_open_files, int)
+// Set method for @statistics
+DEFINE_C_WRAP_SETTER_WRAP_DEC(DBOptions, statistics, PStatistics)
+// Set method for @rate_limiter
+DEFINE_C_WRAP_SETTER_WRAP_DEC(DBOptions, rate_limiter, PRateLimiter)
 
 
 DEFINE_C_WRAP_CONSTRUCTOR_DEC(Options)
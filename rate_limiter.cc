@@ -0,0 +1,69 @@
+// Copyright (c) 2015, Dean ChaoJun Pan.  All rights reserved.
+// This source code is licensed under the BSD-style license found in the
+// LICENSE file in the root directory of this source tree.
+
+#include "rate_limiter.h"
+
+DEFINE_C_WRAP_CONSTRUCTOR(PRateLimiter)
+DEFINE_C_WRAP_DESTRUCTOR(PRateLimiter)
+
+// Create a RateLimiter object, which can be shared among RocksDB instances to
+// control write rate of flush and compaction.
+// @rate_bytes_per_sec: this is the only parameter you want to set most of the
+// time. It controls the total write rate of compaction and flush in bytes per
+// second. Currently, RocksDB does not enforce rate limit for anything other
+// than flush and compaction, e.g. write to WAL.
+// @refill_period_us: this controls how often tokens are refilled. For example,
+// when rate_bytes_per_sec is set to 10MB/s and refill_period_us is set to
+// 100ms, then 1MB is refilled every 100ms internally. Larger value can lead to
+// burstier writes while smaller value introduces more CPU overhead.
+// The default should work for most cases.
+// @fairness: RateLimiter accepts high-pri requests and low-pri requests.
+// A low-pri request is usually blocked in favor of hi-pri request. Currently,
+// RocksDB assigns low-pri to request from compaction and high-pri to request
+// from flush. Low-pri requests can get blocked if flush requests come in
+// continuously. This fairness parameter grants low-pri requests permission by
+// 1/fairness chance even though high-pri requests exist to avoid starvation.
+// You should be good by leaving it at default 10.
+PRateLimiter_t NewPRateLimiterTGeneric(int64_t rate_bytes_per_sec,
+                                       int64_t refill_period_us,
+                                       int32_t fairness)
+{
+    PRateLimiter_t wrap_t;
+    wrap_t.rep = new PRateLimiter(NewGenericRateLimiter(rate_bytes_per_sec, refill_period_us, fairness));
+    return wrap_t;
+}
+
+// This API allows user to dynamically change rate limiter's bytes per second.
+// REQUIRED: bytes_per_second > 0
+void PRateLimiterSetBytesPerSecond(const PRateLimiter_t* limiter, int64_t bytes_per_second)
+{
+    if (limiter && GET_REP(limiter, PRateLimiter) && GET_REP_REF(limiter, PRateLimiter))
+    {
+        GET_REP_REF(limiter, PRateLimiter)->SetBytesPerSecond(bytes_per_second);
+    }
+}
+
+// Max bytes can be granted in a single burst
+int64_t PRateLimiterGetSingleBurstBytes(const PRateLimiter_t* limiter)
+{
+    return ((limiter && GET_REP(limiter, PRateLimiter) && GET_REP_REF(limiter, PRateLimiter)) ?
+            GET_REP_REF(limiter, PRateLimiter)->GetSingleBurstBytes() :
+            0);
+}
+
+// Total bytes that go though rate limiter
+int64_t PRateLimiterGetTotalBytesThrough(const PRateLimiter_t* limiter)
+{
+    return ((limiter && GET_REP(limiter, PRateLimiter) && GET_REP_REF(limiter, PRateLimiter)) ?
+            GET_REP_REF(limiter, PRateLimiter)->GetTotalBytesThrough() :
+            0);
+}
+
+// Total # of requests that go though rate limiter
+int64_t PRateLimiterGetTotalRequests(const PRateLimiter_t* limiter)
+{
+    return ((limiter && GET_REP(limiter, PRateLimiter) && GET_REP_REF(limiter, PRateLimiter)) ?
+            GET_REP_REF(limiter, PRateLimiter)->GetTotalRequests() :
+            0);
+}
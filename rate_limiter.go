@@ -0,0 +1,140 @@
+// Copyright (c) 2015, Dean ChaoJun Pan.  All rights reserved.
+// This source code is licensed under the BSD-style license found in the
+// LICENSE file in the root directory of this source tree.
+
+package rocksdb
+
+/*
+#include "rate_limiter.h"
+*/
+import "C"
+
+import (
+	"runtime"
+)
+
+// Default refill period of NewGenericRateLimiter in microseconds
+const DefaultRateLimiterRefillPeriodUs = 100 * 1000
+
+// Default fairness of NewGenericRateLimiter
+const DefaultRateLimiterFairness = 10
+
+// Wrap go RateLimiter. A RateLimiter can be set to the DBOptions of
+// several DB instances to limit their total write rate of flush and
+// compaction.
+type RateLimiter struct {
+	rl C.PRateLimiter_t
+	// true if rl is deleted
+	closed bool
+}
+
+// Release resources
+func (rl *RateLimiter) finalize() {
+	if !rl.closed {
+		rl.closed = true
+		var crl *C.PRateLimiter_t = &rl.rl
+		C.DeletePRateLimiterT(crl, toCBool(false))
+	}
+}
+
+// Close the @RateLimiter. The DBOptions and databases the rate limiter
+// has been set to keep using it.
+func (rl *RateLimiter) Close() {
+	runtime.SetFinalizer(rl, nil)
+	rl.finalize()
+}
+
+// C RateLimiter to go RateLimiter
+func (crl *C.PRateLimiter_t) toRateLimiter() (rl *RateLimiter) {
+	rl = &RateLimiter{rl: *crl}
+	runtime.SetFinalizer(rl, finalize)
+	return
+}
+
+// Create a RateLimiter object, which can be shared among RocksDB instances to
+// control write rate of flush and compaction.
+// @bytesPerSec: this is the only parameter you want to set most of the
+// time. It controls the total write rate of compaction and flush in bytes per
+// second. Currently, RocksDB does not enforce rate limit for anything other
+// than flush and compaction, e.g. write to WAL.
+// @refillPeriodUs: this controls how often tokens are refilled. For example,
+// when bytesPerSec is set to 10MB/s and refillPeriodUs is set to
+// 100ms, then 1MB is refilled every 100ms internally. Larger value can lead to
+// burstier writes while smaller value introduces more CPU overhead.
+// DefaultRateLimiterRefillPeriodUs should work for most cases.
+// @fairness: RateLimiter accepts high-pri requests and low-pri requests.
+// A low-pri request is usually blocked in favor of hi-pri request. Currently,
+// RocksDB assigns low-pri to request from compaction and high-pri to request
+// from flush. Low-pri requests can get blocked if flush requests come in
+// continuously. This fairness parameter grants low-pri requests permission by
+// 1/fairness chance even though high-pri requests exist to avoid starvation.
+// You should be good by leaving it at DefaultRateLimiterFairness.
+// Returns nil and InvalidArgument if any of the parameters is not positive.
+func NewGenericRateLimiter(bytesPerSec int64, refillPeriodUs int64, fairness int32) (rl *RateLimiter, stat *Status) {
+	if bytesPerSec <= 0 {
+		stat = NewInvalidArgumentStatus("bytes per second must be positive")
+		return
+	}
+	if refillPeriodUs <= 0 {
+		stat = NewInvalidArgumentStatus("refill period must be positive")
+		return
+	}
+	if fairness <= 0 {
+		stat = NewInvalidArgumentStatus("fairness must be positive")
+		return
+	}
+
+	crl := C.NewPRateLimiterTGeneric(C.int64_t(bytesPerSec), C.int64_t(refillPeriodUs), C.int32_t(fairness))
+	rl = crl.toRateLimiter()
+	stat = NewOKStatus()
+	return
+}
+
+// This API allows user to dynamically change rate limiter's bytes per second.
+// Returns InvalidArgument and keeps the current rate if bytesPerSec is
+// not positive.
+func (rl *RateLimiter) SetBytesPerSecond(bytesPerSec int64) (stat *Status) {
+	if rl.closed {
+		stat = NewInvalidArgumentStatus("RateLimiter is closed")
+		return
+	}
+	if bytesPerSec <= 0 {
+		stat = NewInvalidArgumentStatus("bytes per second must be positive")
+		return
+	}
+
+	var crl *C.PRateLimiter_t = &rl.rl
+	C.PRateLimiterSetBytesPerSecond(crl, C.int64_t(bytesPerSec))
+	stat = NewOKStatus()
+	return
+}
+
+// Max bytes can be granted in a single burst
+func (rl *RateLimiter) GetSingleBurstBytes() int64 {
+	if rl.closed {
+		return 0
+	}
+
+	var crl *C.PRateLimiter_t = &rl.rl
+	return int64(C.PRateLimiterGetSingleBurstBytes(crl))
+}
+
+// Total bytes that go though rate limiter
+func (rl *RateLimiter) GetTotalBytesThrough() int64 {
+	if rl.closed {
+		return 0
+	}
+
+	var crl *C.PRateLimiter_t = &rl.rl
+	return int64(C.PRateLimiterGetTotalBytesThrough(crl))
+}
+
+// Total # of requests that go though rate limiter
+func (rl *RateLimiter) GetTotalRequests() int64 {
+	if rl.closed {
+		return 0
+	}
+
+	var crl *C.PRateLimiter_t = &rl.rl
+	return int64(C.PRateLimiterGetTotalRequests(crl))
+}
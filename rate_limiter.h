@@ -0,0 +1,50 @@
+// Copyright (c) 2015, Dean ChaoJun Pan.  All rights reserved.
+// This source code is licensed under the BSD-style license found in the
+// LICENSE file in the root directory of this source tree.
+
+#ifndef GO_ROCKSDB_INCLUDE_RATE_LIMITER_H_
+#define GO_ROCKSDB_INCLUDE_RATE_LIMITER_H_
+
+#ifdef __cplusplus
+#include <rocksdb/rate_limiter.h>
+using namespace rocksdb;
+#endif
+
+#include "types.h"
+
+#ifdef __cplusplus
+typedef std::shared_ptr<RateLimiter> PRateLimiter;
+#endif
+
+#ifdef __cplusplus
+extern "C" {
+#endif
+
+DEFINE_C_WRAP_STRUCT(PRateLimiter)
+DEFINE_C_WRAP_CONSTRUCTOR_DEC(PRateLimiter)
+DEFINE_C_WRAP_DESTRUCTOR_DEC(PRateLimiter)
+
+// Create a RateLimiter object, which can be shared among RocksDB instances to
+// control write rate of flush and compaction.
+PRateLimiter_t NewPRateLimiterTGeneric(int64_t rate_bytes_per_sec,
+                                       int64_t refill_period_us,
+                                       int32_t fairness);
+
+// This API allows user to dynamically change rate limiter's bytes per second.
+// REQUIRED: bytes_per_second > 0
+void PRateLimiterSetBytesPerSecond(const PRateLimiter_t* limiter, int64_t bytes_per_second);
+
+// Max bytes can be granted in a single burst
+int64_t PRateLimiterGetSingleBurstBytes(const PRateLimiter_t* limiter);
+
+// Total bytes that go though rate limiter
+int64_t PRateLimiterGetTotalBytesThrough(const PRateLimiter_t* limiter);
+
+// Total # of requests that go though rate limiter
+int64_t PRateLimiterGetTotalRequests(const PRateLimiter_t* limiter);
+
+#ifdef __cplusplus
+}  /* end extern "C" */
+#endif
+
+#endif  // GO_ROCKSDB_INCLUDE_RATE_LIMITER_H_
@@ -0,0 +1,131 @@
+// Copyright (c) 2015, Dean ChaoJun Pan.  All rights reserved.
+// This source code is licensed under the BSD-style license found in the
+// LICENSE file in the root directory of this source tree.
+
+#include <rocksdb/sst_file_writer.h>
+#include <rocksdb/immutable_options.h>
+
+using namespace rocksdb;
+
+#include "sst_file_writer.h"
+
+// ExternalSstFileInfo include information about sst files created
+// using SstFileWriter
+DEFINE_C_WRAP_CONSTRUCTOR(ExternalSstFileInfo)
+DEFINE_C_WRAP_CONSTRUCTOR_DEFAULT(ExternalSstFileInfo)
+DEFINE_C_WRAP_DESTRUCTOR(ExternalSstFileInfo)
+DEFINE_C_WRAP_GETTER(ExternalSstFileInfo, sequence_number, SequenceNumber)
+DEFINE_C_WRAP_GETTER(ExternalSstFileInfo, file_size, uint64_t)
+DEFINE_C_WRAP_GETTER(ExternalSstFileInfo, num_entries, uint64_t)
+DEFINE_C_WRAP_GETTER(ExternalSstFileInfo, version, int32_t)
+
+// external sst file path
+String_t ExternalSstFileInfo_get_file_path(ExternalSstFileInfo_t* ptr)
+{
+    String ret;
+    if (ptr && GET_REP(ptr, ExternalSstFileInfo))
+    {
+        ret = GET_REP(ptr, ExternalSstFileInfo)->file_path;
+    }
+    return NewStringTCopy(&ret);
+}
+
+// smallest user key in file
+String_t ExternalSstFileInfo_get_smallest_key(ExternalSstFileInfo_t* ptr)
+{
+    String ret;
+    if (ptr && GET_REP(ptr, ExternalSstFileInfo))
+    {
+        ret = GET_REP(ptr, ExternalSstFileInfo)->smallest_key;
+    }
+    return NewStringTCopy(&ret);
+}
+
+// largest user key in file
+String_t ExternalSstFileInfo_get_largest_key(ExternalSstFileInfo_t* ptr)
+{
+    String ret;
+    if (ptr && GET_REP(ptr, ExternalSstFileInfo))
+    {
+        ret = GET_REP(ptr, ExternalSstFileInfo)->largest_key;
+    }
+    return NewStringTCopy(&ret);
+}
+
+// SstFileWriter only keeps references to the ImmutableCFOptions it is
+// created with, which in turn point into the Options. Keep both alive
+// for the lifetime of the writer.
+struct SstFileWriterOptions
+{
+    SstFileWriterOptions(const Options& opt) : options(opt), ioptions(options) {}
+
+    Options options;
+    ImmutableCFOptions ioptions;
+};
+
+class SstFileWriterGo : private SstFileWriterOptions, public SstFileWriter {
+public:
+    SstFileWriterGo(const Options& opt)
+            : SstFileWriterOptions(opt),
+              SstFileWriter(EnvOptions(options), ioptions, options.comparator) {}
+};
+
+DEFINE_C_WRAP_CONSTRUCTOR(SstFileWriter)
+
+// SstFileWriter has no virtual destructor. Delete the SstFileWriterGo
+// created by NewSstFileWriter.
+DEFINE_C_WRAP_DESTRUCTOR_DEC_R(SstFileWriter)
+{
+    if (ptr)
+    {
+        SstFileWriterGo* rep = static_cast<SstFileWriterGo*>(GET_REP(ptr, SstFileWriter));
+        if (rep)
+            delete rep;
+        if (self)
+            delete ptr;
+    }
+}
+
+// Create a SstFileWriter that builds the table with the comparator,
+// table factory and the other settings of options.
+SstFileWriter_t NewSstFileWriter(const Options_t* options)
+{
+    assert(options != NULL);
+    assert(GET_REP(options, Options) != NULL);
+    SstFileWriter* writer = new SstFileWriterGo(GET_REP_REF(options, Options));
+    return NewSstFileWriterT(writer);
+}
+
+// Prepare SstFileWriter to write into file located at "file_path".
+Status_t SstFileWriterOpen(SstFileWriter_t* writer, const String_t* file_path)
+{
+    assert(GET_REP(file_path, String) != NULL);
+    Status ret = ((writer && GET_REP(writer, SstFileWriter)) ?
+                  GET_REP(writer, SstFileWriter)->Open(GET_REP_REF(file_path, String)) :
+                  invalid_status);
+    return NewStatusTCopy(&ret);
+}
+
+// Add key, value to currently opened file
+// REQUIRES: key is after any previously added key according to comparator.
+Status_t SstFileWriterAdd(SstFileWriter_t* writer, const Slice_t* user_key, const Slice_t* value)
+{
+    assert(GET_REP(user_key, Slice) != NULL);
+    assert(GET_REP(value, Slice) != NULL);
+    Status ret = ((writer && GET_REP(writer, SstFileWriter)) ?
+                  GET_REP(writer, SstFileWriter)->Add(GET_REP_REF(user_key, Slice), GET_REP_REF(value, Slice)) :
+                  invalid_status);
+    return NewStatusTCopy(&ret);
+}
+
+// Finalize writing to sst file and close file.
+//
+// An optional ExternalSstFileInfo pointer can be passed to the function
+// which will be populated with information about the created sst file
+Status_t SstFileWriterFinish(SstFileWriter_t* writer, ExternalSstFileInfo_t* file_info)
+{
+    Status ret = ((writer && GET_REP(writer, SstFileWriter)) ?
+                  GET_REP(writer, SstFileWriter)->Finish(file_info ? GET_REP(file_info, ExternalSstFileInfo) : nullptr) :
+                  invalid_status);
+    return NewStatusTCopy(&ret);
+}
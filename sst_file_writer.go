@@ -0,0 +1,141 @@
+// Copyright (c) 2015, Dean ChaoJun Pan.  All rights reserved.
+// This source code is licensed under the BSD-style license found in the
+// LICENSE file in the root directory of this source tree.
+
+package rocksdb
+
+/*
+#include "sst_file_writer.h"
+*/
+import "C"
+
+import (
+	"runtime"
+)
+
+// ExternalSstFileInfo include information about sst files created
+// using SstFileWriter
+type ExternalSstFileInfo struct {
+	// external sst file path
+	FilePath string
+	// smallest user key in file
+	SmallestKey []byte
+	// largest user key in file
+	LargestKey []byte
+	// sequence number of all keys in file
+	SequenceNumber SequenceNumber
+	// file size in bytes
+	FileSize uint64
+	// number of entries in file
+	NumEntries uint64
+	// file version
+	Version int32
+}
+
+// C ExternalSstFileInfo to go ExternalSstFileInfo.
+// Delete the underlying @cesfi if del is true.
+func (cesfi *C.ExternalSstFileInfo_t) toExternalSstFileInfo(del bool) (esfi *ExternalSstFileInfo) {
+	if del {
+		defer C.DeleteExternalSstFileInfoT(cesfi, toCBool(false))
+	}
+
+	var (
+		cpath C.String_t = C.ExternalSstFileInfo_get_file_path(cesfi)
+		csmallestkey C.String_t = C.ExternalSstFileInfo_get_smallest_key(cesfi)
+		clargestkey C.String_t = C.ExternalSstFileInfo_get_largest_key(cesfi)
+	)
+
+	esfi = &ExternalSstFileInfo{}
+	esfi.FilePath = cpath.cToString()
+	esfi.SmallestKey = csmallestkey.cToBytes(true)
+	esfi.LargestKey = clargestkey.cToBytes(true)
+	esfi.SequenceNumber = SequenceNumber(C.ExternalSstFileInfo_get_sequence_number(cesfi))
+	esfi.FileSize = uint64(C.ExternalSstFileInfo_get_file_size(cesfi))
+	esfi.NumEntries = uint64(C.ExternalSstFileInfo_get_num_entries(cesfi))
+	esfi.Version = int32(C.ExternalSstFileInfo_get_version(cesfi))
+	return
+}
+
+// SstFileWriter is used to create sst files that can be added to database later
+// All keys in files generated by SstFileWriter will have sequence number = 0
+type SstFileWriter struct {
+	sfw C.SstFileWriter_t
+	// true if sfw is deleted
+	closed bool
+}
+
+// Release resources
+func (sfw *SstFileWriter) finalize() {
+	if !sfw.closed {
+		sfw.closed = true
+		var csfw *C.SstFileWriter_t = &sfw.sfw
+		C.DeleteSstFileWriterT(csfw, toCBool(false))
+	}
+}
+
+// Close the @SstFileWriter
+func (sfw *SstFileWriter) Close() {
+	runtime.SetFinalizer(sfw, nil)
+	sfw.finalize()
+}
+
+// C SstFileWriter to go SstFileWriter
+func (csfw *C.SstFileWriter_t) toSstFileWriter() (sfw *SstFileWriter) {
+	sfw = &SstFileWriter{sfw: *csfw}
+	runtime.SetFinalizer(sfw, finalize)
+	return
+}
+
+// Create a SstFileWriter. The table is built with the comparator,
+// table factory and the other settings of options, which should match
+// the ones of the column family the file will be added to.
+func NewSstFileWriter(options *Options) *SstFileWriter {
+	csfw := C.NewSstFileWriter(&options.opt)
+	return csfw.toSstFileWriter()
+}
+
+// Prepare SstFileWriter to write into file located at filePath.
+func (sfw *SstFileWriter) Open(filePath *string) (stat *Status) {
+	if sfw.closed {
+		stat = NewDBClosedStatus()
+		return
+	}
+
+	cpath := newCStringFromString(filePath)
+	defer cpath.del()
+	cstat := C.SstFileWriterOpen(&sfw.sfw, &cpath.str)
+	stat = cstat.toStatus()
+	return
+}
+
+// Add key, value to currently opened file
+// REQUIRES: key is after any previously added key according to comparator.
+func (sfw *SstFileWriter) Add(key, val []byte) (stat *Status) {
+	if sfw.closed {
+		stat = NewDBClosedStatus()
+		return
+	}
+
+	ckey := newSliceFromBytes(key)
+	defer ckey.del()
+	cval := newSliceFromBytes(val)
+	defer cval.del()
+	cstat := C.SstFileWriterAdd(&sfw.sfw, &ckey.slc, &cval.slc)
+	stat = cstat.toStatus()
+	return
+}
+
+// Finalize writing to sst file and close file. Returns the information
+// about the created sst file.
+func (sfw *SstFileWriter) Finish() (esfi *ExternalSstFileInfo, stat *Status) {
+	if sfw.closed {
+		stat = NewDBClosedStatus()
+		return
+	}
+
+	cesfi := C.NewExternalSstFileInfoTDefault()
+	cstat := C.SstFileWriterFinish(&sfw.sfw, &cesfi)
+	stat = cstat.toStatus()
+	esfi = cesfi.toExternalSstFileInfo(true)
+	return
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 2015, Dean ChaoJun Pan.  All rights reserved.
+// This source code is licensed under the BSD-style license found in the
+// LICENSE file in the root directory of this source tree.
+
+#ifndef GO_ROCKSDB_INCLUDE_SST_FILE_WRITER_H_
+#define GO_ROCKSDB_INCLUDE_SST_FILE_WRITER_H_
+
+#include "types.h"
+#include "slice.h"
+#include "cstring.h"
+#include "status.h"
+#include "options.h"
+
+#ifdef __cplusplus
+extern "C" {
+#endif
+
+// ExternalSstFileInfo include information about sst files created
+// using SstFileWriter
+DEFINE_C_WRAP_STRUCT(ExternalSstFileInfo)
+DEFINE_C_WRAP_CONSTRUCTOR_DEC(ExternalSstFileInfo)
+DEFINE_C_WRAP_CONSTRUCTOR_DEFAULT_DEC(ExternalSstFileInfo)
+DEFINE_C_WRAP_DESTRUCTOR_DEC(ExternalSstFileInfo)
+// Get methods
+DEFINE_C_WRAP_GETTER_DEC(ExternalSstFileInfo, sequence_number, SequenceNumber)
+DEFINE_C_WRAP_GETTER_DEC(ExternalSstFileInfo, file_size, uint64_t)
+DEFINE_C_WRAP_GETTER_DEC(ExternalSstFileInfo, num_entries, uint64_t)
+DEFINE_C_WRAP_GETTER_DEC(ExternalSstFileInfo, version, int32_t)
+String_t ExternalSstFileInfo_get_file_path(ExternalSstFileInfo_t* ptr);
+String_t ExternalSstFileInfo_get_smallest_key(ExternalSstFileInfo_t* ptr);
+String_t ExternalSstFileInfo_get_largest_key(ExternalSstFileInfo_t* ptr);
+
+// SstFileWriter is used to create sst files that can be added to database later
+// All keys in files generated by SstFileWriter will have sequence number = 0
+DEFINE_C_WRAP_STRUCT(SstFileWriter)
+DEFINE_C_WRAP_CONSTRUCTOR_DEC(SstFileWriter)
+DEFINE_C_WRAP_DESTRUCTOR_DEC(SstFileWriter)
+
+// Create a SstFileWriter that builds the table with the comparator,
+// table factory and the other settings of options.
+SstFileWriter_t NewSstFileWriter(const Options_t* options);
+
+// Prepare SstFileWriter to write into file located at "file_path".
+Status_t SstFileWriterOpen(SstFileWriter_t* writer, const String_t* file_path);
+
+// Add key, value to currently opened file
+// REQUIRES: key is after any previously added key according to comparator.
+Status_t SstFileWriterAdd(SstFileWriter_t* writer, const Slice_t* user_key, const Slice_t* value);
+
+// Finalize writing to sst file and close file.
+//
+// An optional ExternalSstFileInfo pointer can be passed to the function
+// which will be populated with information about the created sst file
+Status_t SstFileWriterFinish(SstFileWriter_t* writer, ExternalSstFileInfo_t* file_info);
+
+#ifdef __cplusplus
+}  /* end extern "C" */
+#endif
+
+#endif  // GO_ROCKSDB_INCLUDE_SST_FILE_WRITER_H_
@@ -0,0 +1,186 @@
+// Copyright (c) 2015, Dean ChaoJun Pan.  All rights reserved.
+// This source code is licensed under the BSD-style license found in the
+// LICENSE file in the root directory of this source tree.
+
+#include "statistics.h"
+
+DEFINE_C_WRAP_CONSTRUCTOR(HistogramData)
+DEFINE_C_WRAP_CONSTRUCTOR_DEFAULT(HistogramData)
+DEFINE_C_WRAP_DESTRUCTOR(HistogramData)
+DEFINE_C_WRAP_GETTER(HistogramData, median, double)
+DEFINE_C_WRAP_GETTER(HistogramData, percentile95, double)
+DEFINE_C_WRAP_GETTER(HistogramData, percentile99, double)
+DEFINE_C_WRAP_GETTER(HistogramData, average, double)
+DEFINE_C_WRAP_GETTER(HistogramData, standard_deviation, double)
+
+DEFINE_C_WRAP_CONSTRUCTOR(PStatistics)
+DEFINE_C_WRAP_DESTRUCTOR(PStatistics)
+
+// The rocksdb tickers in the order of the go Ticker constants
+static const Tickers go_tickers[] = {
+    BLOCK_CACHE_MISS,
+    BLOCK_CACHE_HIT,
+    BLOCK_CACHE_ADD,
+    BLOCK_CACHE_INDEX_MISS,
+    BLOCK_CACHE_INDEX_HIT,
+    BLOCK_CACHE_FILTER_MISS,
+    BLOCK_CACHE_FILTER_HIT,
+    BLOCK_CACHE_DATA_MISS,
+    BLOCK_CACHE_DATA_HIT,
+    BLOOM_FILTER_USEFUL,
+    MEMTABLE_HIT,
+    MEMTABLE_MISS,
+    GET_HIT_L0,
+    GET_HIT_L1,
+    GET_HIT_L2_AND_UP,
+    COMPACTION_KEY_DROP_NEWER_ENTRY,
+    COMPACTION_KEY_DROP_OBSOLETE,
+    COMPACTION_KEY_DROP_USER,
+    NUMBER_KEYS_WRITTEN,
+    NUMBER_KEYS_READ,
+    NUMBER_KEYS_UPDATED,
+    BYTES_WRITTEN,
+    BYTES_READ,
+    NUMBER_DB_SEEK,
+    NUMBER_DB_NEXT,
+    NUMBER_DB_PREV,
+    NUMBER_DB_SEEK_FOUND,
+    NUMBER_DB_NEXT_FOUND,
+    NUMBER_DB_PREV_FOUND,
+    ITER_BYTES_READ,
+    NO_FILE_CLOSES,
+    NO_FILE_OPENS,
+    NO_FILE_ERRORS,
+    STALL_MICROS,
+    DB_MUTEX_WAIT_MICROS,
+    NUMBER_MULTIGET_CALLS,
+    NUMBER_MULTIGET_KEYS_READ,
+    NUMBER_MULTIGET_BYTES_READ,
+    NUMBER_MERGE_FAILURES,
+    BLOOM_FILTER_PREFIX_CHECKED,
+    BLOOM_FILTER_PREFIX_USEFUL,
+    NUMBER_OF_RESEEKS_IN_ITERATION,
+    GET_UPDATES_SINCE_CALLS,
+    BLOCK_CACHE_COMPRESSED_MISS,
+    BLOCK_CACHE_COMPRESSED_HIT,
+    WAL_FILE_SYNCED,
+    WAL_FILE_BYTES,
+    WRITE_DONE_BY_SELF,
+    WRITE_DONE_BY_OTHER,
+    WRITE_WITH_WAL,
+    COMPACT_READ_BYTES,
+    COMPACT_WRITE_BYTES,
+    FLUSH_WRITE_BYTES,
+    ROW_CACHE_HIT,
+    ROW_CACHE_MISS,
+};
+
+// The rocksdb histograms in the order of the go Histogram constants
+static const Histograms go_histograms[] = {
+    DB_GET,
+    DB_WRITE,
+    COMPACTION_TIME,
+    TABLE_SYNC_MICROS,
+    COMPACTION_OUTFILE_SYNC_MICROS,
+    WAL_FILE_SYNC_MICROS,
+    MANIFEST_FILE_SYNC_MICROS,
+    TABLE_OPEN_IO_MICROS,
+    DB_MULTIGET,
+    READ_BLOCK_COMPACTION_MICROS,
+    READ_BLOCK_GET_MICROS,
+    WRITE_RAW_BLOCK_MICROS,
+    NUM_FILES_IN_SINGLE_COMPACTION,
+    DB_SEEK,
+    WRITE_STALL,
+};
+
+static const uint32_t go_tickers_size = sizeof(go_tickers) / sizeof(go_tickers[0]);
+static const uint32_t go_histograms_size = sizeof(go_histograms) / sizeof(go_histograms[0]);
+
+// Create a concrete DBStatistics object
+PStatistics_t NewPStatisticsTCreateDBStatistics()
+{
+    PStatistics_t wrap_t;
+    wrap_t.rep = new PStatistics(CreateDBStatistics());
+    return wrap_t;
+}
+
+uint32_t StatisticsTickerEnumMax()
+{
+    return go_tickers_size;
+}
+
+uint32_t StatisticsHistogramEnumMax()
+{
+    return go_histograms_size;
+}
+
+String_t StatisticsTickerName(uint32_t ticker_type)
+{
+    String name;
+    if (ticker_type < go_tickers_size)
+    {
+        for (auto& t : TickersNameMap)
+        {
+            if (t.first == go_tickers[ticker_type])
+            {
+                name = t.second;
+                break;
+            }
+        }
+    }
+    return NewStringTCopy(&name);
+}
+
+String_t StatisticsHistogramName(uint32_t histogram_type)
+{
+    String name;
+    if (histogram_type < go_histograms_size)
+    {
+        for (auto& h : HistogramsNameMap)
+        {
+            if (h.first == go_histograms[histogram_type])
+            {
+                name = h.second;
+                break;
+            }
+        }
+    }
+    return NewStringTCopy(&name);
+}
+
+uint64_t PStatisticsGetTickerCount(const PStatistics_t* stats, uint32_t ticker_type)
+{
+    return ((stats && GET_REP(stats, PStatistics) && GET_REP_REF(stats, PStatistics) && ticker_type < go_tickers_size) ?
+            GET_REP_REF(stats, PStatistics)->getTickerCount(go_tickers[ticker_type]) :
+            0);
+}
+
+void PStatisticsSetTickerCount(const PStatistics_t* stats, uint32_t ticker_type, uint64_t count)
+{
+    if (stats && GET_REP(stats, PStatistics) && GET_REP_REF(stats, PStatistics) && ticker_type < go_tickers_size)
+    {
+        GET_REP_REF(stats, PStatistics)->setTickerCount(go_tickers[ticker_type], count);
+    }
+}
+
+void PStatisticsHistogramData(const PStatistics_t* stats, uint32_t histogram_type, HistogramData_t* data)
+{
+    assert(data != NULL);
+    assert(GET_REP(data, HistogramData) != NULL);
+    if (stats && GET_REP(stats, PStatistics) && GET_REP_REF(stats, PStatistics) && histogram_type < go_histograms_size)
+    {
+        GET_REP_REF(stats, PStatistics)->histogramData(go_histograms[histogram_type], GET_REP(data, HistogramData));
+    }
+}
+
+// String representation of the statistic object.
+String_t PStatisticsToString(const PStatistics_t* stats)
+{
+    String str;
+    if (stats && GET_REP(stats, PStatistics) && GET_REP_REF(stats, PStatistics))
+    {
+        str = GET_REP_REF(stats, PStatistics)->ToString();
+    }
+    return NewStringTCopy(&str);
+}
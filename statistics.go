@@ -0,0 +1,300 @@
+// Copyright (c) 2015, Dean ChaoJun Pan.  All rights reserved.
+// This source code is licensed under the BSD-style license found in the
+// LICENSE file in the root directory of this source tree.
+
+package rocksdb
+
+/*
+#include "statistics.h"
+*/
+import "C"
+
+import (
+	"runtime"
+)
+
+// Keep adding ticker's here.
+//  1. Any ticker should be added before tickerEnumMax.
+//  2. Add the rocksdb ticker to go_tickers in statistics.cc in the
+//     same order.
+type Ticker uint32
+
+const (
+	// total block cache misses
+	BlockCacheMiss Ticker = iota
+	// total block cache hit
+	BlockCacheHit
+	// # of blocks added to block cache.
+	BlockCacheAdd
+	// # of times cache miss when accessing index block from block cache.
+	BlockCacheIndexMiss
+	// # of times cache hit when accessing index block from block cache.
+	BlockCacheIndexHit
+	// # of times cache miss when accessing filter block from block cache.
+	BlockCacheFilterMiss
+	// # of times cache hit when accessing filter block from block cache.
+	BlockCacheFilterHit
+	// # of times cache miss when accessing data block from block cache.
+	BlockCacheDataMiss
+	// # of times cache hit when accessing data block from block cache.
+	BlockCacheDataHit
+	// # of times bloom filter has avoided file reads.
+	BloomFilterUseful
+	// # of memtable hits.
+	MemtableHit
+	// # of memtable misses.
+	MemtableMiss
+	// # of Get() queries served by L0
+	GetHitL0
+	// # of Get() queries served by L1
+	GetHitL1
+	// # of Get() queries served by L2 and up
+	GetHitL2AndUp
+	// key was written with a newer value.
+	CompactionKeyDropNewerEntry
+	// The key is obsolete.
+	CompactionKeyDropObsolete
+	// user compaction function has dropped the key.
+	CompactionKeyDropUser
+	// Number of keys written to the database via the Put and Write call's
+	NumberKeysWritten
+	// Number of Keys read,
+	NumberKeysRead
+	// Number keys updated, if inplace update is enabled
+	NumberKeysUpdated
+	// The number of uncompressed bytes issued by DB::Put(), DB::Delete(),
+	// DB::Merge(), and DB::Write().
+	BytesWritten
+	// The number of uncompressed bytes read from DB::Get().  It could be
+	// either from memtables, cache, or table files.
+	BytesRead
+	// The number of calls to seek
+	NumberDBSeek
+	// The number of calls to next
+	NumberDBNext
+	// The number of calls to prev
+	NumberDBPrev
+	// The number of calls to seek that returned data
+	NumberDBSeekFound
+	// The number of calls to next that returned data
+	NumberDBNextFound
+	// The number of calls to prev that returned data
+	NumberDBPrevFound
+	// The number of uncompressed bytes read from an iterator.
+	IterBytesRead
+	NoFileCloses
+	NoFileOpens
+	NoFileErrors
+	// Writer has to wait for compaction or flush to finish.
+	StallMicros
+	// The wait time for db mutex.
+	DBMutexWaitMicros
+	// number of MultiGet calls
+	NumberMultigetCalls
+	// number of MultiGet keys read
+	NumberMultigetKeysRead
+	// number of MultiGet bytes read
+	NumberMultigetBytesRead
+	// Number of merge failures
+	NumberMergeFailures
+	// number of times bloom was checked before creating iterator on a
+	// file
+	BloomFilterPrefixChecked
+	// number of times the check was useful in avoiding
+	// iterator creation (and thus likely IOPs).
+	BloomFilterPrefixUseful
+	// Number of times we had to reseek inside an iteration to skip
+	// over large number of keys with same userkey.
+	NumberOfReseeksInIteration
+	// Record the number of calls to GetUpdatesSince. Useful to keep track of
+	// transaction log iterator refreshes
+	GetUpdatesSinceCalls
+	// miss in the compressed block cache
+	BlockCacheCompressedMiss
+	// hit in the compressed block cache
+	BlockCacheCompressedHit
+	// Number of times WAL sync is done
+	WalFileSynced
+	// Number of bytes written to WAL
+	WalFileBytes
+	// Writes can be processed by requesting thread or by the thread at the
+	// head of the writers queue.
+	WriteDoneBySelf
+	WriteDoneByOther
+	// Number of Write calls that request WAL
+	WriteWithWal
+	// Bytes read during compaction
+	CompactReadBytes
+	// Bytes written during compaction
+	CompactWriteBytes
+	// Bytes written during flush
+	FlushWriteBytes
+	// # of row cache hits
+	RowCacheHit
+	// # of row cache misses
+	RowCacheMiss
+	tickerEnumMax
+)
+
+// Name of the ticker, e.g. "rocksdb.block.cache.hit"
+func (t Ticker) String() string {
+	cname := C.StatisticsTickerName(C.uint32_t(t))
+	return cname.cToString()
+}
+
+// All the tickers
+func Tickers() (tks []Ticker) {
+	tks = make([]Ticker, tickerEnumMax)
+	for i := range tks {
+		tks[i] = Ticker(i)
+	}
+	return
+}
+
+// Keep adding histogram's here.
+// Any histogram should have value less than histogramEnumMax and be
+// added to go_histograms in statistics.cc in the same order.
+type Histogram uint32
+
+const (
+	DBGet Histogram = iota
+	DBWrite
+	CompactionTime
+	TableSyncMicros
+	CompactionOutfileSyncMicros
+	WalFileSyncMicros
+	ManifestFileSyncMicros
+	// TIME SPENT IN IO DURING TABLE OPEN
+	TableOpenIOMicros
+	DBMultiget
+	ReadBlockCompactionMicros
+	ReadBlockGetMicros
+	WriteRawBlockMicros
+	NumFilesInSingleCompaction
+	DBSeek
+	WriteStall
+	histogramEnumMax
+)
+
+// Name of the histogram, e.g. "rocksdb.db.get.micros"
+func (h Histogram) String() string {
+	cname := C.StatisticsHistogramName(C.uint32_t(h))
+	return cname.cToString()
+}
+
+// All the histograms
+func Histograms() (hgs []Histogram) {
+	hgs = make([]Histogram, histogramEnumMax)
+	for i := range hgs {
+		hgs[i] = Histogram(i)
+	}
+	return
+}
+
+// The percentiles of a histogram
+type HistogramData struct {
+	Median float64
+	Percentile95 float64
+	Percentile99 float64
+	Average float64
+	StandardDeviation float64
+}
+
+// C HistogramData to go HistogramData
+func (chd *C.HistogramData_t) toHistogramData() (hd *HistogramData) {
+	hd = &HistogramData{}
+	hd.Median = float64(C.HistogramData_get_median(chd))
+	hd.Percentile95 = float64(C.HistogramData_get_percentile95(chd))
+	hd.Percentile99 = float64(C.HistogramData_get_percentile99(chd))
+	hd.Average = float64(C.HistogramData_get_average(chd))
+	hd.StandardDeviation = float64(C.HistogramData_get_standard_deviation(chd))
+	return
+}
+
+// Analyze the performance of a db. Set it to DBOptions with
+// DBOptions.SetStatistics to collect the metrics.
+type Statistics struct {
+	stats C.PStatistics_t
+	// true if stats is deleted
+	closed bool
+}
+
+// Release resources
+func (stats *Statistics) finalize() {
+	if !stats.closed {
+		stats.closed = true
+		var cstats *C.PStatistics_t = &stats.stats
+		C.DeletePStatisticsT(cstats, toCBool(false))
+	}
+}
+
+// Close the @Statistics. The DBOptions and databases the statistics
+// has been set to keep using it.
+func (stats *Statistics) Close() {
+	runtime.SetFinalizer(stats, nil)
+	stats.finalize()
+}
+
+// C Statistics to go Statistics
+func (cstats *C.PStatistics_t) toStatistics() (stats *Statistics) {
+	stats = &Statistics{stats: *cstats}
+	runtime.SetFinalizer(stats, finalize)
+	return
+}
+
+// Create a concrete DBStatistics object
+func NewStatistics() *Statistics {
+	cstats := C.NewPStatisticsTCreateDBStatistics()
+	return cstats.toStatistics()
+}
+
+// Return the count of the ticker
+func (stats *Statistics) GetTickerCount(t Ticker) uint64 {
+	if stats.closed {
+		return 0
+	}
+
+	var cstats *C.PStatistics_t = &stats.stats
+	return uint64(C.PStatisticsGetTickerCount(cstats, C.uint32_t(t)))
+}
+
+// Return the percentiles of the histogram
+func (stats *Statistics) HistogramData(h Histogram) (hd *HistogramData) {
+	if stats.closed {
+		return
+	}
+
+	var (
+		cstats *C.PStatistics_t = &stats.stats
+		chd C.HistogramData_t = C.NewHistogramDataTDefault()
+	)
+	defer C.DeleteHistogramDataT(&chd, toCBool(false))
+
+	C.PStatisticsHistogramData(cstats, C.uint32_t(h), &chd)
+	hd = chd.toHistogramData()
+	return
+}
+
+// Reset all the tickers to 0.
+// The histograms can not be reset by this version of rocksdb.
+func (stats *Statistics) Reset() {
+	if stats.closed {
+		return
+	}
+
+	var cstats *C.PStatistics_t = &stats.stats
+	for t := Ticker(0); t < tickerEnumMax; t++ {
+		C.PStatisticsSetTickerCount(cstats, C.uint32_t(t), 0)
+	}
+}
+
+// String representation of the statistic object.
+func (stats *Statistics) String() string {
+	if stats.closed {
+		return ""
+	}
+
+	var cstats *C.PStatistics_t = &stats.stats
+	cstr := C.PStatisticsToString(cstats)
+	return cstr.cToString()
+}
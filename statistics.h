@@ -0,0 +1,59 @@
+// Copyright (c) 2015, Dean ChaoJun Pan.  All rights reserved.
+// This source code is licensed under the BSD-style license found in the
+// LICENSE file in the root directory of this source tree.
+
+#ifndef GO_ROCKSDB_INCLUDE_STATISTICS_H_
+#define GO_ROCKSDB_INCLUDE_STATISTICS_H_
+
+#ifdef __cplusplus
+#include <rocksdb/statistics.h>
+using namespace rocksdb;
+#endif
+
+#include "types.h"
+#include "cstring.h"
+
+#ifdef __cplusplus
+typedef std::shared_ptr<Statistics> PStatistics;
+#endif
+
+#ifdef __cplusplus
+extern "C" {
+#endif
+
+DEFINE_C_WRAP_STRUCT(HistogramData)
+DEFINE_C_WRAP_CONSTRUCTOR_DEC(HistogramData)
+DEFINE_C_WRAP_CONSTRUCTOR_DEFAULT_DEC(HistogramData)
+DEFINE_C_WRAP_DESTRUCTOR_DEC(HistogramData)
+// Get methods
+DEFINE_C_WRAP_GETTER_DEC(HistogramData, median, double)
+DEFINE_C_WRAP_GETTER_DEC(HistogramData, percentile95, double)
+DEFINE_C_WRAP_GETTER_DEC(HistogramData, percentile99, double)
+DEFINE_C_WRAP_GETTER_DEC(HistogramData, average, double)
+DEFINE_C_WRAP_GETTER_DEC(HistogramData, standard_deviation, double)
+
+DEFINE_C_WRAP_STRUCT(PStatistics)
+DEFINE_C_WRAP_CONSTRUCTOR_DEC(PStatistics)
+DEFINE_C_WRAP_DESTRUCTOR_DEC(PStatistics)
+
+// Create a concrete DBStatistics object
+PStatistics_t NewPStatisticsTCreateDBStatistics();
+
+// The tickers and histograms below are indexed by the go Ticker and
+// Histogram constants, which are mapped to the rocksdb enums.
+uint32_t StatisticsTickerEnumMax();
+uint32_t StatisticsHistogramEnumMax();
+String_t StatisticsTickerName(uint32_t ticker_type);
+String_t StatisticsHistogramName(uint32_t histogram_type);
+
+uint64_t PStatisticsGetTickerCount(const PStatistics_t* stats, uint32_t ticker_type);
+void PStatisticsSetTickerCount(const PStatistics_t* stats, uint32_t ticker_type, uint64_t count);
+void PStatisticsHistogramData(const PStatistics_t* stats, uint32_t histogram_type, HistogramData_t* data);
+// String representation of the statistic object.
+String_t PStatisticsToString(const PStatistics_t* stats);
+
+#ifdef __cplusplus
+}  /* end extern "C" */
+#endif
+
+#endif  // GO_ROCKSDB_INCLUDE_STATISTICS_H_
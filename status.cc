@@ -26,6 +26,49 @@ Status_t StatusDBClosedStatus()
     return NewStatusTCopy(const_cast<Status*>(&db_closed_status));
 }
 
+// Returns a success status.
+Status_t StatusOKStatus()
+{
+    Status ret;
+    return NewStatusTCopy(&ret);
+}
+
+// Returns a corruption status with the message msg.
+Status_t StatusCorruptionStatus(const String_t* msg)
+{
+    Status ret = Status::Corruption((msg && GET_REP(msg, String)) ?
+                                    GET_REP_REF(msg, String) :
+                                    std::string());
+    return NewStatusTCopy(&ret);
+}
+
+// Returns an IO error status with the message msg.
+Status_t StatusIOErrorStatus(const String_t* msg)
+{
+    Status ret = Status::IOError((msg && GET_REP(msg, String)) ?
+                                 GET_REP_REF(msg, String) :
+                                 std::string());
+    return NewStatusTCopy(&ret);
+}
+
+// Returns a not found status with the message msg.
+Status_t StatusNotFoundStatus(const String_t* msg)
+{
+    Status ret = Status::NotFound((msg && GET_REP(msg, String)) ?
+                                  GET_REP_REF(msg, String) :
+                                  std::string());
+    return NewStatusTCopy(&ret);
+}
+
+// Returns an invalid argument status with the message msg.
+Status_t StatusInvalidArgumentStatus(const String_t* msg)
+{
+    Status ret = Status::InvalidArgument((msg && GET_REP(msg, String)) ?
+                                         GET_REP_REF(msg, String) :
+                                         std::string());
+    return NewStatusTCopy(&ret);
+}
+
 // Returns true iff the status indicates success.
 bool StatusOk(Status_t *stat)
 {
@@ -113,6 +156,26 @@ bool StatusIsBusy(Status_t *stat)
             false);
 }
 
+// Returns true iff the status indicates a lock could not be acquired
+// within the lock timeout.
+bool StatusIsLockTimeout(Status_t *stat)
+{
+    return ((stat && GET_REP(stat, Status)) ?
+            (GET_REP(stat, Status)->IsTimedOut() &&
+             GET_REP(stat, Status)->subcode() == Status::kLockTimeout) :
+            false);
+}
+
+// Returns true iff the status indicates a lock could not be acquired
+// because the max number of locks is reached.
+bool StatusIsLockLimit(Status_t *stat)
+{
+    return ((stat && GET_REP(stat, Status)) ?
+            (GET_REP(stat, Status)->IsBusy() &&
+             GET_REP(stat, Status)->subcode() == Status::kLockLimit) :
+            false);
+}
+
 // Return a string representation of this status suitable for printing.
 // Returns the string "OK" for success.
 String_t StatusToString(Status_t *stat)
@@ -39,6 +39,55 @@ func NewDBClosedStatus() *Status {
 	return csta.toStatus()
 }
 
+// Create a new success go status
+func NewOKStatus() *Status {
+	csta := C.StatusOKStatus()
+	return csta.toStatus()
+}
+
+// Create a new corruption go status with the message msg
+func NewCorruptionStatus(msg string) *Status {
+	cmsg := newCStringFromString(&msg)
+	defer cmsg.del()
+	csta := C.StatusCorruptionStatus(&cmsg.str)
+	return csta.toStatus()
+}
+
+// Create a new IO error go status with the message msg
+func NewIOErrorStatus(msg string) *Status {
+	cmsg := newCStringFromString(&msg)
+	defer cmsg.del()
+	csta := C.StatusIOErrorStatus(&cmsg.str)
+	return csta.toStatus()
+}
+
+// Create a new not found go status with the message msg
+func NewNotFoundStatus(msg string) *Status {
+	cmsg := newCStringFromString(&msg)
+	defer cmsg.del()
+	csta := C.StatusNotFoundStatus(&cmsg.str)
+	return csta.toStatus()
+}
+
+// Create a new invalid argument go status with the message msg
+func NewInvalidArgumentStatus(msg string) *Status {
+	cmsg := newCStringFromString(&msg)
+	defer cmsg.del()
+	csta := C.StatusInvalidArgumentStatus(&cmsg.str)
+	return csta.toStatus()
+}
+
+// Go Status to a new C Status which the caller is responsible
+// to delete. A nil stat is a success status.
+func (stat *Status) newCStatus() (csta C.Status_t) {
+	if nil == stat {
+		csta = C.StatusOKStatus()
+	} else {
+		csta = C.NewStatusTCopy(stat.sta.rep)
+	}
+	return
+}
+
 // C Status array to Go Status array
 func newStatusArrayFromCArray(csta *C.Status_t, sz uint) (stas []*Status) {
 	defer C.DeleteStatusTArray(csta)
@@ -115,6 +164,21 @@ func (stat *Status) IsBusy() bool {
 	return C.StatusIsBusy(cstat).toBool()
 }
 
+// Returns true iff the status indicates a lock could not be acquired
+// within the lock timeout. IsTimedOut is also true for such a status.
+func (stat *Status) IsLockTimeout() bool {
+	var cstat *C.Status_t = &stat.sta
+	return C.StatusIsLockTimeout(cstat).toBool()
+}
+
+// Returns true iff the status indicates a lock could not be acquired
+// because the max number of locks is reached. IsBusy is also true for
+// such a status.
+func (stat *Status) IsLockLimit() bool {
+	var cstat *C.Status_t = &stat.sta
+	return C.StatusIsLockLimit(cstat).toBool()
+}
+
 // Return a string representation of this status suitable for printing.
 // Returns the string "OK" for success.
 func (stat *Status) String() string {
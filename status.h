@@ -40,8 +40,15 @@ bool StatusIsShutdownInProgress(Status_t *stat);
 bool StatusIsTimedOut(Status_t *stat);
 bool StatusIsAborted(Status_t *stat);
 bool StatusIsBusy(Status_t *stat);
+bool StatusIsLockTimeout(Status_t *stat);
+bool StatusIsLockLimit(Status_t *stat);
 String_t StatusToString(Status_t *stat);
 Status_t StatusDBClosedStatus();
+Status_t StatusOKStatus();
+Status_t StatusCorruptionStatus(const String_t* msg);
+Status_t StatusIOErrorStatus(const String_t* msg);
+Status_t StatusNotFoundStatus(const String_t* msg);
+Status_t StatusInvalidArgumentStatus(const String_t* msg);
 
 #ifdef __cplusplus
 }  /* end extern "C" */
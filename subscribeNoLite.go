@@ -0,0 +1,219 @@
+// Copyright (c) 2015, Dean ChaoJun Pan.  All rights reserved.
+// This source code is licensed under the BSD-style license found in the
+// LICENSE file in the root directory of this source tree.
+//
+// Change data capture on top of GetUpdatesSince. Subscribe tails the
+// write ahead log and delivers every Put, Merge and Delete written to
+// the database as a ChangeEvent.
+
+// +build !lite
+
+package rocksdb
+
+import (
+	"context"
+	"time"
+)
+
+// The type of a ChangeEvent
+type ChangeOp int
+
+const (
+	ChangeOpPut ChangeOp = iota
+	ChangeOpMerge
+	// A Delete or a SingleDelete
+	ChangeOpDelete
+)
+
+func (op ChangeOp) String() string {
+	switch op {
+	case ChangeOpPut:
+		return "Put"
+	case ChangeOpMerge:
+		return "Merge"
+	case ChangeOpDelete:
+		return "Delete"
+	}
+	return "Unknown"
+}
+
+// A single update read from the write ahead log
+type ChangeEvent struct {
+	// ID of the column family the update was written to.
+	// 0 is the default column family.
+	ColumnFamilyID uint32
+	Op ChangeOp
+	Key []byte
+	// nil for ChangeOpDelete
+	Value []byte
+	Sequence SequenceNumber
+}
+
+// Interval between two polls of the write ahead log when Subscribe
+// has caught up with the database.
+const defaultSubscribePollInterval = 100 * time.Millisecond
+
+// Subscribe returns a channel delivering every update written to the
+// database with a sequence number no less than fromSeq, in sequence order.
+// The iterator is reopened at the last delivered sequence whenever it
+// becomes invalid, e.g. after the WAL has been rotated, so no update is
+// delivered twice.
+// Both channels are closed when ctx is cancelled or the database is closed.
+// Closing the database stops the subscription and waits for it to stop,
+// so the database can be closed while the events are being read.
+// If the log can no longer be read, e.g. the WAL files holding the next
+// sequence have already been purged, the failing status is sent on errs
+// before the channels are closed.
+// Must set WAL_ttl_seconds or WAL_size_limit_MB to large values to
+// subscribe to a busy database, else the WAL files may get purged before
+// they are read.
+func (db *DB) Subscribe(ctx context.Context, fromSeq SequenceNumber, pollInterval ...time.Duration) (events <-chan ChangeEvent, errs <-chan *Status) {
+	var (
+		evch = make(chan ChangeEvent)
+		errch = make(chan *Status, 1)
+		interval = defaultSubscribePollInterval
+	)
+
+	if len(pollInterval) > 0 && pollInterval[0] > 0 {
+		interval = pollInterval[0]
+	}
+
+	// Register the subscription, so that closing the db stops it
+	// before the db is released.
+	ctx, cancel := context.WithCancel(ctx)
+	sub := &subscription{cancel: cancel, done: make(chan struct{})}
+	if !db.addToSubmap(sub) {
+		cancel()
+		errch <- NewDBClosedStatus()
+		close(errch)
+		close(evch)
+		return evch, errch
+	}
+
+	go db.subscribe(ctx, sub, fromSeq, interval, evch, errch)
+	return evch, errch
+}
+
+// The polling loop of Subscribe
+func (db *DB) subscribe(ctx context.Context, sub *subscription, nextsqn SequenceNumber, interval time.Duration, evch chan<- ChangeEvent, errch chan<- *Status) {
+	defer func() {
+		sub.cancel()
+		db.removeFromSubmap(sub)
+		close(errch)
+		close(evch)
+		close(sub.done)
+	}()
+
+	for {
+		// Only reopen the iterator when there is something new to read
+		if nextsqn <= db.GetLatestSequenceNumber() {
+			stat := db.subscribeOnce(ctx, &nextsqn, evch)
+			if nil != stat {
+				if !stat.IsShutdownInProgress() {
+					errch <- stat
+				}
+				return
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// Deliver the updates from nextsqn until the iterator becomes invalid.
+// nextsqn is advanced past every delivered update. Returns nil if the
+// caller should poll again.
+func (db *DB) subscribeOnce(ctx context.Context, nextsqn *SequenceNumber, evch chan<- ChangeEvent) (stat *Status) {
+	tranit, stat := db.GetUpdatesSince(*nextsqn)
+	if !stat.Ok() {
+		return
+	}
+	defer tranit.Close()
+
+	startsqn := *nextsqn
+	for ; tranit.Valid(); tranit.Next() {
+		res := tranit.GetBatch()
+		handler := &changeEventHandler{sqn: res.Sequence}
+		stat = res.Batch.Iterate(handler)
+		res.Batch.Close()
+		if !stat.Ok() {
+			return
+		}
+		evs := handler.evs
+
+		for _, ev := range evs {
+			// The batch holding nextsqn may start before it
+			if ev.Sequence < *nextsqn {
+				continue
+			}
+
+			select {
+			case <-ctx.Done():
+				stat = nil
+				return
+			case evch <- ev:
+			}
+			*nextsqn = ev.Sequence + 1
+		}
+	}
+
+	// Invalid with an ok status means caught up. An error after some
+	// progress, e.g. the WAL file rotated away under the iterator, is
+	// handled by reopening at nextsqn. An error without any progress
+	// would repeat forever, so report it.
+	if stat = tranit.Status(); stat.Ok() || *nextsqn != startsqn {
+		stat = nil
+	}
+	return
+}
+
+// IWriteBatchHandler collecting the updates of a batch read from the
+// write ahead log as ChangeEvents. Every update consumes one sequence
+// number starting from the one of the batch.
+type changeEventHandler struct {
+	// The sequence number of the next update
+	sqn SequenceNumber
+	evs []ChangeEvent
+}
+
+// Collect an update
+func (h *changeEventHandler) add(cfid uint32, op ChangeOp, key, val []byte) {
+	// The value of a Put or Merge is never nil, even if empty
+	if op != ChangeOpDelete && nil == val {
+		val = []byte{}
+	}
+	h.evs = append(h.evs, ChangeEvent{ColumnFamilyID: cfid, Op: op, Key: key, Value: val, Sequence: h.sqn})
+	h.sqn++
+}
+
+func (h *changeEventHandler) Put(key, val []byte) {
+	h.add(0, ChangeOpPut, key, val)
+}
+
+func (h *changeEventHandler) PutCF(cfid uint32, key, val []byte) {
+	h.add(cfid, ChangeOpPut, key, val)
+}
+
+func (h *changeEventHandler) Merge(key, val []byte) {
+	h.add(0, ChangeOpMerge, key, val)
+}
+
+func (h *changeEventHandler) MergeCF(cfid uint32, key, val []byte) {
+	h.add(cfid, ChangeOpMerge, key, val)
+}
+
+func (h *changeEventHandler) Delete(key []byte) {
+	h.add(0, ChangeOpDelete, key, nil)
+}
+
+func (h *changeEventHandler) DeleteCF(cfid uint32, key []byte) {
+	h.add(cfid, ChangeOpDelete, key, nil)
+}
+
+// Log data consumes no sequence number
+func (h *changeEventHandler) LogData(blob []byte) {
+}
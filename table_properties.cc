@@ -0,0 +1,276 @@
+// Copyright (c) 2015, Dean ChaoJun Pan.  All rights reserved.
+// This source code is licensed under the BSD-style license found in the
+// LICENSE file in the root directory of this source tree.
+//
+// TableProperties contains a bunch of read-only properties of its associated
+// table.
+
+#include <rocksdb/db.h>
+#include "table_propertiesPrivate.h"
+#include "table_properties.h"
+
+extern "C" {
+#include "_cgo_export.h"
+}
+
+DEFINE_C_WRAP_CONSTRUCTOR(TableProperties)
+DEFINE_C_WRAP_DESTRUCTOR(TableProperties)
+DEFINE_C_WRAP_DESTRUCTOR_ARRAY(TableProperties)
+
+// the total size of all data blocks.
+DEFINE_C_WRAP_GETTER(TableProperties, data_size, uint64_t)
+// the size of index block.
+DEFINE_C_WRAP_GETTER(TableProperties, index_size, uint64_t)
+// the size of filter block.
+DEFINE_C_WRAP_GETTER(TableProperties, filter_size, uint64_t)
+// total raw key size
+DEFINE_C_WRAP_GETTER(TableProperties, raw_key_size, uint64_t)
+// total raw value size
+DEFINE_C_WRAP_GETTER(TableProperties, raw_value_size, uint64_t)
+// the number of blocks in this table
+DEFINE_C_WRAP_GETTER(TableProperties, num_data_blocks, uint64_t)
+// the number of entries in this table
+DEFINE_C_WRAP_GETTER(TableProperties, num_entries, uint64_t)
+// format version, reserved for backward compatibility
+DEFINE_C_WRAP_GETTER(TableProperties, format_version, uint64_t)
+// If 0, key is variable length. Otherwise number of bytes for each key.
+DEFINE_C_WRAP_GETTER(TableProperties, fixed_key_len, uint64_t)
+// ID of column family for this SST file, corresponding to the CF identified
+// by column_family_name.
+DEFINE_C_WRAP_GETTER(TableProperties, column_family_id, uint32_t)
+
+// Name of the column family with which this SST file is associated.
+// If column family is unknown, `column_family_name` will be an empty string.
+String_t TableProperties_get_column_family_name(TableProperties_t* ptr)
+{
+    String ret;
+    if (ptr && GET_REP(ptr, TableProperties))
+    {
+        ret = GET_REP(ptr, TableProperties)->column_family_name;
+    }
+    return NewStringTCopy(&ret);
+}
+
+// The name of the filter policy used in this table.
+// If no filter policy is used, `filter_policy_name` will be an empty string.
+String_t TableProperties_get_filter_policy_name(TableProperties_t* ptr)
+{
+    String ret;
+    if (ptr && GET_REP(ptr, TableProperties))
+    {
+        ret = GET_REP(ptr, TableProperties)->filter_policy_name;
+    }
+    return NewStringTCopy(&ret);
+}
+
+// The name of the comparator used in this table.
+String_t TableProperties_get_comparator_name(TableProperties_t* ptr)
+{
+    String ret;
+    if (ptr && GET_REP(ptr, TableProperties))
+    {
+        ret = GET_REP(ptr, TableProperties)->comparator_name;
+    }
+    return NewStringTCopy(&ret);
+}
+
+// user collected properties
+void TablePropertiesGetUserCollectedProperties(TableProperties_t* ptr, String_t** keys, String_t** vals, int* n)
+{
+    *n = 0;
+    if (ptr && GET_REP(ptr, TableProperties))
+    {
+        const UserCollectedProperties& props = GET_REP(ptr, TableProperties)->user_collected_properties;
+        *keys = new String_t[props.size()];
+        *vals = new String_t[props.size()];
+        for (auto& prop : props)
+        {
+            (*keys)[*n].rep = new String(prop.first);
+            (*vals)[*n].rep = new String(prop.second);
+            (*n)++;
+        }
+    }
+}
+
+DEFINE_C_WRAP_CONSTRUCTOR(TablePropertiesCollection)
+DEFINE_C_WRAP_CONSTRUCTOR_DEFAULT(TablePropertiesCollection)
+DEFINE_C_WRAP_DESTRUCTOR(TablePropertiesCollection)
+
+// Get the file names and the properties of all tables in the collection.
+// The returned properties remain the property of the collection
+// and must not be deleted.
+void TablePropertiesCollectionGetAll(TablePropertiesCollection_t* ptr, String_t** names, TableProperties_t** props, int* n)
+{
+    *n = 0;
+    if (ptr && GET_REP(ptr, TablePropertiesCollection))
+    {
+        const TablePropertiesCollection& tpc = GET_REP_REF(ptr, TablePropertiesCollection);
+        *names = new String_t[tpc.size()];
+        *props = new TableProperties_t[tpc.size()];
+        for (auto& tp : tpc)
+        {
+            (*names)[*n].rep = new String(tp.first);
+            (*props)[*n].rep = const_cast<TableProperties*>(tp.second.get());
+            (*n)++;
+        }
+    }
+}
+
+DEFINE_C_WRAP_CONSTRUCTOR(UserCollectedProperties)
+DEFINE_C_WRAP_DESTRUCTOR(UserCollectedProperties)
+
+// Insert the property key=val
+void UserCollectedPropertiesInsert(UserCollectedProperties_t* ptr, const String_t* key, const String_t* val)
+{
+    if (ptr && GET_REP(ptr, UserCollectedProperties) && key && val)
+    {
+        GET_REP_REF(ptr, UserCollectedProperties)[GET_REP_REF(key, String)] = GET_REP_REF(val, String);
+    }
+}
+
+DEFINE_C_WRAP_CONSTRUCTOR(PTablePropertiesCollectorFactory)
+DEFINE_C_WRAP_DESTRUCTOR(PTablePropertiesCollectorFactory)
+
+// C++ wrap class for go ITablePropertiesCollector
+// TablePropertiesCollector provides the mechanism for users to collect
+// their own properties that they are interested in.
+class TablePropertiesCollectorGo : public TablePropertiesCollector {
+public:
+    TablePropertiesCollectorGo(void* go_tpc)
+        : m_go_tpc(go_tpc)
+        , m_name(nullptr)
+    {
+        if (go_tpc)
+        {
+            m_name = ITablePropertiesCollectorName(go_tpc);
+        }
+    }
+
+    // Destructor
+    ~TablePropertiesCollectorGo()
+    {
+        if (m_go_tpc)
+        {
+            InterfacesRemoveReference(m_go_tpc);
+        }
+
+        if (m_name)
+        {
+            free(m_name);
+        }
+    }
+
+    // AddUserKey() will be called when a new key/value pair is inserted into
+    // the table.
+    // @params key    the user key that is inserted into the table.
+    // @params value  the value that is inserted into the table.
+    virtual Status AddUserKey(const Slice& key, const Slice& value,
+                              EntryType type, SequenceNumber seq,
+                              uint64_t file_size) override
+    {
+        if (m_go_tpc)
+        {
+            Slice_t key_slc{const_cast<Slice *>(&key)};
+            Slice_t value_slc{const_cast<Slice *>(&value)};
+            ITablePropertiesCollectorAdd(m_go_tpc, &key_slc, &value_slc);
+        }
+        return Status::OK();
+    }
+
+    // Finish() will be called when a table has already been built and is ready
+    // for writing the properties block.
+    // @params properties  User will add their collected statistics to
+    // `properties`.
+    virtual Status Finish(UserCollectedProperties* properties) override
+    {
+        if (m_go_tpc)
+        {
+            UserCollectedProperties_t props{&m_props};
+            ITablePropertiesCollectorFinish(m_go_tpc, &props);
+            properties->insert(m_props.begin(), m_props.end());
+        }
+        return Status::OK();
+    }
+
+    // Return the human-readable properties, where the key is property name and
+    // the value is the human-readable form of value.
+    virtual UserCollectedProperties GetReadableProperties() const override
+    {
+        return m_props;
+    }
+
+    // The name of the properties collector can be used for debugging purpose.
+    virtual const char* Name() const override
+    {
+        return m_name ? m_name : "";
+    }
+
+private:
+    // Wrapped go ITablePropertiesCollector
+    void* m_go_tpc;
+
+    // The name of the TablePropertiesCollector
+    char* m_name;
+
+    // The properties returned by the go ITablePropertiesCollector
+    UserCollectedProperties m_props;
+};
+
+// Constructs TablePropertiesCollector. Internals create a new
+// TablePropertiesCollector for each new table
+class TablePropertiesCollectorFactoryGo : public TablePropertiesCollectorFactory {
+public:
+    TablePropertiesCollectorFactoryGo(void* go_tpcf)
+        : m_go_tpcf(go_tpcf)
+        , m_name(nullptr)
+    {
+        if (go_tpcf)
+        {
+            m_name = ITablePropertiesCollectorFactoryName(go_tpcf);
+        }
+    }
+
+    // Destructor
+    ~TablePropertiesCollectorFactoryGo()
+    {
+        if (m_go_tpcf)
+        {
+            InterfacesRemoveReference(m_go_tpcf);
+        }
+
+        if (m_name)
+        {
+            free(m_name);
+        }
+    }
+
+    // has to be thread-safe
+    virtual TablePropertiesCollector* CreateTablePropertiesCollector(
+        TablePropertiesCollectorFactory::Context context) override
+    {
+        return new TablePropertiesCollectorGo(m_go_tpcf ?
+                                              ITablePropertiesCollectorFactoryCreateTablePropertiesCollector(m_go_tpcf, context.column_family_id) :
+                                              nullptr);
+    }
+
+    // The name of the properties collector can be used for debugging purpose.
+    virtual const char* Name() const override
+    {
+        return m_name;
+    }
+
+private:
+    // Wrapped go ITablePropertiesCollectorFactory
+    void* m_go_tpcf;
+
+    // The name of the TablePropertiesCollectorFactory
+    char* m_name;
+};
+
+// Return a TablePropertiesCollectorFactory from a go ITablePropertiesCollectorFactory
+PTablePropertiesCollectorFactory_t NewPTablePropertiesCollectorFactory(void* go_tpcf)
+{
+    PTablePropertiesCollectorFactory_t wrap_t;
+    wrap_t.rep = new PTablePropertiesCollectorFactory(go_tpcf ? new TablePropertiesCollectorFactoryGo(go_tpcf) : NULL);
+    return wrap_t;
+}
@@ -0,0 +1,222 @@
+// Copyright (c) 2015, Dean ChaoJun Pan.  All rights reserved.
+// This source code is licensed under the BSD-style license found in the
+// LICENSE file in the root directory of this source tree.
+
+package rocksdb
+
+/*
+#include "table_properties.h"
+*/
+import "C"
+
+import (
+	"runtime"
+	"unsafe"
+)
+
+// TableProperties contains a bunch of read-only properties of its associated
+// table.
+type TableProperties struct {
+	// the total size of all data blocks.
+	DataSize uint64
+	// the size of index block.
+	IndexSize uint64
+	// the size of filter block.
+	FilterSize uint64
+	// total raw key size
+	RawKeySize uint64
+	// total raw value size
+	RawValueSize uint64
+	// the number of blocks in this table
+	NumDataBlocks uint64
+	// the number of entries in this table
+	NumEntries uint64
+	// format version, reserved for backward compatibility
+	FormatVersion uint64
+	// If 0, key is variable length. Otherwise number of bytes for each key.
+	FixedKeyLen uint64
+
+	// ID of column family for this SST file, corresponding to the CF identified
+	// by ColumnFamilyName.
+	ColumnFamilyID uint32
+	// Name of the column family with which this SST file is associated.
+	// If column family is unknown, ColumnFamilyName will be an empty string.
+	ColumnFamilyName string
+	// The name of the filter policy used in this table.
+	// If no filter policy is used, FilterPolicyName will be an empty string.
+	FilterPolicyName string
+	// The name of the comparator used in this table.
+	ComparatorName string
+
+	// user collected properties
+	UserCollectedProperties map[string]string
+}
+
+// C TableProperties to go TableProperties
+func (ctp *C.TableProperties_t) toTableProperties() (tp *TableProperties) {
+	var (
+		ccfname C.String_t = C.TableProperties_get_column_family_name(ctp)
+		cfpname C.String_t = C.TableProperties_get_filter_policy_name(ctp)
+		ccmpname C.String_t = C.TableProperties_get_comparator_name(ctp)
+		ckeys *C.String_t
+		cvals *C.String_t
+		n C.int
+	)
+
+	tp = &TableProperties{}
+	tp.DataSize = uint64(C.TableProperties_get_data_size(ctp))
+	tp.IndexSize = uint64(C.TableProperties_get_index_size(ctp))
+	tp.FilterSize = uint64(C.TableProperties_get_filter_size(ctp))
+	tp.RawKeySize = uint64(C.TableProperties_get_raw_key_size(ctp))
+	tp.RawValueSize = uint64(C.TableProperties_get_raw_value_size(ctp))
+	tp.NumDataBlocks = uint64(C.TableProperties_get_num_data_blocks(ctp))
+	tp.NumEntries = uint64(C.TableProperties_get_num_entries(ctp))
+	tp.FormatVersion = uint64(C.TableProperties_get_format_version(ctp))
+	tp.FixedKeyLen = uint64(C.TableProperties_get_fixed_key_len(ctp))
+	tp.ColumnFamilyID = uint32(C.TableProperties_get_column_family_id(ctp))
+	tp.ColumnFamilyName = ccfname.cToString()
+	tp.FilterPolicyName = cfpname.cToString()
+	tp.ComparatorName = ccmpname.cToString()
+
+	C.TablePropertiesGetUserCollectedProperties(ctp, &ckeys, &cvals, &n)
+	keys := newStringArrayFromCArray(ckeys, uint(n))
+	vals := newStringArrayFromCArray(cvals, uint(n))
+	tp.UserCollectedProperties = make(map[string]string, len(keys))
+	for i, key := range keys {
+		tp.UserCollectedProperties[key] = vals[i]
+	}
+	return
+}
+
+// The properties of the tables keyed by the file name
+type TablePropertiesCollection map[string]*TableProperties
+
+// C TablePropertiesCollection to go TablePropertiesCollection.
+// Delete the underlying @ctpc if del is true.
+func (ctpc *C.TablePropertiesCollection_t) toTablePropertiesCollection(del bool) (tpc TablePropertiesCollection) {
+	if del {
+		defer C.DeleteTablePropertiesCollectionT(ctpc, toCBool(false))
+	}
+
+	var (
+		cnames *C.String_t
+		ctps *C.TableProperties_t
+		n C.int
+	)
+
+	C.TablePropertiesCollectionGetAll(ctpc, &cnames, &ctps, &n)
+	defer C.DeleteTablePropertiesTArray(ctps)
+	names := newStringArrayFromCArray(cnames, uint(n))
+	tpc = make(TablePropertiesCollection, len(names))
+	for i, name := range names {
+		tpc[name] = (&(*[arrayDimenMax]C.TableProperties_t)(unsafe.Pointer(ctps))[i]).toTableProperties()
+	}
+	return
+}
+
+// TablePropertiesCollector provides the mechanism for users to collect
+// their own properties that they are interested in. This class is essentially
+// a collection of callback functions that will be invoked during table
+// building. It is constructed with TablePropertiesCollectorFactory. The methods
+// don't need to be thread-safe, as we will create exactly one
+// TablePropertiesCollector object per table and then call it sequentially
+type ITablePropertiesCollector interface {
+	// Add will be called when a new key/value pair is inserted into
+	// the table.
+	// @params key    the user key that is inserted into the table.
+	// @params value  the value that is inserted into the table.
+	Add(key, value []byte)
+
+	// Finish will be called when a table has already been built and is ready
+	// for writing the properties block. The returned properties are stored
+	// with the table as user collected properties.
+	Finish() map[string]string
+
+	// The name of the properties collector can be used for debugging purpose.
+	Name() string
+}
+
+// Wrap functions for ITablePropertiesCollector
+
+//export ITablePropertiesCollectorName
+func ITablePropertiesCollectorName(ctpc unsafe.Pointer) *C.char {
+	tpc := InterfacesGet(ctpc).(ITablePropertiesCollector)
+	return C.CString(tpc.Name())
+}
+
+//export ITablePropertiesCollectorAdd
+func ITablePropertiesCollectorAdd(ctpc unsafe.Pointer, key, value *C.Slice_t) {
+	tpc := InterfacesGet(ctpc).(ITablePropertiesCollector)
+	tpc.Add(key.cToBytes(false), value.cToBytes(false))
+}
+
+//export ITablePropertiesCollectorFinish
+func ITablePropertiesCollectorFinish(ctpc unsafe.Pointer, cprops *C.UserCollectedProperties_t) {
+	tpc := InterfacesGet(ctpc).(ITablePropertiesCollector)
+	for key, val := range tpc.Finish() {
+		ckey := newCStringFromString(&key)
+		cval := newCStringFromString(&val)
+		C.UserCollectedPropertiesInsert(cprops, &ckey.str, &cval.str)
+		ckey.del()
+		cval.del()
+	}
+}
+
+// Constructs ITablePropertiesCollector. Internals create a new
+// ITablePropertiesCollector for each new table
+type ITablePropertiesCollectorFactory interface {
+	// Create a ITablePropertiesCollector for a table of
+	// the column family cfid. Return nil to collect nothing
+	// for the table.
+	CreateTablePropertiesCollector(cfid uint32) ITablePropertiesCollector
+
+	// The name of the properties collector can be used for debugging purpose.
+	Name() string
+}
+
+// Wrap functions for ITablePropertiesCollectorFactory
+
+//export ITablePropertiesCollectorFactoryName
+func ITablePropertiesCollectorFactoryName(ctpcf unsafe.Pointer) *C.char {
+	tpcf := InterfacesGet(ctpcf).(ITablePropertiesCollectorFactory)
+	return C.CString(tpcf.Name())
+}
+
+//export ITablePropertiesCollectorFactoryCreateTablePropertiesCollector
+func ITablePropertiesCollectorFactoryCreateTablePropertiesCollector(ctpcf unsafe.Pointer, cfid C.uint32_t) (tpc unsafe.Pointer) {
+	tpcf := InterfacesGet(ctpcf).(ITablePropertiesCollectorFactory)
+	// A nil collector collects nothing for the table
+	if itpc := tpcf.CreateTablePropertiesCollector(uint32(cfid)); nil != itpc {
+		tpc = InterfacesAddReference(itpc)
+	}
+	return
+}
+
+// Wrap go TablePropertiesCollectorFactory
+type TablePropertiesCollectorFactory struct {
+	tpcf C.PTablePropertiesCollectorFactory_t
+}
+
+// Release resources
+func (tpcf *TablePropertiesCollectorFactory) finalize() {
+	var ctpcf *C.PTablePropertiesCollectorFactory_t = &tpcf.tpcf
+	C.DeletePTablePropertiesCollectorFactoryT(ctpcf, toCBool(false))
+}
+
+// C TablePropertiesCollectorFactory to go TablePropertiesCollectorFactory
+func (ctpcf *C.PTablePropertiesCollectorFactory_t) toTablePropertiesCollectorFactory() (tpcf *TablePropertiesCollectorFactory) {
+	tpcf = &TablePropertiesCollectorFactory{tpcf: *ctpcf}
+	runtime.SetFinalizer(tpcf, finalize)
+	return
+}
+
+// Return a new TablePropertiesCollectorFactory that uses ITablePropertiesCollectorFactory
+func NewTablePropertiesCollectorFactory(itf ITablePropertiesCollectorFactory) (tpcf *TablePropertiesCollectorFactory) {
+	var iftp unsafe.Pointer = nil
+
+	if nil != itf {
+		iftp = InterfacesAddReference(itf)
+	}
+	ctpcf := C.NewPTablePropertiesCollectorFactory(iftp)
+	return ctpcf.toTablePropertiesCollectorFactory()
+}
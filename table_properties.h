@@ -0,0 +1,57 @@
+// Copyright (c) 2015, Dean ChaoJun Pan.  All rights reserved.
+// This source code is licensed under the BSD-style license found in the
+// LICENSE file in the root directory of this source tree.
+
+#ifndef GO_ROCKSDB_INCLUDE_TABLE_PROPERTIES_H_
+#define GO_ROCKSDB_INCLUDE_TABLE_PROPERTIES_H_
+
+#include "types.h"
+#include "cstring.h"
+#include "slice.h"
+
+#ifdef __cplusplus
+extern "C" {
+#endif
+
+DEFINE_C_WRAP_STRUCT(TableProperties)
+DEFINE_C_WRAP_CONSTRUCTOR_DEC(TableProperties)
+DEFINE_C_WRAP_DESTRUCTOR_DEC(TableProperties)
+DEFINE_C_WRAP_DESTRUCTOR_ARRAY_DEC(TableProperties)
+// Get methods
+DEFINE_C_WRAP_GETTER_DEC(TableProperties, data_size, uint64_t)
+DEFINE_C_WRAP_GETTER_DEC(TableProperties, index_size, uint64_t)
+DEFINE_C_WRAP_GETTER_DEC(TableProperties, filter_size, uint64_t)
+DEFINE_C_WRAP_GETTER_DEC(TableProperties, raw_key_size, uint64_t)
+DEFINE_C_WRAP_GETTER_DEC(TableProperties, raw_value_size, uint64_t)
+DEFINE_C_WRAP_GETTER_DEC(TableProperties, num_data_blocks, uint64_t)
+DEFINE_C_WRAP_GETTER_DEC(TableProperties, num_entries, uint64_t)
+DEFINE_C_WRAP_GETTER_DEC(TableProperties, format_version, uint64_t)
+DEFINE_C_WRAP_GETTER_DEC(TableProperties, fixed_key_len, uint64_t)
+DEFINE_C_WRAP_GETTER_DEC(TableProperties, column_family_id, uint32_t)
+String_t TableProperties_get_column_family_name(TableProperties_t* ptr);
+String_t TableProperties_get_filter_policy_name(TableProperties_t* ptr);
+String_t TableProperties_get_comparator_name(TableProperties_t* ptr);
+void TablePropertiesGetUserCollectedProperties(TableProperties_t* ptr, String_t** keys, String_t** vals, int* n);
+
+DEFINE_C_WRAP_STRUCT(TablePropertiesCollection)
+DEFINE_C_WRAP_CONSTRUCTOR_DEC(TablePropertiesCollection)
+DEFINE_C_WRAP_CONSTRUCTOR_DEFAULT_DEC(TablePropertiesCollection)
+DEFINE_C_WRAP_DESTRUCTOR_DEC(TablePropertiesCollection)
+void TablePropertiesCollectionGetAll(TablePropertiesCollection_t* ptr, String_t** names, TableProperties_t** props, int* n);
+
+DEFINE_C_WRAP_STRUCT(UserCollectedProperties)
+DEFINE_C_WRAP_CONSTRUCTOR_DEC(UserCollectedProperties)
+DEFINE_C_WRAP_DESTRUCTOR_DEC(UserCollectedProperties)
+void UserCollectedPropertiesInsert(UserCollectedProperties_t* ptr, const String_t* key, const String_t* val);
+
+DEFINE_C_WRAP_STRUCT(PTablePropertiesCollectorFactory)
+DEFINE_C_WRAP_CONSTRUCTOR_DEC(PTablePropertiesCollectorFactory)
+DEFINE_C_WRAP_DESTRUCTOR_DEC(PTablePropertiesCollectorFactory)
+// Return a TablePropertiesCollectorFactory from a go ITablePropertiesCollectorFactory
+PTablePropertiesCollectorFactory_t NewPTablePropertiesCollectorFactory(void* go_tpcf);
+
+#ifdef __cplusplus
+}  /* end extern "C" */
+#endif
+
+#endif  // GO_ROCKSDB_INCLUDE_TABLE_PROPERTIES_H_
@@ -0,0 +1,334 @@
+// Copyright (c) 2015, Dean ChaoJun Pan.  All rights reserved.
+// This source code is licensed under the BSD-style license found in the
+// LICENSE file in the root directory of this source tree.
+
+#include <rocksdb/utilities/transaction.h>
+
+using namespace rocksdb;
+
+#include "transaction.h"
+
+DEFINE_C_WRAP_CONSTRUCTOR(Transaction)
+DEFINE_C_WRAP_DESTRUCTOR(Transaction)
+
+// If a transaction has a snapshot set, the transaction will ensure that
+// any keys successfully written(or fetched via GetForUpdate()) have not
+// been modified outside of this transaction since the time the snapshot was
+// set.
+// If a snapshot has not been set, the transaction guarantees that keys have
+// not been modified since the time each key was first written (or fetched via
+// GetForUpdate()).
+//
+// Using SetSnapshot() will provide stricter isolation guarantees at the
+// expense of potentially more transaction failures due to conflicts with
+// other writes.
+//
+// Calling SetSnapshot() has no effect on keys written before this function
+// has been called.
+//
+// SetSnapshot() may be called multiple times if you would like to change
+// the snapshot used for different operations in this transaction.
+//
+// Calling SetSnapshot will not affect the version of Data returned by Get()
+// methods.  See Transaction::Get() for more details.
+void TransactionSetSnapshot(Transaction_t* txn)
+{
+    if (txn && GET_REP(txn, Transaction))
+    {
+        GET_REP(txn, Transaction)->SetSnapshot();
+    }
+}
+
+// Returns the Snapshot created by the last call to SetSnapshot().
+//
+// REQUIRED: The returned Snapshot is only valid up until the next time
+// SetSnapshot() is called or the Transaction is deleted.
+Snapshot_t TransactionGetSnapshot(Transaction_t* txn)
+{
+    return NewSnapshotT((txn && GET_REP(txn, Transaction)) ?
+                        const_cast<Snapshot *>(GET_REP(txn, Transaction)->GetSnapshot()) :
+                        nullptr);
+}
+
+// Records the state of the transaction for future calls to
+// RollbackToSavePoint().  May be called multiple times to set multiple save
+// points.
+void TransactionSetSavePoint(Transaction_t* txn)
+{
+    if (txn && GET_REP(txn, Transaction))
+    {
+        GET_REP(txn, Transaction)->SetSavePoint();
+    }
+}
+
+// Undo all operations in this transaction (Put, Merge, Delete, PutLogData)
+// since the most recent call to SetSavePoint() and removes the most recent
+// SetSavePoint().
+// If there is no previous call to SetSavePoint(), returns Status::NotFound()
+Status_t TransactionRollbackToSavePoint(Transaction_t* txn)
+{
+    Status ret = ((txn && GET_REP(txn, Transaction)) ?
+                  GET_REP(txn, Transaction)->RollbackToSavePoint() :
+                  invalid_status);
+    return NewStatusTCopy(&ret);
+}
+
+// Write all batched keys to the db atomically.
+//
+// Returns OK on success.
+//
+// May return any error status that could be returned by DB:Write().
+//
+// If this transaction was created by an OptimisticTransactionDB(),
+// Status::Busy() may be returned if the transaction could not guarantee
+// that there are no write conflicts.  Status::TryAgain() may be returned
+// if the memtable history size is not large enough
+//  (See max_write_buffer_number_to_maintain).
+//
+// If this transaction was created by a TransactionDB(), Status::Expired()
+// may be returned if this transaction has lived for longer than
+// TransactionOptions.expiration.
+Status_t TransactionCommit(Transaction_t* txn)
+{
+    Status ret = ((txn && GET_REP(txn, Transaction)) ?
+                  GET_REP(txn, Transaction)->Commit() :
+                  invalid_status);
+    return NewStatusTCopy(&ret);
+}
+
+// Discard all batched writes in this transaction.
+Status_t TransactionRollback(Transaction_t* txn)
+{
+    Status ret = ((txn && GET_REP(txn, Transaction)) ?
+                  GET_REP(txn, Transaction)->Rollback() :
+                  invalid_status);
+    return NewStatusTCopy(&ret);
+}
+
+// This function is similar to DB::Get() except it will also read pending
+// changes in this transaction.  Currently, this function will return
+// Status::MergeInProgress if the most recent write to the queried key in
+// this batch is a Merge.
+//
+// If read_options.snapshot is not set, the current version of the key will
+// be read.  Calling SetSnapshot() does not affect the version of the data
+// returned.
+//
+// Note that setting read_options.snapshot will affect what is read from the
+// DB but will NOT change which keys are read from this transaction (the keys
+// in this transaction do not yet belong to any snapshot and will be fetched
+// regardless).
+Status_t TransactionGetWithColumnFamily(Transaction_t* txn,
+                                        const ReadOptions_t* options,
+                                        const ColumnFamilyHandle_t* column_family,
+                                        const Slice_t* key,
+                                        String_t* value)
+{
+    Status ret;
+    if (txn && GET_REP(txn, Transaction))
+    {
+        assert(GET_REP(options, ReadOptions) != NULL);
+        assert(GET_REP(column_family, ColumnFamilyHandle) != NULL);
+        assert(GET_REP(key, Slice) != NULL);
+        assert(GET_REP(value, String) != NULL);
+        ret = GET_REP(txn, Transaction)->Get(GET_REP_REF(options, ReadOptions), GET_REP(column_family, ColumnFamilyHandle), GET_REP_REF(key, Slice), GET_REP(value, String));
+    }
+    else
+        ret = invalid_status;
+    return NewStatusTCopy(&ret);
+}
+
+Status_t TransactionGet(Transaction_t* txn,
+                        const ReadOptions_t* options,
+                        const Slice_t* key,
+                        String_t* value)
+{
+    Status ret;
+    if (txn && GET_REP(txn, Transaction))
+    {
+        assert(GET_REP(options, ReadOptions) != NULL);
+        assert(GET_REP(key, Slice) != NULL);
+        assert(GET_REP(value, String) != NULL);
+        ret = GET_REP(txn, Transaction)->Get(GET_REP_REF(options, ReadOptions), GET_REP_REF(key, Slice), GET_REP(value, String));
+    }
+    else
+        ret = invalid_status;
+    return NewStatusTCopy(&ret);
+}
+
+// Read this key and ensure that this transaction will only
+// be able to be committed if this key is not written outside this
+// transaction after it has first been read (or after the snapshot if a
+// snapshot is set in this transaction).  The transaction behavior is the
+// same regardless of whether the key exists or not.
+//
+// The values returned by this function are similar to Transaction::Get().
+// If value==nullptr, then this function will not read any data, but will
+// still ensure that this key cannot be written to by outside of this
+// transaction.
+//
+// If this transaction was created by a TransactionDB, can return
+// Status::Busy() if there is a write conflict,
+// Status::TimedOut() if a lock could not be acquired,
+// Status::TryAgain() if the memtable history size is not large enough
+//  (See max_write_buffer_number_to_maintain)
+// or other errors if this key could not be read.
+Status_t TransactionGetForUpdateWithColumnFamily(Transaction_t* txn,
+                                                 const ReadOptions_t* options,
+                                                 const ColumnFamilyHandle_t* column_family,
+                                                 const Slice_t* key,
+                                                 String_t* value)
+{
+    Status ret;
+    if (txn && GET_REP(txn, Transaction))
+    {
+        assert(GET_REP(options, ReadOptions) != NULL);
+        assert(GET_REP(column_family, ColumnFamilyHandle) != NULL);
+        assert(GET_REP(key, Slice) != NULL);
+        assert(GET_REP(value, String) != NULL);
+        ret = GET_REP(txn, Transaction)->GetForUpdate(GET_REP_REF(options, ReadOptions), GET_REP(column_family, ColumnFamilyHandle), GET_REP_REF(key, Slice), GET_REP(value, String));
+    }
+    else
+        ret = invalid_status;
+    return NewStatusTCopy(&ret);
+}
+
+Status_t TransactionGetForUpdate(Transaction_t* txn,
+                                 const ReadOptions_t* options,
+                                 const Slice_t* key,
+                                 String_t* value)
+{
+    Status ret;
+    if (txn && GET_REP(txn, Transaction))
+    {
+        assert(GET_REP(options, ReadOptions) != NULL);
+        assert(GET_REP(key, Slice) != NULL);
+        assert(GET_REP(value, String) != NULL);
+        ret = GET_REP(txn, Transaction)->GetForUpdate(GET_REP_REF(options, ReadOptions), GET_REP_REF(key, Slice), GET_REP(value, String));
+    }
+    else
+        ret = invalid_status;
+    return NewStatusTCopy(&ret);
+}
+
+// Put, Merge, Delete, and SingleDelete behave similarly to the corresponding
+// functions in WriteBatch, but will also do conflict checking on the
+// keys being written.
+//
+// If this Transaction was created on an OptimisticTransactionDB, these
+// functions should always return Status::OK().
+//
+// If this Transaction was created on a TransactionDB, the status returned
+// can be:
+// Status::OK() on success,
+// Status::Busy() if there is a write conflict,
+// Status::TimedOut() if a lock could not be acquired,
+// Status::TryAgain() if the memtable history size is not large enough
+//  (See max_write_buffer_number_to_maintain)
+// or other errors on unexpected failures.
+Status_t TransactionPutWithColumnFamily(Transaction_t* txn,
+                                        const ColumnFamilyHandle_t* column_family,
+                                        const Slice_t* key,
+                                        const Slice_t* value)
+{
+    Status ret;
+    if (txn && GET_REP(txn, Transaction))
+    {
+        assert(GET_REP(column_family, ColumnFamilyHandle) != NULL);
+        assert(GET_REP(key, Slice) != NULL);
+        assert(GET_REP(value, Slice) != NULL);
+        ret = GET_REP(txn, Transaction)->Put(GET_REP(column_family, ColumnFamilyHandle), GET_REP_REF(key, Slice), GET_REP_REF(value, Slice));
+    }
+    else
+        ret = invalid_status;
+    return NewStatusTCopy(&ret);
+}
+
+Status_t TransactionPut(Transaction_t* txn,
+                        const Slice_t* key,
+                        const Slice_t* value)
+{
+    Status ret;
+    if (txn && GET_REP(txn, Transaction))
+    {
+        assert(GET_REP(key, Slice) != NULL);
+        assert(GET_REP(value, Slice) != NULL);
+        ret = GET_REP(txn, Transaction)->Put(GET_REP_REF(key, Slice), GET_REP_REF(value, Slice));
+    }
+    else
+        ret = invalid_status;
+    return NewStatusTCopy(&ret);
+}
+
+Status_t TransactionMergeWithColumnFamily(Transaction_t* txn,
+                                          const ColumnFamilyHandle_t* column_family,
+                                          const Slice_t* key,
+                                          const Slice_t* value)
+{
+    Status ret;
+    if (txn && GET_REP(txn, Transaction))
+    {
+        assert(GET_REP(column_family, ColumnFamilyHandle) != NULL);
+        assert(GET_REP(key, Slice) != NULL);
+        assert(GET_REP(value, Slice) != NULL);
+        ret = GET_REP(txn, Transaction)->Merge(GET_REP(column_family, ColumnFamilyHandle), GET_REP_REF(key, Slice), GET_REP_REF(value, Slice));
+    }
+    else
+        ret = invalid_status;
+    return NewStatusTCopy(&ret);
+}
+
+Status_t TransactionMerge(Transaction_t* txn,
+                          const Slice_t* key,
+                          const Slice_t* value)
+{
+    Status ret;
+    if (txn && GET_REP(txn, Transaction))
+    {
+        assert(GET_REP(key, Slice) != NULL);
+        assert(GET_REP(value, Slice) != NULL);
+        ret = GET_REP(txn, Transaction)->Merge(GET_REP_REF(key, Slice), GET_REP_REF(value, Slice));
+    }
+    else
+        ret = invalid_status;
+    return NewStatusTCopy(&ret);
+}
+
+Status_t TransactionDeleteWithColumnFamily(Transaction_t* txn,
+                                           const ColumnFamilyHandle_t* column_family,
+                                           const Slice_t* key)
+{
+    Status ret;
+    if (txn && GET_REP(txn, Transaction))
+    {
+        assert(GET_REP(column_family, ColumnFamilyHandle) != NULL);
+        assert(GET_REP(key, Slice) != NULL);
+        ret = GET_REP(txn, Transaction)->Delete(GET_REP(column_family, ColumnFamilyHandle), GET_REP_REF(key, Slice));
+    }
+    else
+        ret = invalid_status;
+    return NewStatusTCopy(&ret);
+}
+
+Status_t TransactionDelete(Transaction_t* txn,
+                           const Slice_t* key)
+{
+    Status ret;
+    if (txn && GET_REP(txn, Transaction))
+    {
+        assert(GET_REP(key, Slice) != NULL);
+        ret = GET_REP(txn, Transaction)->Delete(GET_REP_REF(key, Slice));
+    }
+    else
+        ret = invalid_status;
+    return NewStatusTCopy(&ret);
+}
+
+// Sets the lock timeout in milliseconds for this transaction.
+void TransactionSetLockTimeout(Transaction_t* txn, int64_t timeout)
+{
+    if (txn && GET_REP(txn, Transaction))
+    {
+        GET_REP(txn, Transaction)->SetLockTimeout(timeout);
+    }
+}
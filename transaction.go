@@ -0,0 +1,360 @@
+// Copyright (c) 2015, Dean ChaoJun Pan.  All rights reserved.
+// This source code is licensed under the BSD-style license found in the
+// LICENSE file in the root directory of this source tree.
+
+package rocksdb
+
+/*
+#include "transaction.h"
+*/
+import "C"
+
+import (
+	"runtime"
+)
+
+// Provides BEGIN/COMMIT/ROLLBACK transactions.
+//
+// To use transactions, you must first create either an OptimisticTransactionDB
+// or a TransactionDB.  See comments in optimistic_transaction_db.go and
+// transaction_db.go for more information.
+//
+// To create a transaction, use OptimisticTransactionDB.BeginTransaction()
+// or TransactionDB.BeginTransaction().
+//
+// It is up to the caller to synchronize access to this object.
+type Transaction struct {
+	txn C.Transaction_t
+	// The db the transaction is created from
+	db *DB
+	// true if txn is deleted
+	closed bool
+}
+
+// Release resources
+func (txn *Transaction) finalize() {
+	if !txn.closed {
+		txn.closed = true
+		txn.db.removeFromTxnmap(txn)
+		var ctxn *C.Transaction_t = &txn.txn
+		C.DeleteTransactionT(ctxn, toCBool(false))
+	}
+}
+
+// Close the @Transaction. A transaction that is neither committed nor
+// rolled back is rolled back.
+func (txn *Transaction) Close() {
+	runtime.SetFinalizer(txn, nil)
+	txn.finalize()
+}
+
+// C Transaction to go Transaction
+func (ctxn *C.Transaction_t) toTransaction(db *DB) (txn *Transaction) {
+	txn = &Transaction{txn: *ctxn, db: db}
+	db.addToTxnmap(txn)
+	runtime.SetFinalizer(txn, finalize)
+	return
+}
+
+// Return true if the transaction or its db is closed
+func (txn *Transaction) isClosed() bool {
+	return txn.closed || txn.db.closed
+}
+
+// If a transaction has a snapshot set, the transaction will ensure that
+// any keys successfully written(or fetched via GetForUpdate()) have not
+// been modified outside of this transaction since the time the snapshot was
+// set.
+// If a snapshot has not been set, the transaction guarantees that keys have
+// not been modified since the time each key was first written (or fetched via
+// GetForUpdate()).
+//
+// Using SetSnapshot() will provide stricter isolation guarantees at the
+// expense of potentially more transaction failures due to conflicts with
+// other writes.
+//
+// Calling SetSnapshot() has no effect on keys written before this function
+// has been called.
+//
+// SetSnapshot() may be called multiple times if you would like to change
+// the snapshot used for different operations in this transaction.
+//
+// Calling SetSnapshot will not affect the version of Data returned by Get()
+// methods.  See Transaction.Get() for more details.
+func (txn *Transaction) SetSnapshot() {
+	if txn.isClosed() {
+		return
+	}
+
+	var ctxn *C.Transaction_t = &txn.txn
+	C.TransactionSetSnapshot(ctxn)
+}
+
+// Returns the Snapshot created by the last call to SetSnapshot(), or nil
+// if no snapshot is set. The snapshot is owned by the transaction and must
+// not be released.
+//
+// REQUIRED: The returned Snapshot is only valid up until the next time
+// SetSnapshot() is called or the Transaction is closed.
+func (txn *Transaction) GetSnapshot() (snp *Snapshot) {
+	if txn.isClosed() {
+		return
+	}
+
+	var ctxn *C.Transaction_t = &txn.txn
+	csnp := C.TransactionGetSnapshot(ctxn)
+	if nil != csnp.rep {
+		snp = csnp.toSnapshot(nil)
+	}
+	return
+}
+
+// Records the state of the transaction for future calls to
+// RollbackToSavePoint().  May be called multiple times to set multiple save
+// points.
+func (txn *Transaction) SetSavePoint() {
+	if txn.isClosed() {
+		return
+	}
+
+	var ctxn *C.Transaction_t = &txn.txn
+	C.TransactionSetSavePoint(ctxn)
+}
+
+// Undo all operations in this transaction (Put, Merge, Delete)
+// since the most recent call to SetSavePoint() and removes the most recent
+// SetSavePoint().
+// If there is no previous call to SetSavePoint(), returns NotFound status.
+func (txn *Transaction) RollbackToSavePoint() (stat *Status) {
+	if txn.isClosed() {
+		stat = NewDBClosedStatus()
+		return
+	}
+
+	var ctxn *C.Transaction_t = &txn.txn
+	cstat := C.TransactionRollbackToSavePoint(ctxn)
+	stat = cstat.toStatus()
+	return
+}
+
+// Write all batched keys to the db atomically.
+//
+// Returns OK on success.
+//
+// May return any error status that could be returned by DB.Write().
+//
+// If this transaction was created by an OptimisticTransactionDB,
+// a Busy status may be returned if the transaction could not guarantee
+// that there are no write conflicts.  A TryAgain status may be returned
+// if the memtable history size is not large enough
+// (See max_write_buffer_number_to_maintain).
+//
+// If this transaction was created by a TransactionDB, an Expired status
+// may be returned if this transaction has lived for longer than
+// TransactionOptions expiration.
+func (txn *Transaction) Commit() (stat *Status) {
+	if txn.isClosed() {
+		stat = NewDBClosedStatus()
+		return
+	}
+
+	var ctxn *C.Transaction_t = &txn.txn
+	cstat := C.TransactionCommit(ctxn)
+	stat = cstat.toStatus()
+	return
+}
+
+// Discard all batched writes in this transaction.
+func (txn *Transaction) Rollback() (stat *Status) {
+	if txn.isClosed() {
+		stat = NewDBClosedStatus()
+		return
+	}
+
+	var ctxn *C.Transaction_t = &txn.txn
+	cstat := C.TransactionRollback(ctxn)
+	stat = cstat.toStatus()
+	return
+}
+
+// This function is similar to DB.Get() except it will also read pending
+// changes in this transaction.
+//
+// If the snapshot of options is not set, the current version of the key
+// will be read.  Calling SetSnapshot() does not affect the version of the
+// data returned.
+func (txn *Transaction) Get(options *ReadOptions, key []byte, cfh ...*ColumnFamilyHandle) (val []byte, stat *Status) {
+	return txn.get(false, options, key, cfh...)
+}
+
+// Read this key and ensure that this transaction will only
+// be able to be committed if this key is not written outside this
+// transaction after it has first been read (or after the snapshot if a
+// snapshot is set in this transaction).  The transaction behavior is the
+// same regardless of whether the key exists or not.
+//
+// The values returned by this function are similar to Transaction.Get().
+//
+// If this transaction was created by a TransactionDB, can return
+// a Busy status if there is a write conflict,
+// a TimedOut status (IsLockTimeout) if a lock could not be acquired,
+// or other errors if this key could not be read.
+func (txn *Transaction) GetForUpdate(options *ReadOptions, key []byte, cfh ...*ColumnFamilyHandle) (val []byte, stat *Status) {
+	return txn.get(true, options, key, cfh...)
+}
+
+// Implement Get and GetForUpdate
+func (txn *Transaction) get(forUpdate bool, options *ReadOptions, key []byte, cfh ...*ColumnFamilyHandle) (val []byte, stat *Status) {
+	if txn.isClosed() {
+		stat = NewDBClosedStatus()
+		return
+	}
+
+	ckey := newSliceFromBytes(key)
+	defer ckey.del()
+	cval := newCString()
+
+	var (
+		ctxn *C.Transaction_t = &txn.txn
+		cropt *C.ReadOptions_t = &options.ropt
+		ccfh *C.ColumnFamilyHandle_t
+		cckey *C.Slice_t = &ckey.slc
+		ccval *C.String_t = &cval.str
+	)
+
+	if cfh != nil {
+		ccfh = &cfh[0].cfh
+	}
+
+	var cstat C.Status_t
+	switch {
+	case forUpdate && ccfh != nil:
+		cstat = C.TransactionGetForUpdateWithColumnFamily(ctxn, cropt, ccfh, cckey, ccval)
+	case forUpdate:
+		cstat = C.TransactionGetForUpdate(ctxn, cropt, cckey, ccval)
+	case ccfh != nil:
+		cstat = C.TransactionGetWithColumnFamily(ctxn, cropt, ccfh, cckey, ccval)
+	default:
+		cstat = C.TransactionGet(ctxn, cropt, cckey, ccval)
+	}
+	stat = cstat.toStatus()
+	val = cval.goBytes(true)
+	return
+}
+
+// Put, Merge and Delete behave similarly to the corresponding
+// functions in WriteBatch, but will also do conflict checking on the
+// keys being written.
+//
+// If this Transaction was created on an OptimisticTransactionDB, these
+// functions should always return OK.
+//
+// If this Transaction was created on a TransactionDB, the status returned
+// can be:
+// OK on success,
+// Busy if there is a write conflict,
+// TimedOut (IsLockTimeout) if a lock could not be acquired,
+// or other errors on unexpected failures.
+func (txn *Transaction) Put(key, val []byte, cfh ...*ColumnFamilyHandle) (stat *Status) {
+	if txn.isClosed() {
+		stat = NewDBClosedStatus()
+		return
+	}
+
+	ckey := newSliceFromBytes(key)
+	defer ckey.del()
+	cval := newSliceFromBytes(val)
+	defer cval.del()
+
+	var (
+		ctxn *C.Transaction_t = &txn.txn
+		ccfh *C.ColumnFamilyHandle_t
+		cckey *C.Slice_t = &ckey.slc
+		ccval *C.Slice_t = &cval.slc
+	)
+
+	if cfh != nil {
+		ccfh = &cfh[0].cfh
+	}
+
+	var cstat C.Status_t
+	if ccfh != nil {
+		cstat = C.TransactionPutWithColumnFamily(ctxn, ccfh, cckey, ccval)
+	} else {
+		cstat = C.TransactionPut(ctxn, cckey, ccval)
+	}
+	stat = cstat.toStatus()
+	return
+}
+
+func (txn *Transaction) Merge(key, val []byte, cfh ...*ColumnFamilyHandle) (stat *Status) {
+	if txn.isClosed() {
+		stat = NewDBClosedStatus()
+		return
+	}
+
+	ckey := newSliceFromBytes(key)
+	defer ckey.del()
+	cval := newSliceFromBytes(val)
+	defer cval.del()
+
+	var (
+		ctxn *C.Transaction_t = &txn.txn
+		ccfh *C.ColumnFamilyHandle_t
+		cckey *C.Slice_t = &ckey.slc
+		ccval *C.Slice_t = &cval.slc
+	)
+
+	if cfh != nil {
+		ccfh = &cfh[0].cfh
+	}
+
+	var cstat C.Status_t
+	if ccfh != nil {
+		cstat = C.TransactionMergeWithColumnFamily(ctxn, ccfh, cckey, ccval)
+	} else {
+		cstat = C.TransactionMerge(ctxn, cckey, ccval)
+	}
+	stat = cstat.toStatus()
+	return
+}
+
+func (txn *Transaction) Delete(key []byte, cfh ...*ColumnFamilyHandle) (stat *Status) {
+	if txn.isClosed() {
+		stat = NewDBClosedStatus()
+		return
+	}
+
+	ckey := newSliceFromBytes(key)
+	defer ckey.del()
+
+	var (
+		ctxn *C.Transaction_t = &txn.txn
+		ccfh *C.ColumnFamilyHandle_t
+		cckey *C.Slice_t = &ckey.slc
+	)
+
+	if cfh != nil {
+		ccfh = &cfh[0].cfh
+	}
+
+	var cstat C.Status_t
+	if ccfh != nil {
+		cstat = C.TransactionDeleteWithColumnFamily(ctxn, ccfh, cckey)
+	} else {
+		cstat = C.TransactionDelete(ctxn, cckey)
+	}
+	stat = cstat.toStatus()
+	return
+}
+
+// Sets the lock timeout in milliseconds for this transaction, overriding
+// TransactionOptions lock timeout.
+func (txn *Transaction) SetLockTimeout(timeout int64) {
+	if txn.isClosed() {
+		return
+	}
+
+	var ctxn *C.Transaction_t = &txn.txn
+	C.TransactionSetLockTimeout(ctxn, C.int64_t(timeout))
+}
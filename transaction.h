@@ -0,0 +1,74 @@
+// Copyright (c) 2015, Dean ChaoJun Pan.  All rights reserved.
+// This source code is licensed under the BSD-style license found in the
+// LICENSE file in the root directory of this source tree.
+
+#ifndef GO_ROCKSDB_INCLUDE_TRANSACTION_H_
+#define GO_ROCKSDB_INCLUDE_TRANSACTION_H_
+
+#include "types.h"
+#include "slice.h"
+#include "cstring.h"
+#include "status.h"
+#include "options.h"
+#include "snapshot.h"
+#include "columnFamilyHandle.h"
+
+#ifdef __cplusplus
+extern "C" {
+#endif
+
+// Provides BEGIN/COMMIT/ROLLBACK transactions.
+DEFINE_C_WRAP_STRUCT(Transaction)
+DEFINE_C_WRAP_CONSTRUCTOR_DEC(Transaction)
+DEFINE_C_WRAP_DESTRUCTOR_DEC(Transaction)
+
+void TransactionSetSnapshot(Transaction_t* txn);
+Snapshot_t TransactionGetSnapshot(Transaction_t* txn);
+void TransactionSetSavePoint(Transaction_t* txn);
+Status_t TransactionRollbackToSavePoint(Transaction_t* txn);
+Status_t TransactionCommit(Transaction_t* txn);
+Status_t TransactionRollback(Transaction_t* txn);
+Status_t TransactionGetWithColumnFamily(Transaction_t* txn,
+                                        const ReadOptions_t* options,
+                                        const ColumnFamilyHandle_t* column_family,
+                                        const Slice_t* key,
+                                        String_t* value);
+Status_t TransactionGet(Transaction_t* txn,
+                        const ReadOptions_t* options,
+                        const Slice_t* key,
+                        String_t* value);
+Status_t TransactionGetForUpdateWithColumnFamily(Transaction_t* txn,
+                                                 const ReadOptions_t* options,
+                                                 const ColumnFamilyHandle_t* column_family,
+                                                 const Slice_t* key,
+                                                 String_t* value);
+Status_t TransactionGetForUpdate(Transaction_t* txn,
+                                 const ReadOptions_t* options,
+                                 const Slice_t* key,
+                                 String_t* value);
+Status_t TransactionPutWithColumnFamily(Transaction_t* txn,
+                                        const ColumnFamilyHandle_t* column_family,
+                                        const Slice_t* key,
+                                        const Slice_t* value);
+Status_t TransactionPut(Transaction_t* txn,
+                        const Slice_t* key,
+                        const Slice_t* value);
+Status_t TransactionMergeWithColumnFamily(Transaction_t* txn,
+                                          const ColumnFamilyHandle_t* column_family,
+                                          const Slice_t* key,
+                                          const Slice_t* value);
+Status_t TransactionMerge(Transaction_t* txn,
+                          const Slice_t* key,
+                          const Slice_t* value);
+Status_t TransactionDeleteWithColumnFamily(Transaction_t* txn,
+                                           const ColumnFamilyHandle_t* column_family,
+                                           const Slice_t* key);
+Status_t TransactionDelete(Transaction_t* txn,
+                           const Slice_t* key);
+void TransactionSetLockTimeout(Transaction_t* txn, int64_t timeout);
+
+#ifdef __cplusplus
+}  /* end extern "C" */
+#endif
+
+#endif  // GO_ROCKSDB_INCLUDE_TRANSACTION_H_
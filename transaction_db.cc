@@ -0,0 +1,141 @@
+// Copyright (c) 2015, Dean ChaoJun Pan.  All rights reserved.
+// This source code is licensed under the BSD-style license found in the
+// LICENSE file in the root directory of this source tree.
+
+#include <rocksdb/utilities/transaction_db.h>
+
+using namespace rocksdb;
+
+#include "transaction_db.h"
+
+DEFINE_C_WRAP_CONSTRUCTOR(TransactionDBOptions)
+DEFINE_C_WRAP_CONSTRUCTOR_DEFAULT(TransactionDBOptions)
+DEFINE_C_WRAP_DESTRUCTOR(TransactionDBOptions)
+
+// Specifies the maximum number of keys that can be locked at the same time
+// per column family.
+// If the number of locked keys is greater than max_num_locks, transaction
+// writes (or GetForUpdate) will return an error.
+// If this value is not positive, no limit will be enforced.
+DEFINE_C_WRAP_GETTER(TransactionDBOptions, max_num_locks, int64_t)
+DEFINE_C_WRAP_SETTER(TransactionDBOptions, max_num_locks, int64_t)
+
+// Increasing this value will increase the concurrency by dividing the lock
+// table (per column family) into more sub-tables, each with their own
+// separate
+// mutex.
+DEFINE_C_WRAP_GETTER(TransactionDBOptions, num_stripes, size_t)
+DEFINE_C_WRAP_SETTER(TransactionDBOptions, num_stripes, size_t)
+
+// If positive, specifies the default wait timeout in milliseconds when
+// a transaction attempts to lock a key if not specified by
+// TransactionOptions::lock_timeout.
+//
+// If 0, no waiting is done if a lock cannot instantly be acquired.
+// If negative, there is no timeout.  Not using a timeout is not recommended
+// as it can lead to deadlocks.  Currently, there is no deadlock-detection to
+// recover from a deadlock.
+DEFINE_C_WRAP_GETTER(TransactionDBOptions, transaction_lock_timeout, int64_t)
+DEFINE_C_WRAP_SETTER(TransactionDBOptions, transaction_lock_timeout, int64_t)
+
+// If positive, specifies the wait timeout in milliseconds when writing a key
+// OUTSIDE of a transaction (ie by calling DB::Put(),Merge(),Delete(),Write()
+// directly).
+// If 0, no waiting is done if a lock cannot instantly be acquired.
+// If negative, there is no timeout and will block indefinitely when acquiring
+// a lock.
+DEFINE_C_WRAP_GETTER(TransactionDBOptions, default_lock_timeout, int64_t)
+DEFINE_C_WRAP_SETTER(TransactionDBOptions, default_lock_timeout, int64_t)
+
+DEFINE_C_WRAP_CONSTRUCTOR(TransactionOptions)
+DEFINE_C_WRAP_CONSTRUCTOR_DEFAULT(TransactionOptions)
+DEFINE_C_WRAP_DESTRUCTOR(TransactionOptions)
+
+// Setting set_snapshot=true is the same as calling
+// Transaction::SetSnapshot().
+DEFINE_C_WRAP_GETTER(TransactionOptions, set_snapshot, bool)
+DEFINE_C_WRAP_SETTER(TransactionOptions, set_snapshot, bool)
+
+// If positive, specifies the wait timeout in milliseconds when
+// a transaction attempts to lock a key.
+//
+// If 0, no waiting is done if a lock cannot instantly be acquired.
+// If negative, TransactionDBOptions::transaction_lock_timeout will be used.
+DEFINE_C_WRAP_GETTER(TransactionOptions, lock_timeout, int64_t)
+DEFINE_C_WRAP_SETTER(TransactionOptions, lock_timeout, int64_t)
+
+// Expiration duration in milliseconds.  If non-negative, transactions that
+// last longer than this many milliseconds will fail to commit.  If not set,
+// a forgotten transaction that is never committed, rolled back, or deleted
+// will never relinquish any locks it holds.  This could prevent keys from
+// being written by other writers.
+DEFINE_C_WRAP_GETTER(TransactionOptions, expiration, int64_t)
+DEFINE_C_WRAP_SETTER(TransactionOptions, expiration, int64_t)
+
+DEFINE_C_WRAP_CONSTRUCTOR(TransactionDB)
+DEFINE_C_WRAP_STATIC_CAST(TransactionDB, DB)
+
+// Open a TransactionDB similar to DB::Open().
+Status_t TransactionDBOpen(const Options_t* options,
+                           const TransactionDBOptions_t* txn_db_options,
+                           const String_t* name,
+                           TransactionDB_t* dbptr)
+{
+    assert(dbptr != NULL);
+    TransactionDB** rdbptr = GET_REP_ADDR(dbptr, TransactionDB);
+    assert(rdbptr != NULL);
+    assert(GET_REP(options, Options) != NULL);
+    assert(GET_REP(txn_db_options, TransactionDBOptions) != NULL);
+    assert(GET_REP(name, String) != NULL);
+    Status stat = TransactionDB::Open(GET_REP_REF(options, Options), GET_REP_REF(txn_db_options, TransactionDBOptions), GET_REP_REF(name, String), rdbptr);
+    return NewStatusTCopy(&stat);
+}
+
+// Open a TransactionDB with column families similar to DB::Open().
+Status_t TransactionDBOpenWithColumnFamilies(const Options_t* options,
+                                             const TransactionDBOptions_t* txn_db_options,
+                                             const String_t* name,
+                                             const ColumnFamilyDescriptor_t column_families[], const int size_col,
+                                             ColumnFamilyHandle_t **handles,
+                                             TransactionDB_t* dbptr)
+{
+    std::vector<ColumnFamilyDescriptor> column_families_vec = std::vector<ColumnFamilyDescriptor>();
+    for (int i = 0; i < size_col; i++)
+        column_families_vec.push_back(*(ColumnFamilyDescriptor*)column_families[i].rep);
+    std::vector<ColumnFamilyHandle*> handles_vec;
+    assert(dbptr != NULL);
+    TransactionDB** rdbptr = GET_REP_ADDR(dbptr, TransactionDB);
+    assert(rdbptr != NULL);
+    assert(GET_REP(options, Options) != NULL);
+    assert(GET_REP(txn_db_options, TransactionDBOptions) != NULL);
+    assert(GET_REP(name, String) != NULL);
+    Status stat = TransactionDB::Open(GET_REP_REF(options, Options), GET_REP_REF(txn_db_options, TransactionDBOptions), GET_REP_REF(name, String), column_families_vec, &handles_vec, rdbptr);
+    Status_t ret = NewStatusTCopy(&stat);
+    *handles = new ColumnFamilyHandle_t[size_col];
+    for (int j = 0; j < size_col; j++)
+    {
+        ColumnFamilyHandle** rcfh = GET_REP_ADDR(&(*handles)[j], ColumnFamilyHandle);
+        *rcfh = (j < (int)handles_vec.size()) ? handles_vec[j] : nullptr;
+    }
+    return ret;
+}
+
+// Starts a new Transaction.  Passing set_snapshot=true has the same effect
+// as calling Transaction::SetSnapshot().
+//
+// Caller should delete the returned transaction after calling
+// Transaction::Commit() or Transaction::Rollback().
+Transaction_t TransactionDBBeginTransaction(TransactionDB_t* dbptr,
+                                            const WriteOptions_t* write_options,
+                                            const TransactionOptions_t* txn_options)
+{
+    Transaction* txn = nullptr;
+    if (dbptr && GET_REP(dbptr, TransactionDB))
+    {
+        assert(GET_REP(write_options, WriteOptions) != NULL);
+        txn = (txn_options ?
+               GET_REP(dbptr, TransactionDB)->BeginTransaction(GET_REP_REF(write_options, WriteOptions), GET_REP_REF(txn_options, TransactionOptions)) :
+               GET_REP(dbptr, TransactionDB)->BeginTransaction(GET_REP_REF(write_options, WriteOptions)));
+    }
+    return NewTransactionT(txn);
+}
@@ -0,0 +1,276 @@
+// Copyright (c) 2015, Dean ChaoJun Pan.  All rights reserved.
+// This source code is licensed under the BSD-style license found in the
+// LICENSE file in the root directory of this source tree.
+
+package rocksdb
+
+/*
+#include "transaction_db.h"
+*/
+import "C"
+
+import (
+	"runtime"
+)
+
+// Wrap go TransactionDBOptions
+type TransactionDBOptions struct {
+	tdbopt C.TransactionDBOptions_t
+	// true if tdbopt is deleted
+	closed bool
+}
+
+// Release resources
+func (tdbopt *TransactionDBOptions) finalize() {
+	if !tdbopt.closed {
+		tdbopt.closed = true
+		var ctdbopt *C.TransactionDBOptions_t = &tdbopt.tdbopt
+		C.DeleteTransactionDBOptionsT(ctdbopt, toCBool(false))
+	}
+}
+
+// Close the @TransactionDBOptions
+func (tdbopt *TransactionDBOptions) Close() {
+	runtime.SetFinalizer(tdbopt, nil)
+	tdbopt.finalize()
+}
+
+// C TransactionDBOptions to go TransactionDBOptions
+func (ctdbopt *C.TransactionDBOptions_t) toTransactionDBOptions() (tdbopt *TransactionDBOptions) {
+	tdbopt = &TransactionDBOptions{tdbopt: *ctdbopt}
+	runtime.SetFinalizer(tdbopt, finalize)
+	return
+}
+
+// Create a default TransactionDBOptions
+func NewTransactionDBOptions() *TransactionDBOptions {
+	ctdbopt := C.NewTransactionDBOptionsTDefault()
+	return ctdbopt.toTransactionDBOptions()
+}
+
+// Specifies the maximum number of keys that can be locked at the same time
+// per column family.
+// If the number of locked keys is greater than max_num_locks, transaction
+// writes (or GetForUpdate) will return an error (IsLockLimit).
+// If this value is not positive, no limit will be enforced.
+// Default: -1
+func (tdbopt *TransactionDBOptions) SetMaxNumLocks(val int64) {
+	var ctdbopt *C.TransactionDBOptions_t = &tdbopt.tdbopt
+	C.TransactionDBOptions_set_max_num_locks(ctdbopt, C.int64_t(val))
+}
+
+func (tdbopt *TransactionDBOptions) MaxNumLocks() int64 {
+	var ctdbopt *C.TransactionDBOptions_t = &tdbopt.tdbopt
+	return int64(C.TransactionDBOptions_get_max_num_locks(ctdbopt))
+}
+
+// Increasing this value will increase the concurrency by dividing the lock
+// table (per column family) into more sub-tables, each with their own
+// separate mutex.
+// Default: 16
+func (tdbopt *TransactionDBOptions) SetNumStripes(val uint64) {
+	var ctdbopt *C.TransactionDBOptions_t = &tdbopt.tdbopt
+	C.TransactionDBOptions_set_num_stripes(ctdbopt, C.size_t(val))
+}
+
+func (tdbopt *TransactionDBOptions) NumStripes() uint64 {
+	var ctdbopt *C.TransactionDBOptions_t = &tdbopt.tdbopt
+	return uint64(C.TransactionDBOptions_get_num_stripes(ctdbopt))
+}
+
+// If positive, specifies the default wait timeout in milliseconds when
+// a transaction attempts to lock a key if not specified by
+// TransactionOptions lock timeout.
+//
+// If 0, no waiting is done if a lock cannot instantly be acquired.
+// If negative, there is no timeout.  Not using a timeout is not recommended
+// as it can lead to deadlocks.  Currently, there is no deadlock-detection to
+// recover from a deadlock.
+// Default: 1000
+func (tdbopt *TransactionDBOptions) SetTransactionLockTimeout(val int64) {
+	var ctdbopt *C.TransactionDBOptions_t = &tdbopt.tdbopt
+	C.TransactionDBOptions_set_transaction_lock_timeout(ctdbopt, C.int64_t(val))
+}
+
+func (tdbopt *TransactionDBOptions) TransactionLockTimeout() int64 {
+	var ctdbopt *C.TransactionDBOptions_t = &tdbopt.tdbopt
+	return int64(C.TransactionDBOptions_get_transaction_lock_timeout(ctdbopt))
+}
+
+// If positive, specifies the wait timeout in milliseconds when writing a key
+// OUTSIDE of a transaction (ie by calling DB.Put(),Merge(),Delete(),Write()
+// directly).
+// If 0, no waiting is done if a lock cannot instantly be acquired.
+// If negative, there is no timeout and will block indefinitely when acquiring
+// a lock.
+// Default: 1000
+func (tdbopt *TransactionDBOptions) SetDefaultLockTimeout(val int64) {
+	var ctdbopt *C.TransactionDBOptions_t = &tdbopt.tdbopt
+	C.TransactionDBOptions_set_default_lock_timeout(ctdbopt, C.int64_t(val))
+}
+
+func (tdbopt *TransactionDBOptions) DefaultLockTimeout() int64 {
+	var ctdbopt *C.TransactionDBOptions_t = &tdbopt.tdbopt
+	return int64(C.TransactionDBOptions_get_default_lock_timeout(ctdbopt))
+}
+
+// Wrap go TransactionOptions
+type TransactionOptions struct {
+	topt C.TransactionOptions_t
+	// true if topt is deleted
+	closed bool
+}
+
+// Release resources
+func (topt *TransactionOptions) finalize() {
+	if !topt.closed {
+		topt.closed = true
+		var ctopt *C.TransactionOptions_t = &topt.topt
+		C.DeleteTransactionOptionsT(ctopt, toCBool(false))
+	}
+}
+
+// Close the @TransactionOptions
+func (topt *TransactionOptions) Close() {
+	runtime.SetFinalizer(topt, nil)
+	topt.finalize()
+}
+
+// C TransactionOptions to go TransactionOptions
+func (ctopt *C.TransactionOptions_t) toTransactionOptions() (topt *TransactionOptions) {
+	topt = &TransactionOptions{topt: *ctopt}
+	runtime.SetFinalizer(topt, finalize)
+	return
+}
+
+// Create a default TransactionOptions
+func NewTransactionOptions() *TransactionOptions {
+	ctopt := C.NewTransactionOptionsTDefault()
+	return ctopt.toTransactionOptions()
+}
+
+// Setting set_snapshot=true is the same as calling
+// Transaction.SetSnapshot().
+// Default: false
+func (topt *TransactionOptions) SetSetSnapshot(val bool) {
+	var ctopt *C.TransactionOptions_t = &topt.topt
+	C.TransactionOptions_set_set_snapshot(ctopt, toCBool(val))
+}
+
+func (topt *TransactionOptions) SetSnapshot() bool {
+	var ctopt *C.TransactionOptions_t = &topt.topt
+	return C.TransactionOptions_get_set_snapshot(ctopt).toBool()
+}
+
+// If positive, specifies the wait timeout in milliseconds when
+// a transaction attempts to lock a key.
+//
+// If 0, no waiting is done if a lock cannot instantly be acquired.
+// If negative, TransactionDBOptions transaction_lock_timeout will be used.
+// Default: -1
+func (topt *TransactionOptions) SetLockTimeout(val int64) {
+	var ctopt *C.TransactionOptions_t = &topt.topt
+	C.TransactionOptions_set_lock_timeout(ctopt, C.int64_t(val))
+}
+
+func (topt *TransactionOptions) LockTimeout() int64 {
+	var ctopt *C.TransactionOptions_t = &topt.topt
+	return int64(C.TransactionOptions_get_lock_timeout(ctopt))
+}
+
+// Expiration duration in milliseconds.  If non-negative, transactions that
+// last longer than this many milliseconds will fail to commit.  If not set,
+// a forgotten transaction that is never committed, rolled back, or deleted
+// will never relinquish any locks it holds.  This could prevent keys from
+// being written by other writers.
+// Default: -1
+func (topt *TransactionOptions) SetExpiration(val int64) {
+	var ctopt *C.TransactionOptions_t = &topt.topt
+	C.TransactionOptions_set_expiration(ctopt, C.int64_t(val))
+}
+
+func (topt *TransactionOptions) Expiration() int64 {
+	var ctopt *C.TransactionOptions_t = &topt.topt
+	return int64(C.TransactionOptions_get_expiration(ctopt))
+}
+
+// A TransactionDB is a DB that supports pessimistic transactions. Keys
+// written or read by GetForUpdate in a transaction are locked until the
+// transaction is committed or rolled back.
+// All the DB methods can be called on a TransactionDB.
+type TransactionDB struct {
+	*DB
+	tdb C.TransactionDB_t
+}
+
+// Open a TransactionDB similar to Open(). The transactions created from
+// the TransactionDB that are still open are closed, i.e. rolled back,
+// when the TransactionDB is closed.
+func OpenTransactionDB(options *Options, tdbopt *TransactionDBOptions, name *string, cfds ...*ColumnFamilyDescriptor) (tdb *TransactionDB, stat *Status, cfhs []*ColumnFamilyHandle) {
+	tdb = &TransactionDB{DB: newDB(&options.DBOptions)}
+	rstr := newCStringFromString(name)
+	defer rstr.del()
+
+	var ccfds []C.ColumnFamilyDescriptor_t
+
+	if cfds != nil {
+		s := make([]interface{}, len(cfds))
+		for i, v := range cfds {
+			s[i] = v
+		}
+		ccfds = newCArrayFromColumnFamilyDescriptorArray(s...)
+	}
+
+	var (
+		ctdb *C.TransactionDB_t = &tdb.tdb
+		opt *C.Options_t = &options.opt
+		ctdbopt *C.TransactionDBOptions_t = &tdbopt.tdbopt
+		cstr *C.String_t = &rstr.str
+		cfh *C.ColumnFamilyHandle_t
+	)
+
+	if ccfds != nil {
+		cstat := C.TransactionDBOpenWithColumnFamilies(opt, ctdbopt, cstr, &ccfds[0], C.int(len(ccfds)), &cfh, ctdb)
+		stat = cstat.toStatus()
+		if stat.Ok() {
+			cfhs = newColumnFamilyHandleArrayFromCArray(tdb.DB, cfh, uint(len(ccfds)))
+		} else {
+			C.DeleteColumnFamilyHandleTArray(cfh)
+		}
+	} else {
+		cstat := C.TransactionDBOpen(opt, ctdbopt, cstr, ctdb)
+		stat = cstat.toStatus()
+	}
+
+	if stat.Ok() {
+		C.TransactionDBTStaticCastToDBT(ctdb, &tdb.db)
+		runtime.SetFinalizer(tdb.DB, finalize)
+	}
+
+	return
+}
+
+// Starts a new Transaction. topt is optional. Setting set_snapshot of
+// topt to true has the same effect as calling Transaction.SetSnapshot().
+//
+// Caller should close the returned transaction after calling
+// Transaction.Commit() or Transaction.Rollback().
+func (tdb *TransactionDB) BeginTransaction(wopt *WriteOptions, topt ...*TransactionOptions) (txn *Transaction) {
+	if tdb.closed {
+		return
+	}
+
+	var (
+		ctdb *C.TransactionDB_t = &tdb.tdb
+		cwopt *C.WriteOptions_t = &wopt.wopt
+		ctopt *C.TransactionOptions_t
+	)
+
+	if topt != nil {
+		ctopt = &topt[0].topt
+	}
+
+	ctxn := C.TransactionDBBeginTransaction(ctdb, cwopt, ctopt)
+	txn = ctxn.toTransaction(tdb.DB)
+	return
+}
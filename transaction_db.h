@@ -0,0 +1,74 @@
+// Copyright (c) 2015, Dean ChaoJun Pan.  All rights reserved.
+// This source code is licensed under the BSD-style license found in the
+// LICENSE file in the root directory of this source tree.
+
+#ifndef GO_ROCKSDB_INCLUDE_TRANSACTION_DB_H_
+#define GO_ROCKSDB_INCLUDE_TRANSACTION_DB_H_
+
+#include "types.h"
+#include "db.h"
+#include "transaction.h"
+
+#ifdef __cplusplus
+extern "C" {
+#endif
+
+DEFINE_C_WRAP_STRUCT(TransactionDBOptions)
+DEFINE_C_WRAP_CONSTRUCTOR_DEC(TransactionDBOptions)
+DEFINE_C_WRAP_CONSTRUCTOR_DEFAULT_DEC(TransactionDBOptions)
+DEFINE_C_WRAP_DESTRUCTOR_DEC(TransactionDBOptions)
+// Get/Set methods
+DEFINE_C_WRAP_GETTER_DEC(TransactionDBOptions, max_num_locks, int64_t)
+DEFINE_C_WRAP_SETTER_DEC(TransactionDBOptions, max_num_locks, int64_t)
+DEFINE_C_WRAP_GETTER_DEC(TransactionDBOptions, num_stripes, size_t)
+DEFINE_C_WRAP_SETTER_DEC(TransactionDBOptions, num_stripes, size_t)
+DEFINE_C_WRAP_GETTER_DEC(TransactionDBOptions, transaction_lock_timeout, int64_t)
+DEFINE_C_WRAP_SETTER_DEC(TransactionDBOptions, transaction_lock_timeout, int64_t)
+DEFINE_C_WRAP_GETTER_DEC(TransactionDBOptions, default_lock_timeout, int64_t)
+DEFINE_C_WRAP_SETTER_DEC(TransactionDBOptions, default_lock_timeout, int64_t)
+
+DEFINE_C_WRAP_STRUCT(TransactionOptions)
+DEFINE_C_WRAP_CONSTRUCTOR_DEC(TransactionOptions)
+DEFINE_C_WRAP_CONSTRUCTOR_DEFAULT_DEC(TransactionOptions)
+DEFINE_C_WRAP_DESTRUCTOR_DEC(TransactionOptions)
+// Get/Set methods
+DEFINE_C_WRAP_GETTER_DEC(TransactionOptions, set_snapshot, bool)
+DEFINE_C_WRAP_SETTER_DEC(TransactionOptions, set_snapshot, bool)
+DEFINE_C_WRAP_GETTER_DEC(TransactionOptions, lock_timeout, int64_t)
+DEFINE_C_WRAP_SETTER_DEC(TransactionOptions, lock_timeout, int64_t)
+DEFINE_C_WRAP_GETTER_DEC(TransactionOptions, expiration, int64_t)
+DEFINE_C_WRAP_SETTER_DEC(TransactionOptions, expiration, int64_t)
+
+// A TransactionDB is a DB that supports pessimistic transactions.
+DEFINE_C_WRAP_STRUCT(TransactionDB)
+DEFINE_C_WRAP_CONSTRUCTOR_DEC(TransactionDB)
+DEFINE_C_WRAP_STATIC_CAST_DEC(TransactionDB, DB)
+
+// Open a TransactionDB similar to DB::Open().
+Status_t TransactionDBOpen(const Options_t* options,
+                           const TransactionDBOptions_t* txn_db_options,
+                           const String_t* name,
+                           TransactionDB_t* dbptr);
+
+// Open a TransactionDB with column families similar to DB::Open().
+Status_t TransactionDBOpenWithColumnFamilies(const Options_t* options,
+                                             const TransactionDBOptions_t* txn_db_options,
+                                             const String_t* name,
+                                             const ColumnFamilyDescriptor_t column_families[], const int size_col,
+                                             ColumnFamilyHandle_t **handles,
+                                             TransactionDB_t* dbptr);
+
+// Starts a new Transaction.  Passing set_snapshot=true has the same effect
+// as calling Transaction::SetSnapshot().
+//
+// Caller should delete the returned transaction after calling
+// Transaction::Commit() or Transaction::Rollback().
+Transaction_t TransactionDBBeginTransaction(TransactionDB_t* dbptr,
+                                            const WriteOptions_t* write_options,
+                                            const TransactionOptions_t* txn_options);
+
+#ifdef __cplusplus
+}  /* end extern "C" */
+#endif
+
+#endif  // GO_ROCKSDB_INCLUDE_TRANSACTION_DB_H_
@@ -10,10 +10,109 @@ DEFINE_C_WRAP_CONSTRUCTOR(LogFile)
 DEFINE_C_WRAP_DESTRUCTOR(LogFile)
 DEFINE_C_WRAP_DESTRUCTOR_ARRAY(LogFile)
 
+// Returns log file's pathname relative to the main db dir
+// Eg. For a live-log-file = /000003.log
+//     For an archived-log-file = /archive/000003.log
+String_t LogFilePathName(const LogFile_t* logfile)
+{
+    String ret;
+    if (logfile && GET_REP(logfile, LogFile))
+    {
+        ret = GET_REP(logfile, LogFile)->PathName();
+    }
+    return NewStringTCopy(&ret);
+}
+
+// Primary identifier for log file.
+// This is directly proportional to creation time of the log file
+uint64_t LogFileLogNumber(const LogFile_t* logfile)
+{
+    return ((logfile && GET_REP(logfile, LogFile)) ?
+            GET_REP(logfile, LogFile)->LogNumber() :
+            0);
+}
+
+// Log file can be either alive or archived
+int LogFileType(const LogFile_t* logfile)
+{
+    return ((logfile && GET_REP(logfile, LogFile)) ?
+            GET_REP(logfile, LogFile)->Type() :
+            kAliveLogFile);
+}
+
+// Starting sequence number of writebatch written in this log file
+SequenceNumber LogFileStartSequence(const LogFile_t* logfile)
+{
+    return ((logfile && GET_REP(logfile, LogFile)) ?
+            GET_REP(logfile, LogFile)->StartSequence() :
+            0);
+}
+
+// Size of log file on disk in Bytes
+uint64_t LogFileSizeFileBytes(const LogFile_t* logfile)
+{
+    return ((logfile && GET_REP(logfile, LogFile)) ?
+            GET_REP(logfile, LogFile)->SizeFileBytes() :
+            0);
+}
+
 DEFINE_C_WRAP_CONSTRUCTOR(TransactionLogIterator)
 DEFINE_C_WRAP_DESTRUCTOR(TransactionLogIterator)
 
+// An iterator is either positioned at a WriteBatch or not valid.
+// This method returns true if the iterator is valid.
+// Can read data from a valid iterator.
+bool TransactionLogIteratorValid(TransactionLogIterator_t* it)
+{
+    return ((it && GET_REP(it, TransactionLogIterator)) ?
+            GET_REP(it, TransactionLogIterator)->Valid() :
+            false);
+}
+
+// Moves the iterator to the next WriteBatch.
+// REQUIRES: Valid() to be true.
+void TransactionLogIteratorNext(TransactionLogIterator_t* it)
+{
+    if (it && GET_REP(it, TransactionLogIterator))
+    {
+        GET_REP(it, TransactionLogIterator)->Next();
+    }
+}
+
+// Returns ok if the iterator is valid.
+// Returns the Error when something has gone wrong.
+Status_t TransactionLogIteratorStatus(TransactionLogIterator_t* it)
+{
+    Status ret = ((it && GET_REP(it, TransactionLogIterator)) ?
+                  GET_REP(it, TransactionLogIterator)->status() :
+                  invalid_status);
+    return NewStatusTCopy(&ret);
+}
+
+// If valid return's the current write_batch and the sequence number of the
+// earliest transaction contained in the batch.
+// ONLY use if Valid() is true and status() is OK.
+// The returned write batch is owned by the caller.
+WriteBatch_t TransactionLogIteratorGetBatch(TransactionLogIterator_t* it, SequenceNumber* sequence)
+{
+    WriteBatch_t wrap_t;
+    wrap_t.rep = nullptr;
+    if (it && GET_REP(it, TransactionLogIterator))
+    {
+        BatchResult res = GET_REP(it, TransactionLogIterator)->GetBatch();
+        *sequence = res.sequence;
+        wrap_t.rep = res.writeBatchPtr.release();
+    }
+    return wrap_t;
+}
+
 DEFINE_C_WRAP_CONSTRUCTOR(TransactionLogIterator_ReadOptions)
 DEFINE_C_WRAP_CONSTRUCTOR_DEFAULT(TransactionLogIterator_ReadOptions)
 DEFINE_C_WRAP_CONSTRUCTOR_RAW_ARGS(TransactionLogIterator_ReadOptions, bool)
 DEFINE_C_WRAP_DESTRUCTOR(TransactionLogIterator_ReadOptions)
+
+// If true, all data read from underlying storage will be
+// verified against corresponding checksums.
+// Default: true
+DEFINE_C_WRAP_GETTER(TransactionLogIterator_ReadOptions, verify_checksums_, bool)
+DEFINE_C_WRAP_SETTER(TransactionLogIterator_ReadOptions, verify_checksums_, bool)
@@ -14,6 +14,22 @@ import (
 	"unsafe"
 )
 
+// Is WAL file archived or alive
+type WalFileType int
+
+const (
+	// Indicates that WAL file is in archive directory. WAL files are moved from
+	// the main db directory to archive directory once they are not live and stay
+	// there until cleaned up. Files are cleaned depending on archive size
+	// (Options::WAL_size_limit_MB) and time since last cleaning
+	// (Options::WAL_ttl_seconds).
+	ArchivedLogFile WalFileType = 0
+
+	// Indicates that WAL file is live and resides in the main db directory
+	AliveLogFile WalFileType = 1
+)
+
+// A WAL file of the db
 type LogFile struct {
 	logf C.LogFile_t
 }
@@ -34,26 +50,179 @@ func newLogFileArrayFromCArray(clogfs *C.LogFile_t, sz uint) (logfs []*LogFile)
 	return
 }
 
+// Returns log file's pathname relative to the main db dir
+// Eg. For a live-log-file = /000003.log
+//     For an archived-log-file = /archive/000003.log
+func (logf *LogFile) PathName() string {
+	var (
+		clogf *C.LogFile_t = &logf.logf
+		cname C.String_t = C.LogFilePathName(clogf)
+	)
+	return cname.cToString()
+}
+
+// Primary identifier for log file.
+// This is directly proportional to creation time of the log file
+func (logf *LogFile) LogNumber() uint64 {
+	var clogf *C.LogFile_t = &logf.logf
+	return uint64(C.LogFileLogNumber(clogf))
+}
+
+// Log file can be either alive or archived
+func (logf *LogFile) Type() WalFileType {
+	var clogf *C.LogFile_t = &logf.logf
+	return WalFileType(C.LogFileType(clogf))
+}
+
+// Starting sequence number of writebatch written in this log file
+func (logf *LogFile) StartSequence() SequenceNumber {
+	var clogf *C.LogFile_t = &logf.logf
+	return SequenceNumber(C.LogFileStartSequence(clogf))
+}
+
+// Size of log file on disk in Bytes
+func (logf *LogFile) SizeFileBytes() uint64 {
+	var clogf *C.LogFile_t = &logf.logf
+	return uint64(C.LogFileSizeFileBytes(clogf))
+}
+
+// A TransactionLogIterator is used to iterate over the transactions in a db.
+// One run of the iterator is continuous, i.e. the iterator will stop at the
+// beginning of any gap in sequences
 type TransactionLogIterator struct {
 	tranit C.TransactionLogIterator_t
+	// true if the underlying c object is deleted
+	closed bool
 }
 
+// Release resources
 func (tranit *TransactionLogIterator) finalize() {
-	var ctranit *C.TransactionLogIterator_t = &tranit.tranit
-	C.DeleteTransactionLogIteratorT(ctranit, toCBool(false))
+	if !tranit.closed {
+		tranit.closed = true
+		var ctranit *C.TransactionLogIterator_t = &tranit.tranit
+		C.DeleteTransactionLogIteratorT(ctranit, toCBool(false))
+	}
+}
+
+// Close the TransactionLogIterator
+func (tranit *TransactionLogIterator) Close() {
+	runtime.SetFinalizer(tranit, nil)
+	tranit.finalize()
 }
 
+// C TransactionLogIterator to go TransactionLogIterator
 func (ctranit *C.TransactionLogIterator_t) toTransactionLogIterator() (tranit *TransactionLogIterator) {
 	tranit = &TransactionLogIterator{tranit: *ctranit}
 	runtime.SetFinalizer(tranit, finalize)
 	return
 }
 
+// An iterator is either positioned at a WriteBatch or not valid.
+// This method returns true if the iterator is valid.
+// Can read data from a valid iterator.
+func (tranit *TransactionLogIterator) Valid() bool {
+	if tranit.closed {
+		return false
+	}
+
+	var ctranit *C.TransactionLogIterator_t = &tranit.tranit
+	return C.TransactionLogIteratorValid(ctranit).toBool()
+}
+
+// Moves the iterator to the next WriteBatch.
+// REQUIRES: Valid() to be true.
+func (tranit *TransactionLogIterator) Next() {
+	if tranit.closed {
+		return
+	}
+
+	var ctranit *C.TransactionLogIterator_t = &tranit.tranit
+	C.TransactionLogIteratorNext(ctranit)
+}
+
+// Returns ok if the iterator is valid.
+// Returns the Error when something has gone wrong.
+func (tranit *TransactionLogIterator) Status() (stat *Status) {
+	if tranit.closed {
+		stat = NewDBClosedStatus()
+		return
+	}
+
+	var ctranit *C.TransactionLogIterator_t = &tranit.tranit
+	cstat := C.TransactionLogIteratorStatus(ctranit)
+	stat = cstat.toStatus()
+	return
+}
+
+// The WriteBatch read from the log together with the sequence
+// number of the earliest transaction contained in the batch.
+type BatchResult struct {
+	Sequence SequenceNumber
+	Batch *WriteBatch
+}
+
+// If valid return's the current write_batch and the sequence number of the
+// earliest transaction contained in the batch.
+// ONLY use if Valid() is true and Status() is OK.
+func (tranit *TransactionLogIterator) GetBatch() (res BatchResult) {
+	if tranit.closed {
+		return
+	}
+
+	var (
+		ctranit *C.TransactionLogIterator_t = &tranit.tranit
+		csqn C.SequenceNumber
+	)
+
+	cwbt := C.TransactionLogIteratorGetBatch(ctranit, &csqn)
+	res.Sequence = SequenceNumber(csqn)
+	res.Batch = cwbt.toWriteBatch()
+	return
+}
+
+// Options to control the behavior of GetUpdatesSince
 type TransactionLogIteratorReadOptions struct {
 	tranropt C.TransactionLogIterator_ReadOptions_t
 }
 
+// Create TransactionLogIteratorReadOptions. If verifyChecksums is
+// true (the default), all data read from underlying storage will be
+// verified against corresponding checksums.
+func NewTransactionLogIteratorReadOptions(verifyChecksums ...bool) (tranropt *TransactionLogIteratorReadOptions) {
+	var ctranropt C.TransactionLogIterator_ReadOptions_t
+	if len(verifyChecksums) > 0 {
+		ctranropt = C.NewTransactionLogIterator_ReadOptionsTRawArgs(toCBool(verifyChecksums[0]))
+	} else {
+		ctranropt = C.NewTransactionLogIterator_ReadOptionsTDefault()
+	}
+	tranropt = &TransactionLogIteratorReadOptions{tranropt: ctranropt}
+	runtime.SetFinalizer(tranropt, finalize)
+	return
+}
+
+// Release resources
 func (tranropt *TransactionLogIteratorReadOptions) finalize() {
 	var ctranropt *C.TransactionLogIterator_ReadOptions_t = &tranropt.tranropt
 	C.DeleteTransactionLogIterator_ReadOptionsT(ctranropt, toCBool(false))
 }
+
+// Close the TransactionLogIteratorReadOptions
+func (tranropt *TransactionLogIteratorReadOptions) Close() {
+	runtime.SetFinalizer(tranropt, nil)
+	tranropt.finalize()
+}
+
+// If true, all data read from underlying storage will be
+// verified against corresponding checksums.
+// Default: true
+func (tranropt *TransactionLogIteratorReadOptions) SetVerifyChecksums(val bool) {
+	var ctranropt *C.TransactionLogIterator_ReadOptions_t = &tranropt.tranropt
+	C.TransactionLogIterator_ReadOptions_set_verify_checksums_(ctranropt, toCBool(val))
+}
+
+// Whether all data read from underlying storage will be
+// verified against corresponding checksums.
+func (tranropt *TransactionLogIteratorReadOptions) VerifyChecksums() bool {
+	var ctranropt *C.TransactionLogIterator_ReadOptions_t = &tranropt.tranropt
+	return C.TransactionLogIterator_ReadOptions_get_verify_checksums_(ctranropt).toBool()
+}
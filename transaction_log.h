@@ -10,6 +10,9 @@
 #endif
 
 #include "types.h"
+#include "status.h"
+#include "write_batch.h"
+#include "cstring.h"
 
 #ifdef __cplusplus
 typedef rocksdb::TransactionLogIterator::ReadOptions TransactionLogIterator_ReadOptions;
@@ -23,16 +26,28 @@ DEFINE_C_WRAP_STRUCT(LogFile)
 DEFINE_C_WRAP_CONSTRUCTOR_DEC(LogFile)
 DEFINE_C_WRAP_DESTRUCTOR_DEC(LogFile)
 DEFINE_C_WRAP_DESTRUCTOR_ARRAY_DEC(LogFile)
+String_t LogFilePathName(const LogFile_t* logfile);
+uint64_t LogFileLogNumber(const LogFile_t* logfile);
+int LogFileType(const LogFile_t* logfile);
+SequenceNumber LogFileStartSequence(const LogFile_t* logfile);
+uint64_t LogFileSizeFileBytes(const LogFile_t* logfile);
 
 DEFINE_C_WRAP_STRUCT(TransactionLogIterator)
 DEFINE_C_WRAP_CONSTRUCTOR_DEC(TransactionLogIterator)
 DEFINE_C_WRAP_DESTRUCTOR_DEC(TransactionLogIterator)
+bool TransactionLogIteratorValid(TransactionLogIterator_t* it);
+void TransactionLogIteratorNext(TransactionLogIterator_t* it);
+Status_t TransactionLogIteratorStatus(TransactionLogIterator_t* it);
+WriteBatch_t TransactionLogIteratorGetBatch(TransactionLogIterator_t* it, SequenceNumber* sequence);
 
 DEFINE_C_WRAP_STRUCT(TransactionLogIterator_ReadOptions)
 DEFINE_C_WRAP_CONSTRUCTOR_DEC(TransactionLogIterator_ReadOptions)
 DEFINE_C_WRAP_CONSTRUCTOR_DEFAULT_DEC(TransactionLogIterator_ReadOptions)
 DEFINE_C_WRAP_CONSTRUCTOR_RAW_ARGS_DEC(TransactionLogIterator_ReadOptions, bool)
 DEFINE_C_WRAP_DESTRUCTOR_DEC(TransactionLogIterator_ReadOptions)
+// Get/Set methods
+DEFINE_C_WRAP_GETTER_DEC(TransactionLogIterator_ReadOptions, verify_checksums_, bool)
+DEFINE_C_WRAP_SETTER_DEC(TransactionLogIterator_ReadOptions, verify_checksums_, bool)
 
 #ifdef __cplusplus
 }  /* end extern "C" */
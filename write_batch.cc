@@ -24,6 +24,10 @@
 
 using namespace rocksdb;
 
+extern "C" {
+#include "_cgo_export.h"
+}
+
 DEFINE_C_WRAP_CONSTRUCTOR(WriteBatch)
 DEFINE_C_WRAP_DESTRUCTOR(WriteBatch)
 DEFINE_C_WRAP_CONSTRUCTOR_COPY(WriteBatch)
@@ -127,8 +131,10 @@ size_t WriteBatchGetDataSize(const WriteBatch_t* write_batch)
     if (write_batch)
     {
         assert(GET_REP(write_batch, WriteBatch) != NULL);
-        GET_REP(write_batch, WriteBatch)->GetDataSize();
+        return GET_REP(write_batch, WriteBatch)->GetDataSize();
     }
+
+    return 0;
 }
 
 // Returns the number of updates in the batch
@@ -137,8 +143,10 @@ int WriteBatchCount(const WriteBatch_t* write_batch)
     if (write_batch)
     {
         assert(GET_REP(write_batch, WriteBatch) != NULL);
-        GET_REP(write_batch, WriteBatch)->Count();
+        return GET_REP(write_batch, WriteBatch)->Count();
     }
+
+    return 0;
 }
 
 WriteBatch_t NewWriteBatchTRawArgs(const String_t* str)
@@ -157,3 +165,106 @@ WriteBatch_t NewWriteBatchTRawArgs(const String_t* str)
 
     return wrap;
 }
+
+// C++ wrap class for go IWriteBatchHandler
+class WriteBatchHandlerGo : public WriteBatch::Handler {
+public:
+    WriteBatchHandlerGo(void* go_handler)
+        : m_go_handler(go_handler)
+    {
+    }
+
+    // Destructor
+    ~WriteBatchHandlerGo()
+    {
+        if (m_go_handler)
+        {
+            InterfacesRemoveReference(m_go_handler);
+        }
+    }
+
+    virtual Status PutCF(uint32_t column_family_id, const Slice& key,
+                         const Slice& value) override
+    {
+        if (m_go_handler)
+        {
+            Slice_t key_slc{const_cast<Slice *>(&key)};
+            Slice_t value_slc{const_cast<Slice *>(&value)};
+            if (0 == column_family_id)
+            {
+                IWriteBatchHandlerPut(m_go_handler, &key_slc, &value_slc);
+            }
+            else
+            {
+                IWriteBatchHandlerPutCF(m_go_handler, column_family_id, &key_slc, &value_slc);
+            }
+        }
+        return Status::OK();
+    }
+
+    virtual Status MergeCF(uint32_t column_family_id, const Slice& key,
+                           const Slice& value) override
+    {
+        if (m_go_handler)
+        {
+            Slice_t key_slc{const_cast<Slice *>(&key)};
+            Slice_t value_slc{const_cast<Slice *>(&value)};
+            if (0 == column_family_id)
+            {
+                IWriteBatchHandlerMerge(m_go_handler, &key_slc, &value_slc);
+            }
+            else
+            {
+                IWriteBatchHandlerMergeCF(m_go_handler, column_family_id, &key_slc, &value_slc);
+            }
+        }
+        return Status::OK();
+    }
+
+    virtual Status DeleteCF(uint32_t column_family_id, const Slice& key) override
+    {
+        if (m_go_handler)
+        {
+            Slice_t key_slc{const_cast<Slice *>(&key)};
+            if (0 == column_family_id)
+            {
+                IWriteBatchHandlerDelete(m_go_handler, &key_slc);
+            }
+            else
+            {
+                IWriteBatchHandlerDeleteCF(m_go_handler, column_family_id, &key_slc);
+            }
+        }
+        return Status::OK();
+    }
+
+    // A single deletion is reported as a deletion
+    virtual Status SingleDeleteCF(uint32_t column_family_id, const Slice& key) override
+    {
+        return DeleteCF(column_family_id, key);
+    }
+
+    // The default implementation of LogData does nothing.
+    virtual void LogData(const Slice& blob) override
+    {
+        if (m_go_handler)
+        {
+            Slice_t blob_slc{const_cast<Slice *>(&blob)};
+            IWriteBatchHandlerLogData(m_go_handler, &blob_slc);
+        }
+    }
+
+private:
+    // Wrapped go IWriteBatchHandler
+    void* m_go_handler;
+};
+
+// Iterate the updates in the batch with a go IWriteBatchHandler
+Status_t WriteBatchIterate(const WriteBatch_t* write_batch, void* go_handler)
+{
+    WriteBatchHandlerGo handler(go_handler);
+    Status ret = ((write_batch && GET_REP(write_batch, WriteBatch)) ?
+                  GET_REP(write_batch, WriteBatch)->Iterate(&handler) :
+                  invalid_status);
+    return NewStatusTCopy(&ret);
+}
@@ -28,6 +28,7 @@ import "C"
 import (
 	"runtime"
 	"sync"
+	"unsafe"
 )
 
 // Go WriteBatch
@@ -71,6 +72,13 @@ func NewWriteBatchFromBytes(bytes []byte) *WriteBatch {
 	return wbt
 }
 
+// C write batch to go write batch
+func (cwbt *C.WriteBatch_t) toWriteBatch() (wbt *WriteBatch) {
+	wbt = &WriteBatch{wbt: *cwbt, mutex: sync.Mutex{}}
+	runtime.SetFinalizer(wbt, finalize)
+	return
+}
+
 // Store the mapping "key->value" in the database.
 func (wbt *WriteBatch) Put(key []byte, val []byte, cfh ...*ColumnFamilyHandle) {
 	if wbt.closed {
@@ -234,3 +242,95 @@ func (wbt *WriteBatch) Count() uint64 {
 
 	return uint64(cnt)
 }
+
+// Support for iterating over the contents of a batch.
+// Updates to the default column family are reported by Put, Merge and
+// Delete, updates to the other column families by PutCF, MergeCF and
+// DeleteCF with the ID of the column family.
+type IWriteBatchHandler interface {
+	// Called for a Put to the default column family
+	Put(key, val []byte)
+
+	// Called for a Put to the column family cfid
+	PutCF(cfid uint32, key, val []byte)
+
+	// Called for a Merge to the default column family
+	Merge(key, val []byte)
+
+	// Called for a Merge to the column family cfid
+	MergeCF(cfid uint32, key, val []byte)
+
+	// Called for a Delete or SingleDelete to the default column family
+	Delete(key []byte)
+
+	// Called for a Delete or SingleDelete to the column family cfid
+	DeleteCF(cfid uint32, key []byte)
+
+	// Called for a blob of arbitrary data added to the batch
+	LogData(blob []byte)
+}
+
+// Wrap functions for IWriteBatchHandler
+
+//export IWriteBatchHandlerPut
+func IWriteBatchHandlerPut(chandler unsafe.Pointer, key, val *C.Slice_t) {
+	handler := InterfacesGet(chandler).(IWriteBatchHandler)
+	handler.Put(key.cToBytes(false), val.cToBytes(false))
+}
+
+//export IWriteBatchHandlerPutCF
+func IWriteBatchHandlerPutCF(chandler unsafe.Pointer, cfid C.uint32_t, key, val *C.Slice_t) {
+	handler := InterfacesGet(chandler).(IWriteBatchHandler)
+	handler.PutCF(uint32(cfid), key.cToBytes(false), val.cToBytes(false))
+}
+
+//export IWriteBatchHandlerMerge
+func IWriteBatchHandlerMerge(chandler unsafe.Pointer, key, val *C.Slice_t) {
+	handler := InterfacesGet(chandler).(IWriteBatchHandler)
+	handler.Merge(key.cToBytes(false), val.cToBytes(false))
+}
+
+//export IWriteBatchHandlerMergeCF
+func IWriteBatchHandlerMergeCF(chandler unsafe.Pointer, cfid C.uint32_t, key, val *C.Slice_t) {
+	handler := InterfacesGet(chandler).(IWriteBatchHandler)
+	handler.MergeCF(uint32(cfid), key.cToBytes(false), val.cToBytes(false))
+}
+
+//export IWriteBatchHandlerDelete
+func IWriteBatchHandlerDelete(chandler unsafe.Pointer, key *C.Slice_t) {
+	handler := InterfacesGet(chandler).(IWriteBatchHandler)
+	handler.Delete(key.cToBytes(false))
+}
+
+//export IWriteBatchHandlerDeleteCF
+func IWriteBatchHandlerDeleteCF(chandler unsafe.Pointer, cfid C.uint32_t, key *C.Slice_t) {
+	handler := InterfacesGet(chandler).(IWriteBatchHandler)
+	handler.DeleteCF(uint32(cfid), key.cToBytes(false))
+}
+
+//export IWriteBatchHandlerLogData
+func IWriteBatchHandlerLogData(chandler unsafe.Pointer, blob *C.Slice_t) {
+	handler := InterfacesGet(chandler).(IWriteBatchHandler)
+	handler.LogData(blob.cToBytes(false))
+}
+
+// Walk the updates in the batch in order, calling the method of
+// handler matching each record.
+func (wbt *WriteBatch) Iterate(handler IWriteBatchHandler) (stat *Status) {
+	if wbt.closed {
+		stat = NewDBClosedStatus()
+		return
+	}
+
+	var (
+		cwbt *C.WriteBatch_t = &wbt.wbt
+		chandler unsafe.Pointer = InterfacesAddReference(handler)
+	)
+
+	defer wbt.mutex.Unlock()
+	wbt.mutex.Lock()
+
+	cstat := C.WriteBatchIterate(cwbt, chandler)
+	stat = cstat.toStatus()
+	return
+}
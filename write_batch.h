@@ -24,7 +24,7 @@
 #include "columnFamilyHandle.h"
 #include "slice.h"
 #include "cstring.h"
-
+#include "status.h"
 
 #ifdef __cplusplus
 extern "C" {
@@ -61,6 +61,9 @@ int WriteBatchCount(const WriteBatch_t* write_batch);
 
 WriteBatch_t NewWriteBatchTRawArgs(const String_t* str);
 
+// Iterate the updates in the batch with a go IWriteBatchHandler
+Status_t WriteBatchIterate(const WriteBatch_t* write_batch, void* go_handler);
+
 #ifdef __cplusplus
 }  /* end extern "C" */
 #endif
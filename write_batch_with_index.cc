@@ -0,0 +1,236 @@
+// Copyright (c) 2015, Dean ChaoJun Pan.  All rights reserved.
+// This source code is licensed under the BSD-style license found in the
+// LICENSE file in the root directory of this source tree.
+//
+// A WriteBatchWithIndex with a binary searchable index built for all the keys
+// inserted.
+
+#include <rocksdb/utilities/write_batch_with_index.h>
+
+using namespace rocksdb;
+
+#include "write_batch_with_index.h"
+
+DEFINE_C_WRAP_CONSTRUCTOR(WriteBatchWithIndex)
+DEFINE_C_WRAP_DESTRUCTOR(WriteBatchWithIndex)
+
+// backup_index_comparator: the backup comparator used to compare keys
+// within the same column family, if column family is not given in the
+// interface, or we can't find a column family from the column family handle
+// passed in, backup_index_comparator will be used for the column family.
+// reserved_bytes: reserved bytes in underlying WriteBatch
+// overwrite_key: if true, overwrite the key in the index when inserting
+//                the same key as previously, so iterator will never
+//                show two entries with the same key.
+WriteBatchWithIndex_t NewWriteBatchWithIndexTArgs(const Comparator_t* backup_index_comparator,
+                                                  size_t reserved_bytes,
+                                                  bool overwrite_key)
+{
+    WriteBatchWithIndex_t wrap_t;
+    wrap_t.rep = new WriteBatchWithIndex((backup_index_comparator && GET_REP(backup_index_comparator, Comparator)) ?
+                                         GET_REP(backup_index_comparator, Comparator) :
+                                         BytewiseComparator(),
+                                         reserved_bytes,
+                                         overwrite_key);
+    return wrap_t;
+}
+
+// Store the mapping "key->value" in the batch.
+void WriteBatchWithIndexPutWithColumnFamily(WriteBatchWithIndex_t* wbwi,
+                                            const ColumnFamilyHandle_t* column_family,
+                                            const Slice_t* key, const Slice_t* value)
+{
+    if (wbwi)
+    {
+        assert(GET_REP(wbwi, WriteBatchWithIndex) != NULL);
+        assert(GET_REP(column_family, ColumnFamilyHandle) != NULL);
+        GET_REP(wbwi, WriteBatchWithIndex)->Put(GET_REP(column_family, ColumnFamilyHandle), GET_REP_REF(key, Slice), GET_REP_REF(value, Slice));
+    }
+}
+
+void WriteBatchWithIndexPut(WriteBatchWithIndex_t* wbwi,
+                            const Slice_t* key, const Slice_t* value)
+{
+    if (wbwi)
+    {
+        assert(GET_REP(wbwi, WriteBatchWithIndex) != NULL);
+        GET_REP(wbwi, WriteBatchWithIndex)->Put(GET_REP_REF(key, Slice), GET_REP_REF(value, Slice));
+    }
+}
+
+// Merge "value" with the existing value of "key" in the batch.
+void WriteBatchWithIndexMergeWithColumnFamily(WriteBatchWithIndex_t* wbwi,
+                                              const ColumnFamilyHandle_t* column_family,
+                                              const Slice_t* key, const Slice_t* value)
+{
+    if (wbwi)
+    {
+        assert(GET_REP(wbwi, WriteBatchWithIndex) != NULL);
+        assert(GET_REP(column_family, ColumnFamilyHandle) != NULL);
+        GET_REP(wbwi, WriteBatchWithIndex)->Merge(GET_REP(column_family, ColumnFamilyHandle), GET_REP_REF(key, Slice), GET_REP_REF(value, Slice));
+    }
+}
+
+void WriteBatchWithIndexMerge(WriteBatchWithIndex_t* wbwi,
+                              const Slice_t* key, const Slice_t* value)
+{
+    if (wbwi)
+    {
+        assert(GET_REP(wbwi, WriteBatchWithIndex) != NULL);
+        GET_REP(wbwi, WriteBatchWithIndex)->Merge(GET_REP_REF(key, Slice), GET_REP_REF(value, Slice));
+    }
+}
+
+// If the database contains a mapping for "key", erase it.  Else do nothing.
+void WriteBatchWithIndexDeleteWithColumnFamily(WriteBatchWithIndex_t* wbwi,
+                                               const ColumnFamilyHandle_t* column_family,
+                                               const Slice_t* key)
+{
+    if (wbwi)
+    {
+        assert(GET_REP(wbwi, WriteBatchWithIndex) != NULL);
+        assert(GET_REP(column_family, ColumnFamilyHandle) != NULL);
+        GET_REP(wbwi, WriteBatchWithIndex)->Delete(GET_REP(column_family, ColumnFamilyHandle), GET_REP_REF(key, Slice));
+    }
+}
+
+void WriteBatchWithIndexDelete(WriteBatchWithIndex_t* wbwi, const Slice_t* key)
+{
+    if (wbwi)
+    {
+        assert(GET_REP(wbwi, WriteBatchWithIndex) != NULL);
+        GET_REP(wbwi, WriteBatchWithIndex)->Delete(GET_REP_REF(key, Slice));
+    }
+}
+
+// Clear all updates buffered in this batch.
+void WriteBatchWithIndexClear(WriteBatchWithIndex_t* wbwi)
+{
+    if (wbwi)
+    {
+        assert(GET_REP(wbwi, WriteBatchWithIndex) != NULL);
+        GET_REP(wbwi, WriteBatchWithIndex)->Clear();
+    }
+}
+
+// Return a copy of the WriteBatch holding the updates in the batch
+WriteBatch_t WriteBatchWithIndexGetWriteBatch(WriteBatchWithIndex_t* wbwi)
+{
+    assert(wbwi != NULL);
+    assert(GET_REP(wbwi, WriteBatchWithIndex) != NULL);
+    return NewWriteBatchTCopy(GET_REP(wbwi, WriteBatchWithIndex)->GetWriteBatch());
+}
+
+// Provides Read-Your-Own-Writes like functionality by creating a new
+// Iterator that will use WBWIIterator as a delta and base_iterator as
+// base. The returned iterator owns base_iterator.
+Iterator_t WriteBatchWithIndexNewIteratorWithBaseWithColumnFamily(WriteBatchWithIndex_t* wbwi,
+                                                                  ColumnFamilyHandle_t* column_family,
+                                                                  Iterator_t* base_iterator)
+{
+    assert(wbwi != NULL);
+    assert(GET_REP(wbwi, WriteBatchWithIndex) != NULL);
+    assert(GET_REP(column_family, ColumnFamilyHandle) != NULL);
+    assert(GET_REP(base_iterator, Iterator) != NULL);
+    return NewIteratorT(GET_REP(wbwi, WriteBatchWithIndex)->NewIteratorWithBase(GET_REP(column_family, ColumnFamilyHandle), GET_REP(base_iterator, Iterator)));
+}
+
+Iterator_t WriteBatchWithIndexNewIteratorWithBase(WriteBatchWithIndex_t* wbwi,
+                                                  Iterator_t* base_iterator)
+{
+    assert(wbwi != NULL);
+    assert(GET_REP(wbwi, WriteBatchWithIndex) != NULL);
+    assert(GET_REP(base_iterator, Iterator) != NULL);
+    return NewIteratorT(GET_REP(wbwi, WriteBatchWithIndex)->NewIteratorWithBase(GET_REP(base_iterator, Iterator)));
+}
+
+// Similar to DB::Get() but will only read the key from this batch.
+// If the batch does not have enough data to resolve Merge operations,
+// MergeInProgress status may be returned.
+Status_t WriteBatchWithIndexGetFromBatchWithColumnFamily(WriteBatchWithIndex_t* wbwi,
+                                                         ColumnFamilyHandle_t* column_family,
+                                                         const DBOptions_t* options,
+                                                         const Slice_t* key,
+                                                         String_t* value)
+{
+    Status ret;
+    if (wbwi && GET_REP(wbwi, WriteBatchWithIndex))
+    {
+        assert(GET_REP(column_family, ColumnFamilyHandle) != NULL);
+        assert(GET_REP(options, DBOptions) != NULL);
+        assert(GET_REP(key, Slice) != NULL);
+        assert(GET_REP(value, String) != NULL);
+        ret = GET_REP(wbwi, WriteBatchWithIndex)->GetFromBatch(GET_REP(column_family, ColumnFamilyHandle), GET_REP_REF(options, DBOptions), GET_REP_REF(key, Slice), GET_REP(value, String));
+    }
+    else
+        ret = invalid_status;
+    return NewStatusTCopy(&ret);
+}
+
+Status_t WriteBatchWithIndexGetFromBatch(WriteBatchWithIndex_t* wbwi,
+                                         const DBOptions_t* options,
+                                         const Slice_t* key,
+                                         String_t* value)
+{
+    Status ret;
+    if (wbwi && GET_REP(wbwi, WriteBatchWithIndex))
+    {
+        assert(GET_REP(options, DBOptions) != NULL);
+        assert(GET_REP(key, Slice) != NULL);
+        assert(GET_REP(value, String) != NULL);
+        ret = GET_REP(wbwi, WriteBatchWithIndex)->GetFromBatch(GET_REP_REF(options, DBOptions), GET_REP_REF(key, Slice), GET_REP(value, String));
+    }
+    else
+        ret = invalid_status;
+    return NewStatusTCopy(&ret);
+}
+
+// Similar to DB::Get() but will also read writes from this batch.
+//
+// This function will query both this batch and the DB and then merge
+// the results using the DB's merge operator (if the batch contains any
+// merge requests).
+//
+// Setting read_options.snapshot will affect what is read from the DB
+// but will NOT change which keys are read from the batch (the keys in
+// this batch do not yet belong to any snapshot and will be fetched
+// regardless).
+Status_t WriteBatchWithIndexGetFromBatchAndDBWithColumnFamily(WriteBatchWithIndex_t* wbwi,
+                                                              DB_t* db,
+                                                              const ReadOptions_t* read_options,
+                                                              ColumnFamilyHandle_t* column_family,
+                                                              const Slice_t* key,
+                                                              String_t* value)
+{
+    Status ret;
+    if (wbwi && GET_REP(wbwi, WriteBatchWithIndex) && db && GET_REP(db, DB))
+    {
+        assert(GET_REP(read_options, ReadOptions) != NULL);
+        assert(GET_REP(column_family, ColumnFamilyHandle) != NULL);
+        assert(GET_REP(key, Slice) != NULL);
+        assert(GET_REP(value, String) != NULL);
+        ret = GET_REP(wbwi, WriteBatchWithIndex)->GetFromBatchAndDB(GET_REP(db, DB), GET_REP_REF(read_options, ReadOptions), GET_REP(column_family, ColumnFamilyHandle), GET_REP_REF(key, Slice), GET_REP(value, String));
+    }
+    else
+        ret = invalid_status;
+    return NewStatusTCopy(&ret);
+}
+
+Status_t WriteBatchWithIndexGetFromBatchAndDB(WriteBatchWithIndex_t* wbwi,
+                                              DB_t* db,
+                                              const ReadOptions_t* read_options,
+                                              const Slice_t* key,
+                                              String_t* value)
+{
+    Status ret;
+    if (wbwi && GET_REP(wbwi, WriteBatchWithIndex) && db && GET_REP(db, DB))
+    {
+        assert(GET_REP(read_options, ReadOptions) != NULL);
+        assert(GET_REP(key, Slice) != NULL);
+        assert(GET_REP(value, String) != NULL);
+        ret = GET_REP(wbwi, WriteBatchWithIndex)->GetFromBatchAndDB(GET_REP(db, DB), GET_REP_REF(read_options, ReadOptions), GET_REP_REF(key, Slice), GET_REP(value, String));
+    }
+    else
+        ret = invalid_status;
+    return NewStatusTCopy(&ret);
+}
@@ -0,0 +1,366 @@
+// Copyright (c) 2015, Dean ChaoJun Pan.  All rights reserved.
+// This source code is licensed under the BSD-style license found in the
+// LICENSE file in the root directory of this source tree.
+//
+// A WriteBatchWithIndex with a binary searchable index built for all the keys
+// inserted.
+
+package rocksdb
+
+/*
+#include "write_batch_with_index.h"
+*/
+import "C"
+
+import (
+	"runtime"
+	"sync"
+)
+
+// A WriteBatchWithIndex with a binary searchable index built for all the keys
+// inserted.
+// In Put(), Merge() or Delete(), the same function of the wrapped will be
+// called. At the same time, indexes will be built.
+// By calling GetWriteBatch(), a WriteBatch holding the updates can be
+// written to the DB.
+// GetFromBatch, GetFromBatchAndDB and NewIteratorWithBase read the keys
+// back, so a large update built in a batch can see its own writes before
+// it is written.
+type WriteBatchWithIndex struct {
+	wbwi C.WriteBatchWithIndex_t
+	// Keep cmp from garbage collected
+	cmp *Comparator
+	// The overwriteKey the batch is created with
+	overwriteKey bool
+	// Thread safe
+	mutex sync.Mutex
+	// Map of the iterators created by NewIteratorWithBase to close
+	// before the batch is closed
+	itmap map[*Iterator]bool
+	// Mutext to protect itmap
+	itmapmtx sync.Mutex
+	// true if the underlying c object is deleted
+	closed bool
+}
+
+// Add it to itmap
+func (wbwi *WriteBatchWithIndex) addToItmap(it *Iterator) {
+	defer wbwi.itmapmtx.Unlock()
+	wbwi.itmapmtx.Lock()
+	if nil == wbwi.itmap {
+		wbwi.itmap = make(map[*Iterator]bool, initialMapSize)
+	}
+	wbwi.itmap[it] = true
+}
+
+// Remove it from itmap
+func (wbwi *WriteBatchWithIndex) removeFromItmap(it *Iterator) {
+	defer wbwi.itmapmtx.Unlock()
+	wbwi.itmapmtx.Lock()
+	delete(wbwi.itmap, it)
+}
+
+// Release resources
+func (wbwi *WriteBatchWithIndex) finalize() {
+	if !wbwi.closed {
+		wbwi.closed = true
+
+		// Close all the opened Iterators reading the batch
+		for k, _ := range wbwi.itmap {
+			k.Close()
+		}
+
+		var cwbwi *C.WriteBatchWithIndex_t = &wbwi.wbwi
+		C.DeleteWriteBatchWithIndexT(cwbwi, toCBool(false))
+	}
+}
+
+// Close the WriteBatchWithIndex. The iterators created by
+// NewIteratorWithBase that are still open are closed first.
+func (wbwi *WriteBatchWithIndex) Close() {
+	runtime.SetFinalizer(wbwi, nil)
+	wbwi.finalize()
+}
+
+// Create a WriteBatchWithIndex.
+// cmp: the backup comparator used to compare keys within the same column
+// family, if column family is not given in the interface, or we can't find
+// a column family from the column family handle passed in, cmp will be
+// used for the column family. A byte-wise comparator is used if cmp is nil.
+// reservedBytes: reserved bytes in underlying WriteBatch
+// overwriteKey: if true, overwrite the key in the index when inserting
+// the same key as previously, so iterator will never show two entries
+// with the same key. Must be true to use NewIteratorWithBase.
+func NewWriteBatchWithIndex(cmp *Comparator, reservedBytes uint64, overwriteKey bool) (wbwi *WriteBatchWithIndex) {
+	var ccmp *C.Comparator_t
+
+	if nil != cmp {
+		ccmp = &cmp.cmp
+	}
+
+	wbwi = &WriteBatchWithIndex{wbwi: C.NewWriteBatchWithIndexTArgs(ccmp, C.size_t(reservedBytes), toCBool(overwriteKey)), cmp: cmp, overwriteKey: overwriteKey, mutex: sync.Mutex{}}
+	runtime.SetFinalizer(wbwi, finalize)
+	return
+}
+
+// Store the mapping "key->value" in the batch.
+func (wbwi *WriteBatchWithIndex) Put(key []byte, val []byte, cfh ...*ColumnFamilyHandle) {
+	if wbwi.closed {
+		return
+	}
+
+	ckey := newSliceFromBytes(key)
+	defer ckey.del()
+	cval := newSliceFromBytes(val)
+	defer cval.del()
+
+	var (
+		cwbwi *C.WriteBatchWithIndex_t = &wbwi.wbwi
+		ccfh *C.ColumnFamilyHandle_t
+		cckey *C.Slice_t = &ckey.slc
+		ccval *C.Slice_t = &cval.slc
+	)
+
+	if cfh != nil {
+		ccfh = &cfh[0].cfh
+	}
+
+	defer wbwi.mutex.Unlock()
+	wbwi.mutex.Lock()
+	if ccfh != nil {
+		C.WriteBatchWithIndexPutWithColumnFamily(cwbwi, ccfh, cckey, ccval)
+	} else {
+		C.WriteBatchWithIndexPut(cwbwi, cckey, ccval)
+	}
+}
+
+// Merge "value" with the existing value of "key" in the batch.
+// "key->merge(existing, value)"
+func (wbwi *WriteBatchWithIndex) Merge(key []byte, val []byte, cfh ...*ColumnFamilyHandle) {
+	if wbwi.closed {
+		return
+	}
+
+	ckey := newSliceFromBytes(key)
+	defer ckey.del()
+	cval := newSliceFromBytes(val)
+	defer cval.del()
+
+	var (
+		cwbwi *C.WriteBatchWithIndex_t = &wbwi.wbwi
+		ccfh *C.ColumnFamilyHandle_t
+		cckey *C.Slice_t = &ckey.slc
+		ccval *C.Slice_t = &cval.slc
+	)
+
+	if cfh != nil {
+		ccfh = &cfh[0].cfh
+	}
+
+	defer wbwi.mutex.Unlock()
+	wbwi.mutex.Lock()
+	if ccfh != nil {
+		C.WriteBatchWithIndexMergeWithColumnFamily(cwbwi, ccfh, cckey, ccval)
+	} else {
+		C.WriteBatchWithIndexMerge(cwbwi, cckey, ccval)
+	}
+}
+
+// If the database contains a mapping for "key", erase it.  Else do nothing.
+func (wbwi *WriteBatchWithIndex) Delete(key []byte, cfh ...*ColumnFamilyHandle) {
+	if wbwi.closed {
+		return
+	}
+
+	ckey := newSliceFromBytes(key)
+	defer ckey.del()
+
+	var (
+		cwbwi *C.WriteBatchWithIndex_t = &wbwi.wbwi
+		ccfh *C.ColumnFamilyHandle_t
+		cckey *C.Slice_t = &ckey.slc
+	)
+
+	if cfh != nil {
+		ccfh = &cfh[0].cfh
+	}
+
+	defer wbwi.mutex.Unlock()
+	wbwi.mutex.Lock()
+	if ccfh != nil {
+		C.WriteBatchWithIndexDeleteWithColumnFamily(cwbwi, ccfh, cckey)
+	} else {
+		C.WriteBatchWithIndexDelete(cwbwi, cckey)
+	}
+}
+
+// Clear all updates buffered in this batch.
+func (wbwi *WriteBatchWithIndex) Clear() {
+	if wbwi.closed {
+		return
+	}
+
+	var (
+		cwbwi *C.WriteBatchWithIndex_t = &wbwi.wbwi
+	)
+
+	defer wbwi.mutex.Unlock()
+	wbwi.mutex.Lock()
+
+	C.WriteBatchWithIndexClear(cwbwi)
+}
+
+// Return a copy of the WriteBatch holding the updates in the batch,
+// which can be passed to DB.Write().
+func (wbwi *WriteBatchWithIndex) GetWriteBatch() (wbt *WriteBatch) {
+	if wbwi.closed {
+		return
+	}
+
+	var (
+		cwbwi *C.WriteBatchWithIndex_t = &wbwi.wbwi
+	)
+
+	defer wbwi.mutex.Unlock()
+	wbwi.mutex.Lock()
+
+	cwbt := C.WriteBatchWithIndexGetWriteBatch(cwbwi)
+	wbt = cwbt.toWriteBatch()
+	return
+}
+
+// Provides Read-Your-Own-Writes like functionality by creating a new
+// Iterator that will use the updates in the batch as a delta and
+// base as base.
+//
+// The returned iterator owns base, which must not be used or closed
+// afterwards. The returned iterator is closed when the batch is closed,
+// and becomes invalid if the batch is updated.
+// Returns nil and leaves base to the caller if the batch is not created
+// with overwriteKey.
+func (wbwi *WriteBatchWithIndex) NewIteratorWithBase(base *Iterator, cfh ...*ColumnFamilyHandle) (it *Iterator) {
+	if wbwi.closed || base.closed || !wbwi.overwriteKey {
+		return
+	}
+
+	var (
+		cwbwi *C.WriteBatchWithIndex_t = &wbwi.wbwi
+		ccfh *C.ColumnFamilyHandle_t
+		cit C.Iterator_t
+	)
+
+	if cfh != nil {
+		ccfh = &cfh[0].cfh
+	}
+
+	defer wbwi.mutex.Unlock()
+	wbwi.mutex.Lock()
+	defer base.mutex.Unlock()
+	base.mutex.Lock()
+
+	var cbase *C.Iterator_t = &base.it
+	if ccfh != nil {
+		cit = C.WriteBatchWithIndexNewIteratorWithBaseWithColumnFamily(cwbwi, ccfh, cbase)
+	} else {
+		cit = C.WriteBatchWithIndexNewIteratorWithBase(cwbwi, cbase)
+	}
+	// base is not taken over if no iterator is created
+	if nil == cit.rep {
+		return
+	}
+
+	// The underlying c iterator of base is owned by the new iterator
+	runtime.SetFinalizer(base, nil)
+	base.closed = true
+	base.db.removeFromItmap(base)
+	it = cit.toIterator(base.db)
+	it.wbwi = wbwi
+	wbwi.addToItmap(it)
+	return
+}
+
+// Similar to DB.Get() but will only read the key from this batch.
+// If the batch does not have enough data to resolve Merge operations,
+// a MergeInProgress status may be returned.
+func (wbwi *WriteBatchWithIndex) GetFromBatch(options *DBOptions, key []byte, cfh ...*ColumnFamilyHandle) (val []byte, stat *Status) {
+	if wbwi.closed {
+		stat = NewDBClosedStatus()
+		return
+	}
+
+	ckey := newSliceFromBytes(key)
+	defer ckey.del()
+	cval := newCString()
+
+	var (
+		cwbwi *C.WriteBatchWithIndex_t = &wbwi.wbwi
+		cdbopt *C.DBOptions_t = &options.dbopt
+		ccfh *C.ColumnFamilyHandle_t
+		cckey *C.Slice_t = &ckey.slc
+		ccval *C.String_t = &cval.str
+	)
+
+	if cfh != nil {
+		ccfh = &cfh[0].cfh
+	}
+
+	defer wbwi.mutex.Unlock()
+	wbwi.mutex.Lock()
+
+	var cstat C.Status_t
+	if ccfh != nil {
+		cstat = C.WriteBatchWithIndexGetFromBatchWithColumnFamily(cwbwi, ccfh, cdbopt, cckey, ccval)
+	} else {
+		cstat = C.WriteBatchWithIndexGetFromBatch(cwbwi, cdbopt, cckey, ccval)
+	}
+	stat = cstat.toStatus()
+	val = cval.goBytes(true)
+	return
+}
+
+// Similar to DB.Get() but will also read writes from this batch.
+//
+// This function will query both this batch and the DB and then merge
+// the results using the DB's merge operator (if the batch contains any
+// merge requests).
+//
+// Setting the snapshot of options will affect what is read from the DB
+// but will NOT change which keys are read from the batch (the keys in
+// this batch do not yet belong to any snapshot and will be fetched
+// regardless).
+func (wbwi *WriteBatchWithIndex) GetFromBatchAndDB(db *DB, options *ReadOptions, key []byte, cfh ...*ColumnFamilyHandle) (val []byte, stat *Status) {
+	if wbwi.closed || db.closed {
+		stat = NewDBClosedStatus()
+		return
+	}
+
+	ckey := newSliceFromBytes(key)
+	defer ckey.del()
+	cval := newCString()
+
+	var (
+		cwbwi *C.WriteBatchWithIndex_t = &wbwi.wbwi
+		cdb *C.DB_t = &db.db
+		cropt *C.ReadOptions_t = &options.ropt
+		ccfh *C.ColumnFamilyHandle_t
+		cckey *C.Slice_t = &ckey.slc
+		ccval *C.String_t = &cval.str
+	)
+
+	if cfh != nil {
+		ccfh = &cfh[0].cfh
+	}
+
+	defer wbwi.mutex.Unlock()
+	wbwi.mutex.Lock()
+
+	var cstat C.Status_t
+	if ccfh != nil {
+		cstat = C.WriteBatchWithIndexGetFromBatchAndDBWithColumnFamily(cwbwi, cdb, cropt, ccfh, cckey, ccval)
+	} else {
+		cstat = C.WriteBatchWithIndexGetFromBatchAndDB(cwbwi, cdb, cropt, cckey, ccval)
+	}
+	stat = cstat.toStatus()
+	val = cval.goBytes(true)
+	return
+}
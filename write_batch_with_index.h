@@ -0,0 +1,96 @@
+// Copyright (c) 2015, Dean ChaoJun Pan.  All rights reserved.
+// This source code is licensed under the BSD-style license found in the
+// LICENSE file in the root directory of this source tree.
+//
+// A WriteBatchWithIndex with a binary searchable index built for all the keys
+// inserted.
+
+#ifndef GO_ROCKSDB_INCLUDE_WRITE_BATCH_WITH_INDEX_H_
+#define GO_ROCKSDB_INCLUDE_WRITE_BATCH_WITH_INDEX_H_
+
+#include "types.h"
+#include "db.h"
+#include "comparator.h"
+#include "iterator.h"
+#include "write_batch.h"
+
+#ifdef __cplusplus
+extern "C" {
+#endif
+
+DEFINE_C_WRAP_STRUCT(WriteBatchWithIndex)
+DEFINE_C_WRAP_CONSTRUCTOR_DEC(WriteBatchWithIndex)
+DEFINE_C_WRAP_DESTRUCTOR_DEC(WriteBatchWithIndex)
+
+// backup_index_comparator: the backup comparator used to compare keys
+// within the same column family, if column family is not given in the
+// interface, or we can't find a column family from the column family handle
+// passed in, backup_index_comparator will be used for the column family.
+// reserved_bytes: reserved bytes in underlying WriteBatch
+// overwrite_key: if true, overwrite the key in the index when inserting
+//                the same key as previously, so iterator will never
+//                show two entries with the same key.
+WriteBatchWithIndex_t NewWriteBatchWithIndexTArgs(const Comparator_t* backup_index_comparator,
+                                                  size_t reserved_bytes,
+                                                  bool overwrite_key);
+
+void WriteBatchWithIndexPutWithColumnFamily(WriteBatchWithIndex_t* wbwi,
+                                            const ColumnFamilyHandle_t* column_family,
+                                            const Slice_t* key, const Slice_t* value);
+void WriteBatchWithIndexPut(WriteBatchWithIndex_t* wbwi,
+                            const Slice_t* key, const Slice_t* value);
+
+void WriteBatchWithIndexMergeWithColumnFamily(WriteBatchWithIndex_t* wbwi,
+                                              const ColumnFamilyHandle_t* column_family,
+                                              const Slice_t* key, const Slice_t* value);
+void WriteBatchWithIndexMerge(WriteBatchWithIndex_t* wbwi,
+                              const Slice_t* key, const Slice_t* value);
+
+void WriteBatchWithIndexDeleteWithColumnFamily(WriteBatchWithIndex_t* wbwi,
+                                               const ColumnFamilyHandle_t* column_family,
+                                               const Slice_t* key);
+void WriteBatchWithIndexDelete(WriteBatchWithIndex_t* wbwi, const Slice_t* key);
+
+void WriteBatchWithIndexClear(WriteBatchWithIndex_t* wbwi);
+
+// Return a copy of the WriteBatch holding the updates in the batch
+WriteBatch_t WriteBatchWithIndexGetWriteBatch(WriteBatchWithIndex_t* wbwi);
+
+// Provides Read-Your-Own-Writes like functionality by creating a new
+// Iterator that will use WBWIIterator as a delta and base_iterator as
+// base. The returned iterator owns base_iterator.
+Iterator_t WriteBatchWithIndexNewIteratorWithBaseWithColumnFamily(WriteBatchWithIndex_t* wbwi,
+                                                                  ColumnFamilyHandle_t* column_family,
+                                                                  Iterator_t* base_iterator);
+Iterator_t WriteBatchWithIndexNewIteratorWithBase(WriteBatchWithIndex_t* wbwi,
+                                                  Iterator_t* base_iterator);
+
+// Similar to DB::Get() but will only read the key from this batch.
+Status_t WriteBatchWithIndexGetFromBatchWithColumnFamily(WriteBatchWithIndex_t* wbwi,
+                                                         ColumnFamilyHandle_t* column_family,
+                                                         const DBOptions_t* options,
+                                                         const Slice_t* key,
+                                                         String_t* value);
+Status_t WriteBatchWithIndexGetFromBatch(WriteBatchWithIndex_t* wbwi,
+                                         const DBOptions_t* options,
+                                         const Slice_t* key,
+                                         String_t* value);
+
+// Similar to DB::Get() but will also read writes from this batch.
+Status_t WriteBatchWithIndexGetFromBatchAndDBWithColumnFamily(WriteBatchWithIndex_t* wbwi,
+                                                              DB_t* db,
+                                                              const ReadOptions_t* read_options,
+                                                              ColumnFamilyHandle_t* column_family,
+                                                              const Slice_t* key,
+                                                              String_t* value);
+Status_t WriteBatchWithIndexGetFromBatchAndDB(WriteBatchWithIndex_t* wbwi,
+                                              DB_t* db,
+                                              const ReadOptions_t* read_options,
+                                              const Slice_t* key,
+                                              String_t* value);
+
+#ifdef __cplusplus
+}  /* end extern "C" */
+#endif
+
+#endif  // GO_ROCKSDB_INCLUDE_WRITE_BATCH_WITH_INDEX_H_